@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/relay"
+)
+
+func main() {
+	relay.Run()
+}
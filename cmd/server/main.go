@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	_ "github.com/ArshiAbolghasemi/dom-cobb/docs"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/domcobb"
 )
@@ -12,5 +14,8 @@ import (
 // @host      localhost:8080
 // @BasePath  /api/v1
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(domcobb.Migrate(os.Args[2:], os.Stdout, os.Stderr))
+	}
 	domcobb.Run()
 }
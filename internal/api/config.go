@@ -0,0 +1,10 @@
+package api
+
+import "os"
+
+// GetTrustedActorHeader returns the header an auth proxy in front of the
+// server sets to the authenticated user, or an empty string when actors
+// are not taken from headers.
+func GetTrustedActorHeader() string {
+	return os.Getenv("AUTH_TRUSTED_ACTOR_HEADER")
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware tags every request with the X-Request-ID header,
+// generating one when the client sent none, and stores it in the request
+// context for audit log entries.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = logger.NewRequestID()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// ActorMiddleware stores who makes the request in its context: the
+// principal set by an authentication middleware under gin.AuthUserKey,
+// else the trusted actor header when configured, else AnonymousActor.
+func ActorMiddleware() gin.HandlerFunc {
+	header := GetTrustedActorHeader()
+	return func(c *gin.Context) {
+		actor := c.GetString(gin.AuthUserKey)
+		if actor == "" && header != "" {
+			actor = strings.TrimSpace(c.GetHeader(header))
+		}
+		if actor == "" {
+			actor = logger.AnonymousActor
+		}
+
+		c.Request = c.Request.WithContext(logger.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// AuditActorMiddleware rejects requests whose actor is longer than log
+// filters accept. Only routes that write audit entries use it, so a bad
+// actor header cannot take down reads.
+func AuditActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(logger.GetActor(c.Request.Context())) > logger.MaxActorLength {
+			RespondAPIError(c, BadRequestError("Invalid actor", fmt.Sprintf(
+				"Actor must be at most %d characters", logger.MaxActorLength)))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,126 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Middleware", func() {
+	var (
+		router    *gin.Engine
+		auth      []gin.HandlerFunc
+		actor     string
+		requestID string
+	)
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	BeforeEach(func() {
+		actor, requestID = "", ""
+		auth = nil
+		router = gin.New()
+	})
+
+	JustBeforeEach(func() {
+		router.Use(auth...)
+		router.Use(api.RequestIDMiddleware(), api.ActorMiddleware())
+		handler := func(c *gin.Context) {
+			actor = logger.GetActor(c.Request.Context())
+			requestID = logger.GetRequestID(c.Request.Context())
+		}
+		router.GET("/", handler)
+		router.POST("/", api.AuditActorMiddleware(), handler)
+	})
+
+	Describe("Request ID", func() {
+		It("should keep the request id sent by the client", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(api.RequestIDHeader, "req-1")
+
+			w := serve(req)
+
+			Expect(requestID).To(Equal("req-1"))
+			Expect(w.Header().Get(api.RequestIDHeader)).To(Equal("req-1"))
+		})
+
+		It("should generate a request id when the client sent none", func() {
+			w := serve(httptest.NewRequest(http.MethodGet, "/", nil))
+
+			Expect(requestID).To(HaveLen(32))
+			Expect(w.Header().Get(api.RequestIDHeader)).To(Equal(requestID))
+		})
+	})
+
+	Describe("Actor", func() {
+		It("should record anonymous requests", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Forwarded-User", "mallory")
+
+			serve(req)
+
+			Expect(actor).To(Equal(logger.AnonymousActor))
+		})
+
+		When("a trusted actor header is configured", func() {
+			BeforeEach(func() {
+				GinkgoT().Setenv("AUTH_TRUSTED_ACTOR_HEADER", "X-Forwarded-User")
+			})
+
+			It("should take the actor from the header", func() {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Forwarded-User", " alice ")
+
+				serve(req)
+
+				Expect(actor).To(Equal("alice"))
+			})
+
+			It("should reject an audited request by an actor longer than the filter accepts", func() {
+				req := httptest.NewRequest(http.MethodPost, "/", nil)
+				req.Header.Set("X-Forwarded-User", strings.Repeat("a", logger.MaxActorLength+1))
+
+				w := serve(req)
+
+				Expect(w.Code).To(Equal(http.StatusBadRequest))
+				Expect(actor).To(BeEmpty())
+			})
+
+			It("should serve other requests by an actor longer than the filter accepts", func() {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Forwarded-User", strings.Repeat("a", logger.MaxActorLength+1))
+
+				w := serve(req)
+
+				Expect(w.Code).To(Equal(http.StatusOK))
+				Expect(actor).To(HaveLen(logger.MaxActorLength + 1))
+			})
+
+			When("an authentication middleware runs first", func() {
+				BeforeEach(func() {
+					auth = []gin.HandlerFunc{gin.BasicAuth(gin.Accounts{"bob": "secret"})}
+				})
+
+				It("should prefer the authenticated principal", func() {
+					req := httptest.NewRequest(http.MethodGet, "/", nil)
+					req.SetBasicAuth("bob", "secret")
+					req.Header.Set("X-Forwarded-User", "alice")
+
+					serve(req)
+
+					Expect(actor).To(Equal("bob"))
+				})
+			})
+		})
+	})
+})
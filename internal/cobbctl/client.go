@@ -0,0 +1,204 @@
+package cobbctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+const defaultTimeout = 30 * time.Second
+
+// APIError is an api.ErrorResponse returned by the server along with its
+// HTTP status code.
+type APIError struct {
+	StatusCode int
+	api.ErrorResponse
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s", e.ErrorResponse.Error, e.Message)
+	}
+	return e.ErrorResponse.Error
+}
+
+// Client is a minimal REST client for the flag management endpoints.
+type Client struct {
+	baseURL    string
+	context    *Context
+	httpClient *http.Client
+}
+
+func NewClient(ctx *Context) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(ctx.Server, "/"),
+		context:    ctx,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// do sends the request and decodes the data field of a successful response
+// into out. It returns the response message, which for some no-op requests
+// is the error field of a 200 response.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) (string, error) {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return "", err
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.context.Token != "":
+		req.Header.Set("Authorization", "Bearer "+c.context.Token)
+	case c.context.Username != "":
+		req.SetBasicAuth(c.context.Username, c.context.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Data    json.RawMessage `json:"data"`
+		Message string          `json:"message"`
+		Error   string          `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to decode response with status %d: %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		apiErr.ErrorResponse.Error = envelope.Error
+		apiErr.Message = envelope.Message
+		if apiErr.ErrorResponse.Error == "" {
+			apiErr.ErrorResponse.Error = http.StatusText(resp.StatusCode)
+		}
+		return "", apiErr
+	}
+
+	if out != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, out); err != nil {
+			return "", fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	if envelope.Error != "" {
+		return envelope.Error, nil
+	}
+	return envelope.Message, nil
+}
+
+func (c *Client) CreateFlag(ctx context.Context, req *flags.CreateFeatureFlagRequest) (*flags.FeatureFlagData, error) {
+	var data flags.FeatureFlagData
+	if _, err := c.do(ctx, http.MethodPost, "/api/v1/flags", req, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) GetFlag(ctx context.Context, id uint) (*flags.FeatureFlagData, error) {
+	var data flags.FeatureFlagData
+	if _, err := c.do(ctx, http.MethodGet, flagPath(id, ""), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) ListFlags(ctx context.Context, page, size uint) (*flags.ListFeatureFlagsData, error) {
+	query := url.Values{}
+	query.Set("page", strconv.FormatUint(uint64(page), 10))
+	query.Set("size", strconv.FormatUint(uint64(size), 10))
+
+	var data flags.ListFeatureFlagsData
+	if _, err := c.do(ctx, http.MethodGet, "/api/v1/flags?"+query.Encode(), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) ToggleFlag(ctx context.Context, id uint, req *flags.UpdateFeatureFlagRequest) (string, error) {
+	return c.do(ctx, http.MethodPatch, flagPath(id, ""), req, nil)
+}
+
+func (c *Client) UpdateFlagDependencies(
+	ctx context.Context,
+	id uint,
+	req *flags.UpdateFeatureFlagDependenciesRequest,
+) (
+	string,
+	error,
+) {
+	return c.do(ctx, http.MethodPut, flagPath(id, "/dependencies"), req, nil)
+}
+
+func (c *Client) ExplainFlag(
+	ctx context.Context,
+	id uint,
+	req *flags.EvaluateFeatureFlagRequest,
+) (
+	*flags.ExplainFeatureFlagData,
+	error,
+) {
+	var data flags.ExplainFeatureFlagData
+	if _, err := c.do(ctx, http.MethodPost, flagPath(id, "/explain"), req, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) GetFlagLogs(ctx context.Context, id uint, page, size uint) (*flags.GetFeatureFlagLogsData, error) {
+	query := url.Values{}
+	query.Set("page", strconv.FormatUint(uint64(page), 10))
+	query.Set("size", strconv.FormatUint(uint64(size), 10))
+
+	var data flags.GetFeatureFlagLogsData
+	if _, err := c.do(ctx, http.MethodGet, flagPath(id, "/logs")+"?"+query.Encode(), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) VerifyLogs(ctx context.Context) (*logger.ChainVerification, error) {
+	var data logger.ChainVerification
+	if _, err := c.do(ctx, http.MethodGet, "/api/v1/logs/verify", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (c *Client) GetConfig(ctx context.Context) (*evaluation.Config, error) {
+	var data evaluation.Config
+	if _, err := c.do(ctx, http.MethodGet, "/api/v1/config", nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func flagPath(id uint, suffix string) string {
+	return "/api/v1/flags/" + strconv.FormatUint(uint64(id), 10) + suffix
+}
@@ -0,0 +1,654 @@
+package cobbctl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+const usage = `cobbctl manages dom-cobb feature flags through the REST API.
+
+Usage:
+  cobbctl [global flags] <command> [arguments]
+
+Commands:
+  create <name> [--active] [--depends-on 1,2]   create a feature flag
+  get <id>                                      show a feature flag
+  list [--page n] [--size n] [--all]            list feature flags
+  toggle <id> on|off --reason text              activate or deactivate a flag
+  deps <id> [set|add|remove <ids>] --reason text
+                                                show or edit a flag's dependencies
+  explain <id> --key key                        trace how a flag evaluates for a targeting key
+  logs <id> [-n 10] [-f] [--interval 2s]        show the latest logs of a flag
+  audit verify                                  verify the audit log hash chain
+  tree [<id>] [--reverse]                       render the dependency tree
+  context list|current|use|set|delete           manage named server contexts
+
+Global flags:
+  -o, --output table|json|yaml   output format (default table)
+  --context name                 context to use instead of the current one
+  --server url                   server URL, overrides the context and COBBCTL_SERVER
+  --config path                  config file, defaults to COBBCTL_CONFIG or ~/.config/cobbctl/config.yaml
+
+Exit codes:
+  0 success, 1 other error, 2 usage error, 3 bad request,
+  4 not found, 5 conflict, 6 server error, 7 audit log chain broken
+`
+
+const (
+	maxPageSize           = 20
+	defaultFollowInterval = 2 * time.Second
+)
+
+type App struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	configPath  string
+	contextName string
+	server      string
+	output      string
+}
+
+// Run executes cobbctl with the given arguments and returns the process exit
+// code.
+func Run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	app := &App{stdout: stdout, stderr: stderr}
+
+	err := app.run(ctx, args)
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "Error: "+err.Error())
+		var usageErr *usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintln(stderr, "Run 'cobbctl help' for usage.")
+		}
+	}
+	return ExitCode(err)
+}
+
+func (a *App) run(ctx context.Context, args []string) error {
+	fs := a.flagSet("cobbctl")
+	rest, err := a.parse(fs, args, true)
+	if err != nil {
+		return err
+	}
+	if len(rest) == 0 {
+		return &usageError{message: "no command given"}
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"create":  a.create,
+		"get":     a.get,
+		"list":    a.list,
+		"toggle":  a.toggle,
+		"deps":    a.deps,
+		"explain": a.explain,
+		"logs":    a.logs,
+		"audit":   a.audit,
+		"tree":    a.tree,
+		"context": a.context,
+	}
+	name := rest[0]
+	if name == "help" {
+		return flag.ErrHelp
+	}
+	command, exists := commands[name]
+	if !exists {
+		return &usageError{message: fmt.Sprintf("unknown command %q", name)}
+	}
+	return command(ctx, rest[1:])
+}
+
+// flagSet returns a flag set with the global flags registered so they are
+// accepted before and after the command name.
+func (a *App) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if a.output == "" {
+		a.output = OutputTable
+	}
+	fs.StringVar(&a.output, "output", a.output, "")
+	fs.StringVar(&a.output, "o", a.output, "")
+	fs.StringVar(&a.contextName, "context", a.contextName, "")
+	fs.StringVar(&a.server, "server", a.server, "")
+	fs.StringVar(&a.configPath, "config", a.configPath, "")
+	return fs
+}
+
+// parse parses flags wherever they appear among the positional arguments.
+// With stopAtFirst the remaining arguments after the first positional one
+// are returned untouched, which is how the command name is split off.
+func (a *App) parse(fs *flag.FlagSet, args []string, stopAtFirst bool) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{message: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if stopAtFirst {
+			return args, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func (a *App) loadConfig() (*Config, string, error) {
+	path := a.configPath
+	if path == "" {
+		var err error
+		path, err = GetConfigPath()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return config, path, nil
+}
+
+func (a *App) client() (*Client, error) {
+	config, _, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := config.Resolve(a.contextName, a.server)
+	if err != nil {
+		return nil, &usageError{message: err.Error()}
+	}
+	return NewClient(resolved), nil
+}
+
+func (a *App) printer() (*Printer, error) {
+	return NewPrinter(a.stdout, a.output)
+}
+
+// setup parses a command's flags, checks the positional argument count and
+// returns the client and printer the command needs.
+func (a *App) setup(fs *flag.FlagSet, args []string, minArgs, maxArgs int) ([]string, *Client, *Printer, error) {
+	positional, err := a.parse(fs, args, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(positional) < minArgs || (maxArgs >= 0 && len(positional) > maxArgs) {
+		return nil, nil, nil, &usageError{message: fmt.Sprintf("wrong number of arguments for %s", fs.Name())}
+	}
+	printer, err := a.printer()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	client, err := a.client()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return positional, client, printer, nil
+}
+
+func (a *App) create(ctx context.Context, args []string) error {
+	fs := a.flagSet("create")
+	active := fs.Bool("active", false, "")
+	dependsOn := fs.String("depends-on", "", "")
+	positional, client, printer, err := a.setup(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	dependencies, err := parseIds(*dependsOn)
+	if err != nil {
+		return err
+	}
+
+	flag, err := client.CreateFlag(ctx, &flags.CreateFeatureFlagRequest{
+		Name:                      positional[0],
+		IsActive:                  *active,
+		FeatureFlagIDDependencies: dependencies,
+	})
+	if err != nil {
+		return err
+	}
+	return printer.Flags([]*flags.FeatureFlagData{flag})
+}
+
+func (a *App) get(ctx context.Context, args []string) error {
+	positional, client, printer, err := a.setup(a.flagSet("get"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseId(positional[0])
+	if err != nil {
+		return err
+	}
+
+	flag, err := client.GetFlag(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printer.Flags([]*flags.FeatureFlagData{flag})
+}
+
+func (a *App) list(ctx context.Context, args []string) error {
+	fs := a.flagSet("list")
+	page := fs.Uint("page", 1, "")
+	size := fs.Uint("size", maxPageSize, "")
+	all := fs.Bool("all", false, "")
+	_, client, printer, err := a.setup(fs, args, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	if !*all {
+		data, err := client.ListFlags(ctx, *page, *size)
+		if err != nil {
+			return err
+		}
+		return printer.FlagList(data)
+	}
+
+	var list []*flags.FeatureFlagData
+	for page := uint(1); ; page++ {
+		data, err := client.ListFlags(ctx, page, maxPageSize)
+		if err != nil {
+			return err
+		}
+		list = append(list, data.Flags...)
+		if page >= data.TotalPages {
+			break
+		}
+	}
+	return printer.Flags(list)
+}
+
+func (a *App) toggle(ctx context.Context, args []string) error {
+	fs := a.flagSet("toggle")
+	reason := fs.String("reason", "", "")
+	positional, client, printer, err := a.setup(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	id, err := parseId(positional[0])
+	if err != nil {
+		return err
+	}
+	var active bool
+	switch positional[1] {
+	case "on":
+		active = true
+	case "off":
+		active = false
+	default:
+		return &usageError{message: fmt.Sprintf("state must be on or off, got %q", positional[1])}
+	}
+	if *reason == "" {
+		return &usageError{message: "--reason is required"}
+	}
+
+	message, err := client.ToggleFlag(ctx, id, &flags.UpdateFeatureFlagRequest{IsActive: active, Reason: *reason})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(a.stderr, message)
+
+	flag, err := client.GetFlag(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printer.Flags([]*flags.FeatureFlagData{flag})
+}
+
+func (a *App) deps(ctx context.Context, args []string) error {
+	fs := a.flagSet("deps")
+	reason := fs.String("reason", "", "")
+	positional, client, printer, err := a.setup(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	id, err := parseId(positional[0])
+	if err != nil {
+		return err
+	}
+
+	flag, err := client.GetFlag(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 1 {
+		return printer.Flags([]*flags.FeatureFlagData{flag})
+	}
+
+	ids, err := parseIds(strings.Join(positional[2:], ","))
+	if err != nil {
+		return err
+	}
+	var dependencies []uint
+	switch positional[1] {
+	case "set":
+		dependencies = ids
+	case "add":
+		dependencies = utils.Unique(append(flag.Dependencies, ids...))
+	case "remove":
+		removed := make(map[uint]bool, len(ids))
+		for _, id := range ids {
+			removed[id] = true
+		}
+		dependencies = []uint{}
+		for _, dependency := range flag.Dependencies {
+			if !removed[dependency] {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	default:
+		return &usageError{message: fmt.Sprintf("unknown deps action %q, use set, add or remove", positional[1])}
+	}
+	if *reason == "" {
+		return &usageError{message: "--reason is required"}
+	}
+
+	message, err := client.UpdateFlagDependencies(ctx, id, &flags.UpdateFeatureFlagDependenciesRequest{
+		Dependencies: dependencies,
+		Reason:       *reason,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(a.stderr, message)
+
+	flag, err = client.GetFlag(ctx, id)
+	if err != nil {
+		return err
+	}
+	return printer.Flags([]*flags.FeatureFlagData{flag})
+}
+
+func (a *App) explain(ctx context.Context, args []string) error {
+	fs := a.flagSet("explain")
+	key := fs.String("key", "", "")
+	positional, client, printer, err := a.setup(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseId(positional[0])
+	if err != nil {
+		return err
+	}
+	if *key == "" {
+		return &usageError{message: "--key is required"}
+	}
+
+	data, err := client.ExplainFlag(ctx, id, &flags.EvaluateFeatureFlagRequest{
+		Context: evaluation.Context{TargetingKey: *key},
+	})
+	if err != nil {
+		return err
+	}
+	return printer.Explanation(data)
+}
+
+func (a *App) logs(ctx context.Context, args []string) error {
+	fs := a.flagSet("logs")
+	count := fs.Uint("n", 10, "")
+	follow := fs.Bool("f", false, "")
+	fs.BoolVar(follow, "follow", false, "")
+	interval := fs.Duration("interval", defaultFollowInterval, "")
+	positional, client, printer, err := a.setup(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	id, err := parseId(positional[0])
+	if err != nil {
+		return err
+	}
+	if *count < 1 || *count > maxPageSize {
+		return &usageError{message: fmt.Sprintf("-n must be between 1 and %d", maxPageSize)}
+	}
+
+	data, err := client.GetFlagLogs(ctx, id, 1, *count)
+	if err != nil {
+		return err
+	}
+	// Logs come newest first; print them oldest first like tail does.
+	entries := reverseLogs(data.Logs)
+	if err := printer.Logs(entries, true); err != nil {
+		return err
+	}
+	if !*follow {
+		return nil
+	}
+
+	seen := newLogCursor(entries)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		data, err := client.GetFlagLogs(ctx, id, 1, maxPageSize)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := printer.Logs(seen.next(reverseLogs(data.Logs)), false); err != nil {
+			return err
+		}
+	}
+}
+
+func (a *App) audit(ctx context.Context, args []string) error {
+	fs := a.flagSet("audit")
+	positional, client, printer, err := a.setup(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if positional[0] != "verify" {
+		return &usageError{message: fmt.Sprintf("unknown audit action %q", positional[0])}
+	}
+
+	verification, err := client.VerifyLogs(ctx)
+	if err != nil {
+		return err
+	}
+	if err := printer.ChainVerification(verification); err != nil {
+		return err
+	}
+	if verification.Break != nil {
+		return &chainBrokenError{chainBreak: verification.Break}
+	}
+	return nil
+}
+
+func (a *App) tree(ctx context.Context, args []string) error {
+	fs := a.flagSet("tree")
+	reverse := fs.Bool("reverse", false, "")
+	positional, client, printer, err := a.setup(fs, args, 0, 1)
+	if err != nil {
+		return err
+	}
+	var rootId uint
+	if len(positional) == 1 {
+		if rootId, err = parseId(positional[0]); err != nil {
+			return err
+		}
+	}
+
+	config, err := client.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	roots, err := BuildTree(config, rootId, *reverse)
+	if err != nil {
+		return err
+	}
+	return printer.Tree(roots)
+}
+
+func (a *App) context(_ context.Context, args []string) error {
+	// context set takes its URL from the global --server flag.
+	fs := a.flagSet("context")
+	token := fs.String("token", "", "")
+	username := fs.String("username", "", "")
+	password := fs.String("password", "", "")
+	positional, err := a.parse(fs, args, false)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return &usageError{message: "context needs an action: list, current, use, set or delete"}
+	}
+	printer, err := a.printer()
+	if err != nil {
+		return err
+	}
+	config, path, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	action, names := positional[0], positional[1:]
+	needName := func() error {
+		if len(names) != 1 {
+			return &usageError{message: fmt.Sprintf("context %s needs exactly one name", action)}
+		}
+		return nil
+	}
+	switch action {
+	case "list":
+		return printer.Contexts(config)
+	case "current":
+		if config.CurrentContext == "" {
+			return errors.New("no current context is set")
+		}
+		_, err := fmt.Fprintln(a.stdout, config.CurrentContext)
+		return err
+	case "use":
+		if err := needName(); err != nil {
+			return err
+		}
+		if config.GetContext(names[0]) == nil {
+			return &usageError{message: fmt.Sprintf("context %q is not defined", names[0])}
+		}
+		config.CurrentContext = names[0]
+	case "set":
+		if err := needName(); err != nil {
+			return err
+		}
+		if a.server == "" {
+			return &usageError{message: "--server is required"}
+		}
+		config.SetContext(&Context{
+			Name:     names[0],
+			Server:   a.server,
+			Token:    *token,
+			Username: *username,
+			Password: *password,
+		})
+	case "delete":
+		if err := needName(); err != nil {
+			return err
+		}
+		if !config.DeleteContext(names[0]) {
+			return &usageError{message: fmt.Sprintf("context %q is not defined", names[0])}
+		}
+	default:
+		return &usageError{message: fmt.Sprintf("unknown context action %q", action)}
+	}
+	return config.Save(path)
+}
+
+// logCursor remembers the newest timestamp printed so far, and which entries
+// at that timestamp were already printed, to print each log only once while
+// following.
+type logCursor struct {
+	last time.Time
+	keys map[string]bool
+}
+
+func newLogCursor(entries []*logger.LogEntry) *logCursor {
+	cursor := &logCursor{keys: map[string]bool{}}
+	cursor.next(entries)
+	return cursor
+}
+
+func (c *logCursor) next(entries []*logger.LogEntry) []*logger.LogEntry {
+	var fresh []*logger.LogEntry
+	for _, entry := range entries {
+		key := logKey(entry)
+		switch {
+		case entry.Timestamp.Before(c.last):
+			continue
+		case entry.Timestamp.Equal(c.last):
+			if c.keys[key] {
+				continue
+			}
+		default:
+			c.last = entry.Timestamp
+			c.keys = map[string]bool{}
+		}
+		c.keys[key] = true
+		fresh = append(fresh, entry)
+	}
+	return fresh
+}
+
+func logKey(entry *logger.LogEntry) string {
+	key, _ := json.Marshal(entry)
+	return string(key)
+}
+
+func reverseLogs(entries []*logger.LogEntry) []*logger.LogEntry {
+	reversed := make([]*logger.LogEntry, len(entries))
+	for i, entry := range entries {
+		reversed[len(entries)-1-i] = entry
+	}
+	return reversed
+}
+
+func parseId(value string) (uint, error) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, &usageError{message: fmt.Sprintf("invalid flag id %q", value)}
+	}
+	return uint(id), nil
+}
+
+// parseIds parses a comma separated list of flag ids.
+func parseIds(value string) ([]uint, error) {
+	ids := []uint{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := parseId(part)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,130 @@
+package cobbctl
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// Context is a named dom-cobb server together with the credentials used to
+// reach it.
+type Context struct {
+	Name     string `yaml:"name" json:"name"`
+	Server   string `yaml:"server" json:"server"`
+	Token    string `yaml:"token,omitempty" json:"-"`
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+	Password string `yaml:"password,omitempty" json:"-"`
+}
+
+type Config struct {
+	CurrentContext string     `yaml:"current_context"`
+	Contexts       []*Context `yaml:"contexts"`
+}
+
+// GetConfigPath returns COBBCTL_CONFIG when set and
+// $XDG_CONFIG_HOME/cobbctl/config.yaml otherwise.
+func GetConfigPath() (string, error) {
+	if path, exists := os.LookupEnv("COBBCTL_CONFIG"); exists {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cobbctl", "config.yaml"), nil
+}
+
+// LoadConfig reads the config file at path. A missing file yields an empty
+// config.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Save writes the config with owner-only permissions since it holds
+// credentials.
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (c *Config) GetContext(name string) *Context {
+	for _, ctx := range c.Contexts {
+		if ctx.Name == name {
+			return ctx
+		}
+	}
+	return nil
+}
+
+func (c *Config) SetContext(ctx *Context) {
+	if existing := c.GetContext(ctx.Name); existing != nil {
+		*existing = *ctx
+	} else {
+		c.Contexts = append(c.Contexts, ctx)
+	}
+	if c.CurrentContext == "" {
+		c.CurrentContext = ctx.Name
+	}
+}
+
+func (c *Config) DeleteContext(name string) bool {
+	for i, ctx := range c.Contexts {
+		if ctx.Name == name {
+			c.Contexts = append(c.Contexts[:i], c.Contexts[i+1:]...)
+			if c.CurrentContext == name {
+				c.CurrentContext = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve picks the context to talk to. An explicit server URL wins, then
+// COBBCTL_SERVER, then the named or current context, then localhost.
+func (c *Config) Resolve(name, server string) (*Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+
+	ctx := &Context{Name: name, Server: defaultServer}
+	if name != "" {
+		found := c.GetContext(name)
+		if found == nil {
+			return nil, fmt.Errorf("context %q is not defined", name)
+		}
+		*ctx = *found
+	}
+
+	if env, exists := os.LookupEnv("COBBCTL_SERVER"); exists && server == "" {
+		server = env
+	}
+	if server != "" {
+		ctx.Server = server
+	}
+	return ctx, nil
+}
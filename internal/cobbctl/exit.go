@@ -0,0 +1,72 @@
+package cobbctl
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+)
+
+// Exit codes returned by cobbctl. Server errors map onto them through the
+// status code of the api.ErrorResponse so scripts can branch on the cause.
+const (
+	ExitOK         = 0
+	ExitError      = 1
+	ExitUsage      = 2
+	ExitBadRequest = 3
+	ExitNotFound   = 4
+	ExitConflict   = 5
+	ExitServer     = 6
+	// ExitTampered means the audit log hash chain did not verify.
+	ExitTampered = 7
+)
+
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+type chainBrokenError struct {
+	chainBreak *logger.ChainBreak
+}
+
+func (e *chainBrokenError) Error() string {
+	return fmt.Sprintf("audit log chain is broken at sequence %d: %s", e.chainBreak.Sequence, e.chainBreak.Reason)
+}
+
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return ExitUsage
+	}
+
+	var chainErr *chainBrokenError
+	if errors.As(err, &chainErr) {
+		return ExitTampered
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return ExitError
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound:
+		return ExitNotFound
+	case apiErr.StatusCode == http.StatusConflict:
+		return ExitConflict
+	case apiErr.StatusCode >= http.StatusInternalServerError:
+		return ExitServer
+	case apiErr.StatusCode >= http.StatusBadRequest:
+		return ExitBadRequest
+	default:
+		return ExitError
+	}
+}
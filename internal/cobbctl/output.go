@@ -0,0 +1,203 @@
+package cobbctl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+type Printer struct {
+	w      io.Writer
+	format string
+}
+
+func NewPrinter(w io.Writer, format string) (*Printer, error) {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML:
+		return &Printer{w: w, format: format}, nil
+	default:
+		return nil, &usageError{message: fmt.Sprintf("unknown output format %q, use table, json or yaml", format)}
+	}
+}
+
+// print writes v as JSON or YAML, or calls table for the table format.
+// YAML goes through JSON first so both formats share the json tags.
+func (p *Printer) print(v any, table func(w *tabwriter.Writer)) error {
+	switch p.format {
+	case OutputJSON:
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case OutputYAML:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var generic any
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return err
+		}
+		return yaml.NewEncoder(p.w).Encode(generic)
+	default:
+		w := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+}
+
+func (p *Printer) Flags(list []*flags.FeatureFlagData) error {
+	return p.print(list, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tACTIVE\tDEPENDENCIES\tDEPENDENTS")
+		for _, flag := range list {
+			fmt.Fprintf(w, "%d\t%s\t%t\t%s\t%s\n",
+				flag.ID, flag.Name, flag.Active, formatIds(flag.Dependencies), formatIds(flag.Dependents))
+		}
+	})
+}
+
+func (p *Printer) FlagList(data *flags.ListFeatureFlagsData) error {
+	if p.format != OutputTable {
+		return p.print(data, nil)
+	}
+	if err := p.Flags(data.Flags); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(p.w, "\npage %d of %d, %d flags\n", data.Page, data.TotalPages, data.Total)
+	return err
+}
+
+// Logs prints log entries. With header false the table header is skipped
+// and JSON entries are written one per line, which suits following a log.
+func (p *Printer) Logs(entries []*logger.LogEntry, header bool) error {
+	if !header && p.format == OutputJSON {
+		encoder := json.NewEncoder(p.w)
+		for _, entry := range entries {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if !header && p.format == OutputYAML && len(entries) > 0 {
+		if _, err := fmt.Fprintln(p.w, "---"); err != nil {
+			return err
+		}
+	}
+
+	return p.print(entries, func(w *tabwriter.Writer) {
+		if header {
+			fmt.Fprintln(w, "TIMESTAMP\tACTION\tFLAG\tREASON\tMESSAGE\tMETADATA")
+		}
+		for _, entry := range entries {
+			metadata, _ := json.Marshal(entry.Metadata)
+			if len(entry.Metadata) == 0 {
+				metadata = nil
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n",
+				entry.Timestamp.Local().Format(time.RFC3339),
+				entry.Action,
+				entry.EntityID,
+				entry.Reason,
+				entry.Message,
+				metadata,
+			)
+		}
+	})
+}
+
+// Explanation prints the result of a flag evaluation and the steps that led
+// to it, nested by prerequisite depth.
+func (p *Printer) Explanation(data *flags.ExplainFeatureFlagData) error {
+	return p.print(data, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tVALUE\tVARIANT\tREASON")
+		fmt.Fprintf(w, "%d\t%s\t%t\t%s\t%s\n", data.ID, data.Name, data.Value, data.Variant, data.Reason)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FLAG\tCHECK\tMATCHED\tDETAIL")
+		for _, step := range data.Trace {
+			fmt.Fprintf(w, "%s%d\t%s\t%t\t%s\n",
+				strings.Repeat("  ", step.Depth), step.FlagID, step.Check, step.Matched, step.Detail)
+		}
+	})
+}
+
+func (p *Printer) ChainVerification(verification *logger.ChainVerification) error {
+	return p.print(verification, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "VALID\tENTRIES\tFIRST\tLAST\tLAST HASH")
+		fmt.Fprintf(w, "%t\t%d\t%d\t%d\t%s\n",
+			verification.Valid,
+			verification.Entries,
+			verification.FirstSequence,
+			verification.LastSequence,
+			verification.LastHash,
+		)
+	})
+}
+
+func (p *Printer) Contexts(config *Config) error {
+	type contextData struct {
+		*Context
+		Current bool `json:"current"`
+	}
+	list := make([]*contextData, 0, len(config.Contexts))
+	for _, ctx := range config.Contexts {
+		list = append(list, &contextData{Context: ctx, Current: ctx.Name == config.CurrentContext})
+	}
+
+	return p.print(list, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "CURRENT\tNAME\tSERVER\tAUTH")
+		for _, ctx := range list {
+			current := ""
+			if ctx.Current {
+				current = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", current, ctx.Name, ctx.Server, authKind(ctx.Context))
+		}
+	})
+}
+
+func (p *Printer) Tree(roots []*TreeNode) error {
+	if p.format != OutputTable {
+		return p.print(roots, nil)
+	}
+	for _, root := range roots {
+		if _, err := io.WriteString(p.w, RenderTree(root)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func authKind(ctx *Context) string {
+	switch {
+	case ctx.Token != "":
+		return "token"
+	case ctx.Username != "":
+		return "basic"
+	default:
+		return "none"
+	}
+}
+
+func formatIds(ids []uint) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprint(id))
+	}
+	return strings.Join(parts, ",")
+}
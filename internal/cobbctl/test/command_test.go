@@ -0,0 +1,344 @@
+package cobbctl_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/cobbctl"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type recordedRequest struct {
+	Method        string
+	Path          string
+	Authorization string
+	Body          map[string]any
+}
+
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []*recordedRequest
+}
+
+func newFakeServer() *fakeServer {
+	fake := &fakeServer{}
+	flagsById := map[string]*flags.FeatureFlagData{
+		"1": {ID: 1, Name: "checkout", Active: true, Dependencies: []uint{}, Dependents: []uint{2}},
+		"2": {ID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}, Dependents: []uint{}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/flags/{id}", func(w http.ResponseWriter, r *http.Request) {
+		flag, exists := flagsById[r.PathValue("id")]
+		if !exists {
+			respond(w, http.StatusNotFound, api.ErrorResponse{Error: "Invalid flag id"})
+			return
+		}
+		respond(w, http.StatusOK, api.SuccessResponse{Data: flag})
+	})
+	mux.HandleFunc("PATCH /api/v1/flags/{id}", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.ErrorResponse{Error: "Flag is already active"})
+	})
+	mux.HandleFunc("PUT /api/v1/flags/{id}/dependencies", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.SuccessResponse{Message: "Feature flag dependencies are updated successfully"})
+	})
+	mux.HandleFunc("POST /api/v1/flags", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusConflict, api.ErrorResponse{
+			Error:   "Feature flag already exists",
+			Message: "A feature flag with this name already exists",
+		})
+	})
+	mux.HandleFunc("GET /api/v1/flags", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.SuccessResponse{Data: flags.ListFeatureFlagsData{
+			Flags:              []*flags.FeatureFlagData{flagsById["1"], flagsById["2"]},
+			PaginationResponse: api.PaginationResponse{Page: 1, Size: 20, Total: 2, TotalPages: 1},
+		}})
+	})
+	mux.HandleFunc("POST /api/v1/flags/{id}/explain", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.SuccessResponse{Data: flags.ExplainFeatureFlagData{
+			EvaluateFeatureFlagData: flags.EvaluateFeatureFlagData{
+				ID:     2,
+				Name:   "new-cart",
+				Result: evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonPrerequisiteFailed},
+			},
+			Trace: []*evaluation.TraceStep{
+				{FlagID: 2, Depth: 0, Check: evaluation.CheckActive, Matched: true, Detail: `"new-cart" is active`},
+				{FlagID: 1, Depth: 1, Check: evaluation.CheckDenyList, Matched: true, Detail: `"user-2" is on the deny list`},
+				{FlagID: 2, Depth: 0, Check: evaluation.CheckDecision, Matched: false, Detail: `"new-cart" evaluates to off`},
+			},
+		}})
+	})
+	mux.HandleFunc("GET /api/v1/flags/{id}/logs", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.SuccessResponse{Data: flags.GetFeatureFlagLogsData{
+			Logs: []*logger.LogEntry{
+				{Message: "second", Timestamp: time.Unix(200, 0)},
+				{Message: "first", Timestamp: time.Unix(100, 0)},
+			},
+		}})
+	})
+	mux.HandleFunc("GET /api/v1/logs/verify", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.SuccessResponse{Data: logger.ChainVerification{
+			Entries:       4,
+			FirstSequence: 1,
+			LastSequence:  4,
+			LastHash:      "abc",
+			Break:         &logger.ChainBreak{Sequence: 5, Reason: "content does not match its hash"},
+		}})
+	})
+	mux.HandleFunc("GET /api/v1/config", func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, api.SuccessResponse{Data: evaluation.Config{Flags: []*evaluation.Flag{
+			{ID: 1, Name: "checkout", Active: true, Dependencies: []uint{}},
+			{ID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}},
+			{ID: 3, Name: "wallet", Active: false, Dependencies: []uint{1, 2}},
+		}}})
+	})
+
+	fake.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded := &recordedRequest{
+			Method:        r.Method,
+			Path:          r.URL.Path,
+			Authorization: r.Header.Get("Authorization"),
+		}
+		if raw, _ := io.ReadAll(r.Body); len(raw) > 0 {
+			Expect(json.Unmarshal(raw, &recorded.Body)).To(Succeed())
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, recorded)
+		fake.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	return fake
+}
+
+func (f *fakeServer) lastRequest() *recordedRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.requests) - 1; i >= 0; i-- {
+		if f.requests[i].Method != http.MethodGet {
+			return f.requests[i]
+		}
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+func respond(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+var _ = Describe("Cobbctl", func() {
+	var (
+		fake       *fakeServer
+		configPath string
+		stdout     *bytes.Buffer
+		stderr     *bytes.Buffer
+	)
+
+	run := func(args ...string) int {
+		stdout.Reset()
+		stderr.Reset()
+		args = append([]string{"--config", configPath}, args...)
+		return cobbctl.Run(context.Background(), args, stdout, stderr)
+	}
+
+	BeforeEach(func() {
+		fake = newFakeServer()
+		configPath = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+		Expect(run("context", "set", "test", "--server", fake.URL, "--token", "secret")).To(Equal(cobbctl.ExitOK))
+	})
+
+	AfterEach(func() {
+		fake.Close()
+	})
+
+	Describe("Exit codes", func() {
+		It("should map api errors onto exit codes", func() {
+			Expect(run("get", "7")).To(Equal(cobbctl.ExitNotFound))
+			Expect(stderr.String()).To(ContainSubstring("Invalid flag id"))
+
+			Expect(run("create", "checkout")).To(Equal(cobbctl.ExitConflict))
+			Expect(stderr.String()).To(ContainSubstring("A feature flag with this name already exists"))
+		})
+
+		It("should return the usage exit code for invalid arguments", func() {
+			Expect(run("toggle", "1", "on")).To(Equal(cobbctl.ExitUsage))
+			Expect(run("toggle", "1", "maybe", "--reason", "x")).To(Equal(cobbctl.ExitUsage))
+			Expect(run("get", "abc")).To(Equal(cobbctl.ExitUsage))
+			Expect(run("unknown")).To(Equal(cobbctl.ExitUsage))
+			Expect(run("get", "1", "-o", "xml")).To(Equal(cobbctl.ExitUsage))
+		})
+
+		It("should map server and bad request statuses", func() {
+			Expect(cobbctl.ExitCode(&cobbctl.APIError{StatusCode: http.StatusBadRequest})).To(Equal(cobbctl.ExitBadRequest))
+			Expect(cobbctl.ExitCode(&cobbctl.APIError{StatusCode: http.StatusBadGateway})).To(Equal(cobbctl.ExitServer))
+			Expect(cobbctl.ExitCode(io.EOF)).To(Equal(cobbctl.ExitError))
+		})
+	})
+
+	Describe("Get", func() {
+		It("should print a table and send the context token", func() {
+			Expect(run("get", "2")).To(Equal(cobbctl.ExitOK))
+
+			Expect(stdout.String()).To(ContainSubstring("NAME"))
+			Expect(stdout.String()).To(MatchRegexp(`2\s+new-cart\s+true\s+1\s+-`))
+			Expect(fake.lastRequest().Authorization).To(Equal("Bearer secret"))
+		})
+
+		It("should print json and yaml", func() {
+			Expect(run("get", "1", "-o", "json")).To(Equal(cobbctl.ExitOK))
+			var list []*flags.FeatureFlagData
+			Expect(json.Unmarshal(stdout.Bytes(), &list)).To(Succeed())
+			Expect(list[0].Name).To(Equal("checkout"))
+
+			Expect(run("get", "1", "--output", "yaml")).To(Equal(cobbctl.ExitOK))
+			Expect(stdout.String()).To(ContainSubstring("name: checkout"))
+		})
+
+		It("should let --server override the context", func() {
+			Expect(run("get", "1", "--server", "http://127.0.0.1:1")).To(Equal(cobbctl.ExitError))
+		})
+	})
+
+	Describe("Toggle", func() {
+		It("should send the state and reason and succeed on a no-op", func() {
+			Expect(run("toggle", "1", "on", "--reason", "release")).To(Equal(cobbctl.ExitOK))
+
+			request := fake.lastRequest()
+			Expect(request.Method).To(Equal(http.MethodPatch))
+			Expect(request.Body).To(Equal(map[string]any{"active": true, "reason": "release"}))
+			Expect(stderr.String()).To(ContainSubstring("Flag is already active"))
+		})
+	})
+
+	Describe("Deps", func() {
+		It("should add to the current dependencies", func() {
+			Expect(run("deps", "2", "add", "3,4", "--reason", "rollout")).To(Equal(cobbctl.ExitOK))
+
+			request := fake.lastRequest()
+			Expect(request.Method).To(Equal(http.MethodPut))
+			Expect(request.Path).To(Equal("/api/v1/flags/2/dependencies"))
+			Expect(request.Body["dependencies"]).To(Equal([]any{float64(1), float64(3), float64(4)}))
+		})
+
+		It("should remove from the current dependencies", func() {
+			Expect(run("deps", "2", "remove", "1", "--reason", "cleanup")).To(Equal(cobbctl.ExitOK))
+
+			Expect(fake.lastRequest().Body["dependencies"]).To(Equal([]any{}))
+		})
+	})
+
+	Describe("List", func() {
+		It("should print pagination under the table", func() {
+			Expect(run("list")).To(Equal(cobbctl.ExitOK))
+
+			Expect(stdout.String()).To(ContainSubstring("checkout"))
+			Expect(stdout.String()).To(ContainSubstring("page 1 of 1, 2 flags"))
+		})
+	})
+
+	Describe("Explain", func() {
+		It("should send the targeting key and print the trace", func() {
+			Expect(run("explain", "2", "--key", "user-2")).To(Equal(cobbctl.ExitOK))
+
+			request := fake.lastRequest()
+			Expect(request.Path).To(Equal("/api/v1/flags/2/explain"))
+			Expect(request.Body).To(Equal(map[string]any{"context": map[string]any{"targeting_key": "user-2"}}))
+			Expect(stdout.String()).To(MatchRegexp(`2\s+new-cart\s+false\s+off\s+PREREQUISITE_FAILED`))
+			Expect(stdout.String()).To(MatchRegexp(`\n  1\s+deny_list\s+true\s+"user-2" is on the deny list`))
+		})
+
+		It("should print json and yaml", func() {
+			Expect(run("explain", "2", "--key", "user-2", "-o", "json")).To(Equal(cobbctl.ExitOK))
+			var data flags.ExplainFeatureFlagData
+			Expect(json.Unmarshal(stdout.Bytes(), &data)).To(Succeed())
+			Expect(data.Trace).To(HaveLen(3))
+
+			Expect(run("explain", "2", "--key", "user-2", "-o", "yaml")).To(Equal(cobbctl.ExitOK))
+			Expect(stdout.String()).To(ContainSubstring("check: deny_list"))
+		})
+
+		It("should require a targeting key", func() {
+			Expect(run("explain", "2")).To(Equal(cobbctl.ExitUsage))
+		})
+	})
+
+	Describe("Logs", func() {
+		It("should print the oldest log first", func() {
+			Expect(run("logs", "1", "-n", "2")).To(Equal(cobbctl.ExitOK))
+
+			Expect(stdout.String()).To(MatchRegexp(`(?s)first.*second`))
+		})
+	})
+
+	Describe("Audit", func() {
+		It("should fail with the tampered exit code on a broken chain", func() {
+			Expect(run("audit", "verify")).To(Equal(cobbctl.ExitTampered))
+
+			Expect(stdout.String()).To(ContainSubstring("false"))
+			Expect(stderr.String()).To(ContainSubstring("audit log chain is broken at sequence 5: content does not match its hash"))
+		})
+
+		It("should reject an unknown action", func() {
+			Expect(run("audit", "repair")).To(Equal(cobbctl.ExitUsage))
+		})
+	})
+
+	Describe("Tree", func() {
+		It("should render every root with its dependencies", func() {
+			Expect(run("tree")).To(Equal(cobbctl.ExitOK))
+
+			Expect(stdout.String()).To(Equal("" +
+				"wallet (3) [off]\n" +
+				"├── checkout (1) [on]\n" +
+				"└── new-cart (2) [on]\n" +
+				"    └── checkout (1) [on]\n"))
+		})
+
+		It("should render dependents with --reverse", func() {
+			Expect(run("tree", "1", "--reverse")).To(Equal(cobbctl.ExitOK))
+
+			Expect(stdout.String()).To(Equal("" +
+				"checkout (1) [on]\n" +
+				"├── new-cart (2) [on]\n" +
+				"│   └── wallet (3) [off]\n" +
+				"└── wallet (3) [off]\n"))
+		})
+
+		It("should return not found for an unknown root", func() {
+			Expect(run("tree", "9")).To(Equal(cobbctl.ExitNotFound))
+		})
+	})
+
+	Describe("Context", func() {
+		It("should switch and delete contexts", func() {
+			Expect(run("context", "set", "prod", "--server", "https://flags.example.com", "--username", "ops")).To(Equal(cobbctl.ExitOK))
+			Expect(run("context", "current")).To(Equal(cobbctl.ExitOK))
+			Expect(stdout.String()).To(Equal("test\n"))
+
+			Expect(run("context", "use", "prod")).To(Equal(cobbctl.ExitOK))
+			Expect(run("context", "list")).To(Equal(cobbctl.ExitOK))
+			Expect(stdout.String()).To(MatchRegexp(`\*\s+prod\s+https://flags.example.com\s+basic`))
+			Expect(stdout.String()).NotTo(ContainSubstring("secret"))
+
+			Expect(run("context", "delete", "prod")).To(Equal(cobbctl.ExitOK))
+			Expect(run("context", "current")).To(Equal(cobbctl.ExitError))
+			Expect(run("context", "use", "prod")).To(Equal(cobbctl.ExitUsage))
+		})
+	})
+})
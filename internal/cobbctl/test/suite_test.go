@@ -0,0 +1,13 @@
+package cobbctl_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestCobbctl(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Cobbctl Suite")
+}
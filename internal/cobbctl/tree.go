@@ -0,0 +1,110 @@
+package cobbctl
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+// TreeNode is a flag with the flags it depends on (or, for a reversed tree,
+// the flags depending on it) as children.
+type TreeNode struct {
+	ID       uint        `json:"id"`
+	Name     string      `json:"name"`
+	Active   bool        `json:"active"`
+	Children []*TreeNode `json:"children,omitempty"`
+}
+
+// BuildTree builds dependency trees from the flags configuration. With a
+// root id it returns that flag's tree, otherwise one tree per flag that
+// nothing else points at. Reverse follows dependents instead of
+// dependencies.
+func BuildTree(config *evaluation.Config, rootId uint, reverse bool) ([]*TreeNode, error) {
+	flagsById := make(map[uint]*evaluation.Flag, len(config.Flags))
+	edges := make(map[uint][]uint)
+	hasParent := make(map[uint]bool)
+	for _, flag := range config.Flags {
+		flagsById[flag.ID] = flag
+	}
+	for _, flag := range config.Flags {
+		for _, dependencyId := range flag.Dependencies {
+			from, to := flag.ID, dependencyId
+			if reverse {
+				from, to = to, from
+			}
+			edges[from] = append(edges[from], to)
+			hasParent[to] = true
+		}
+	}
+	for id := range edges {
+		sort.Slice(edges[id], func(i, j int) bool { return edges[id][i] < edges[id][j] })
+	}
+
+	var build func(id uint, path map[uint]bool) *TreeNode
+	build = func(id uint, path map[uint]bool) *TreeNode {
+		flag := flagsById[id]
+		node := &TreeNode{ID: id}
+		if flag != nil {
+			node.Name = flag.Name
+			node.Active = flag.Active
+		}
+		// The server rejects cycles; the guard keeps a corrupt graph from
+		// recursing forever.
+		if path[id] {
+			return node
+		}
+		path[id] = true
+		for _, childId := range edges[id] {
+			node.Children = append(node.Children, build(childId, path))
+		}
+		delete(path, id)
+		return node
+	}
+
+	if rootId != 0 {
+		if flagsById[rootId] == nil {
+			return nil, &APIError{StatusCode: http.StatusNotFound, ErrorResponse: api.ErrorResponse{Error: "Invalid flag id"}}
+		}
+		return []*TreeNode{build(rootId, map[uint]bool{})}, nil
+	}
+
+	roots := make([]*TreeNode, 0)
+	for _, flag := range config.Flags {
+		if !hasParent[flag.ID] {
+			roots = append(roots, build(flag.ID, map[uint]bool{}))
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool { return roots[i].ID < roots[j].ID })
+	return roots, nil
+}
+
+// RenderTree draws a tree with box-drawing characters, one flag per line.
+func RenderTree(root *TreeNode) string {
+	var b strings.Builder
+	b.WriteString(formatNode(root) + "\n")
+	renderChildren(&b, root.Children, "")
+	return b.String()
+}
+
+func renderChildren(b *strings.Builder, children []*TreeNode, prefix string) {
+	for i, child := range children {
+		connector, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		b.WriteString(prefix + connector + formatNode(child) + "\n")
+		renderChildren(b, child.Children, prefix+indent)
+	}
+}
+
+func formatNode(node *TreeNode) string {
+	state := "off"
+	if node.Active {
+		state = "on"
+	}
+	return fmt.Sprintf("%s (%d) [%s]", node.Name, node.ID, state)
+}
@@ -58,3 +58,11 @@ func GetMaxIdleConnections() (int, error) {
 
 	return maxIdleConnections, nil
 }
+
+func IsMigrateOnStartupEnabled() (bool, error) {
+	enabledStr, exists := os.LookupEnv("POSTGRES_MIGRATE_ON_STARTUP")
+	if !exists {
+		return false, nil
+	}
+	return strconv.ParseBool(enabledStr)
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	ChangesChannel = "dom_cobb_changes"
+
+	maxReconnectDelay = 30 * time.Second
+)
+
+type Notification struct {
+	Payload string
+	// Resync is set after the listener (re)connects: notifications sent
+	// while it was disconnected are lost, so subscribers must reload.
+	Resync bool
+}
+
+type Listener struct {
+	channel string
+
+	mu          sync.Mutex
+	subscribers map[chan Notification]struct{}
+}
+
+var (
+	listener     *Listener
+	onceListener sync.Once
+)
+
+func GetListener() *Listener {
+	onceListener.Do(func() {
+		listener = NewListener(ChangesChannel)
+		go listener.Run(context.Background())
+	})
+	return listener
+}
+
+func NewListener(channel string) *Listener {
+	return &Listener{
+		channel:     channel,
+		subscribers: make(map[chan Notification]struct{}),
+	}
+}
+
+func (l *Listener) Subscribe() (<-chan Notification, func()) {
+	subscriber := make(chan Notification, 1)
+
+	l.mu.Lock()
+	l.subscribers[subscriber] = struct{}{}
+	l.mu.Unlock()
+
+	return subscriber, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.subscribers, subscriber)
+	}
+}
+
+func (l *Listener) publish(notification Notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for subscriber := range l.subscribers {
+		select {
+		case subscriber <- notification:
+		default:
+			// A pending notification already makes the subscriber reload,
+			// but a resync must not be collapsed into a plain one.
+			if notification.Resync {
+				select {
+				case <-subscriber:
+				default:
+				}
+				subscriber <- notification
+			}
+		}
+	}
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	delay := time.Second
+	for {
+		connected, _ := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxReconnectDelay)
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) (bool, error) {
+	dsn, err := GetDSN()
+	if err != nil {
+		return false, err
+	}
+
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return false, err
+	}
+	l.publish(Notification{Resync: true})
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		l.publish(Notification{Payload: notification.Payload})
+	}
+}
+
+func Notify(tx *gorm.DB, channel, payload string) error {
+	return tx.Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
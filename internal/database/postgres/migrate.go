@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationsLockKey keeps replicas that start together from applying the
+// same migration twice.
+const migrationsLockKey = 0x636f6263
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type SchemaMigration struct {
+	Version   uint      `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Unknown marks a version recorded in the database that this build
+	// does not ship, e.g. after rolling back to an older release.
+	Unknown bool
+}
+
+// GetMigrations returns the migrations embedded in the binary.
+func GetMigrations() ([]*Migration, error) {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return LoadMigrations(sub)
+}
+
+// LoadMigrations reads <version>_<name>.up.sql and .down.sql pairs from
+// fsys and returns them sorted by version. Every version needs both files.
+func LoadMigrations(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[uint(version)]
+		if !exists {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has mismatched names %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d must have both up and down files", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// PendingMigrations returns the migrations not yet applied, oldest first.
+func PendingMigrations(migrations []*Migration, applied []*SchemaMigration) []*Migration {
+	appliedVersions := make(map[uint]bool, len(applied))
+	for _, schemaMigration := range applied {
+		appliedVersions[schemaMigration.Version] = true
+	}
+
+	pending := make([]*Migration, 0)
+	for _, migration := range migrations {
+		if !appliedVersions[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// RollbackMigrations returns up to steps applied migrations to revert,
+// newest first. Versions this build does not ship cannot be reverted.
+func RollbackMigrations(migrations []*Migration, applied []*SchemaMigration, steps int) ([]*Migration, error) {
+	byVersion := make(map[uint]*Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	sorted := append([]*SchemaMigration(nil), applied...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version > sorted[j].Version })
+
+	rollback := make([]*Migration, 0, steps)
+	for _, schemaMigration := range sorted {
+		if len(rollback) == steps {
+			break
+		}
+		migration, exists := byVersion[schemaMigration.Version]
+		if !exists {
+			return nil, fmt.Errorf("migration %d is applied but unknown to this build", schemaMigration.Version)
+		}
+		rollback = append(rollback, migration)
+	}
+	return rollback, nil
+}
+
+// MigrationStatuses merges the shipped migrations with the applied ones.
+func MigrationStatuses(migrations []*Migration, applied []*SchemaMigration) []*MigrationStatus {
+	appliedByVersion := make(map[uint]*SchemaMigration, len(applied))
+	for _, schemaMigration := range applied {
+		appliedByVersion[schemaMigration.Version] = schemaMigration
+	}
+
+	statuses := make([]*MigrationStatus, 0, len(migrations))
+	shipped := make(map[uint]bool, len(migrations))
+	for _, migration := range migrations {
+		shipped[migration.Version] = true
+		status := &MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if schemaMigration, exists := appliedByVersion[migration.Version]; exists {
+			status.Applied = true
+			status.AppliedAt = &schemaMigration.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for _, schemaMigration := range applied {
+		if shipped[schemaMigration.Version] {
+			continue
+		}
+		statuses = append(statuses, &MigrationStatus{
+			Version:   schemaMigration.Version,
+			Name:      schemaMigration.Name,
+			Applied:   true,
+			AppliedAt: &schemaMigration.AppliedAt,
+			Unknown:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+func NewMigrator(db *gorm.DB, migrations []*Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns the ones it applied.
+func (m *Migrator) Up() ([]*Migration, error) {
+	var applied []*Migration
+	err := m.withLock(func(conn *gorm.DB) error {
+		schemaMigrations, err := m.getApplied(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range PendingMigrations(m.migrations, schemaMigrations) {
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the newest steps applied migrations and returns them.
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	var reverted []*Migration
+	err := m.withLock(func(conn *gorm.DB) error {
+		schemaMigrations, err := m.getApplied(conn)
+		if err != nil {
+			return err
+		}
+		rollback, err := RollbackMigrations(m.migrations, schemaMigrations, steps)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range rollback {
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("rollback of migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	var schemaMigrations []*SchemaMigration
+	if m.db.Migrator().HasTable(&SchemaMigration{}) {
+		if err := m.db.Order("version").Find(&schemaMigrations).Error; err != nil {
+			return nil, err
+		}
+	}
+	return MigrationStatuses(m.migrations, schemaMigrations), nil
+}
+
+// withLock runs fn on a single connection holding a session advisory lock,
+// so concurrent migrators wait for each other instead of racing.
+func (m *Migrator) withLock(fn func(conn *gorm.DB) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationsLockKey).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationsLockKey)
+
+		return fn(conn)
+	})
+}
+
+func (m *Migrator) getApplied(conn *gorm.DB) ([]*SchemaMigration, error) {
+	err := conn.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaMigrations []*SchemaMigration
+	if err := conn.Order("version").Find(&schemaMigrations).Error; err != nil {
+		return nil, err
+	}
+	return schemaMigrations, nil
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS flag_dependencies;
+DROP TABLE IF EXISTS feature_flags;
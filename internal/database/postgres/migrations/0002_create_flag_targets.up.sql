@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS flag_targets (
+    flag_id BIGINT NOT NULL,
+    targeting_key VARCHAR(255) NOT NULL,
+    list VARCHAR(8) NOT NULL CHECK (list IN ('allow', 'deny')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (flag_id, targeting_key),
+    CONSTRAINT fk_flag_targets_flag_id
+        FOREIGN KEY (flag_id) REFERENCES feature_flags (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_flag_targets_targeting_key ON flag_targets (targeting_key);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS flag_events (
+    revision BIGSERIAL PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    flag_id BIGINT NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL,
+    dependencies JSONB,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_flag_events_flag_id ON flag_events (flag_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_flag_events_created_at;
+
+ALTER TABLE flag_events DROP COLUMN IF EXISTS caused_by;
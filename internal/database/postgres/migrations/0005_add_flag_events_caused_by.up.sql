@@ -0,0 +1,3 @@
+ALTER TABLE flag_events ADD COLUMN IF NOT EXISTS caused_by BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_flag_events_created_at ON flag_events (created_at);
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS flag_versions (
+    id BIGSERIAL PRIMARY KEY,
+    flag_id BIGINT NOT NULL,
+    "version" BIGINT NOT NULL,
+    change VARCHAR(32) NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL,
+    dependencies JSONB NOT NULL,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    caused_by BIGINT NOT NULL DEFAULT 0,
+    rolled_back_to BIGINT NOT NULL DEFAULT 0,
+    revision BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_flag_versions_flag_id_version ON flag_versions (flag_id, "version");
+
+-- Existing flags start from a baseline version with their current state.
+INSERT INTO flag_versions (flag_id, "version", change, "name", active, dependencies, actor, revision)
+SELECT
+    f.id,
+    1,
+    'imported',
+    f.name,
+    f.is_active,
+    COALESCE(
+        (SELECT jsonb_agg(fd.depends_on_flag_id ORDER BY fd.depends_on_flag_id)
+         FROM flag_dependencies fd
+         WHERE fd.flag_id = f.id),
+        '[]'::jsonb
+    ),
+    'system',
+    COALESCE((SELECT MAX(e.revision) FROM flag_events e WHERE e.flag_id = f.id), 0)
+FROM feature_flags f
+WHERE f.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
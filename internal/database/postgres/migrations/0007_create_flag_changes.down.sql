@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_flag_versions_change_id;
+
+ALTER TABLE flag_versions DROP COLUMN IF EXISTS change_id;
+
+DROP TABLE IF EXISTS flag_changes;
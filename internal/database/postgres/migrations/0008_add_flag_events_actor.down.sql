@@ -0,0 +1,3 @@
+ALTER TABLE flag_events DROP COLUMN IF EXISTS previous_dependencies;
+
+ALTER TABLE flag_events DROP COLUMN IF EXISTS actor;
@@ -0,0 +1,3 @@
+ALTER TABLE flag_events ADD COLUMN IF NOT EXISTS actor VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE flag_events ADD COLUMN IF NOT EXISTS previous_dependencies JSONB;
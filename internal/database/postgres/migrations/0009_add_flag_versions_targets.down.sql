@@ -0,0 +1,3 @@
+ALTER TABLE flag_versions DROP COLUMN IF EXISTS deny;
+
+ALTER TABLE flag_versions DROP COLUMN IF EXISTS allow;
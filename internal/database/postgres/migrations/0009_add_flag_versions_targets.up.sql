@@ -0,0 +1,21 @@
+ALTER TABLE flag_versions ADD COLUMN IF NOT EXISTS allow JSONB;
+
+ALTER TABLE flag_versions ADD COLUMN IF NOT EXISTS deny JSONB;
+
+-- The latest version of every flag holds its current targets; older
+-- versions keep no targets and leave them untouched when restored.
+UPDATE flag_versions v
+SET
+    allow = COALESCE(
+        (SELECT jsonb_agg(t.targeting_key ORDER BY t.targeting_key)
+         FROM flag_targets t
+         WHERE t.flag_id = v.flag_id AND t.list = 'allow'),
+        '[]'::jsonb
+    ),
+    deny = COALESCE(
+        (SELECT jsonb_agg(t.targeting_key ORDER BY t.targeting_key)
+         FROM flag_targets t
+         WHERE t.flag_id = v.flag_id AND t.list = 'deny'),
+        '[]'::jsonb
+    )
+WHERE v."version" = (SELECT MAX(l."version") FROM flag_versions l WHERE l.flag_id = v.flag_id);
@@ -0,0 +1,160 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func createMigrations(versions ...uint) []*postgres.Migration {
+	migrations := make([]*postgres.Migration, 0, len(versions))
+	for _, version := range versions {
+		migrations = append(migrations, &postgres.Migration{Version: version, Name: "m", Up: "up", Down: "down"})
+	}
+	return migrations
+}
+
+func createApplied(versions ...uint) []*postgres.SchemaMigration {
+	applied := make([]*postgres.SchemaMigration, 0, len(versions))
+	for _, version := range versions {
+		applied = append(applied, &postgres.SchemaMigration{Version: version, Name: "m", AppliedAt: time.Unix(int64(version), 0)})
+	}
+	return applied
+}
+
+func versionsOf(migrations []*postgres.Migration) []uint {
+	versions := make([]uint, 0, len(migrations))
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+var _ = Describe("Migrations", func() {
+	Describe("Embedded migrations", func() {
+		It("should have contiguous versions with up and down files", func() {
+			migrations, err := postgres.GetMigrations()
+
+			Expect(err).NotTo(HaveOccurred())
+			for i, migration := range migrations {
+				Expect(migration.Version).To(Equal(uint(i + 1)))
+				Expect(migration.Up).NotTo(BeEmpty())
+				Expect(migration.Down).NotTo(BeEmpty())
+			}
+		})
+
+		It("should create the table of every model", func() {
+			migrations, err := postgres.GetMigrations()
+			Expect(err).NotTo(HaveOccurred())
+
+			var up strings.Builder
+			for _, migration := range migrations {
+				up.WriteString(migration.Up)
+			}
+			for _, table := range []string{
+				flags.FeatureFlag{}.TableName(),
+				flags.FlagDependency{}.TableName(),
+				flags.FlagTarget{}.TableName(),
+				flags.FlagEvent{}.TableName(),
+				flags.FlagVersion{}.TableName(),
+				flags.FlagChange{}.TableName(),
+				webhooks.Webhook{}.TableName(),
+				webhooks.WebhookDelivery{}.TableName(),
+				webhooks.WebhookDispatchState{}.TableName(),
+			} {
+				Expect(up.String()).To(ContainSubstring("CREATE TABLE IF NOT EXISTS "+table+" ("), table)
+			}
+		})
+	})
+
+	Describe("Load Migrations", func() {
+		It("should sort migrations by version", func() {
+			migrations, err := postgres.LoadMigrations(fstest.MapFS{
+				"0002_b.up.sql":   {Data: []byte("up b")},
+				"0002_b.down.sql": {Data: []byte("down b")},
+				"0001_a.up.sql":   {Data: []byte("up a")},
+				"0001_a.down.sql": {Data: []byte("down a")},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versionsOf(migrations)).To(Equal([]uint{1, 2}))
+			Expect(migrations[0].Name).To(Equal("a"))
+			Expect(migrations[0].Up).To(Equal("up a"))
+			Expect(migrations[0].Down).To(Equal("down a"))
+		})
+
+		DescribeTable("should reject invalid migration files",
+			func(fsys fstest.MapFS, message string) {
+				_, err := postgres.LoadMigrations(fsys)
+
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("missing down file", fstest.MapFS{
+				"0001_a.up.sql": {Data: []byte("up")},
+			}, "must have both up and down files"),
+			Entry("invalid name", fstest.MapFS{
+				"create.sql": {Data: []byte("up")},
+			}, "invalid migration file name"),
+			Entry("zero version", fstest.MapFS{
+				"0000_a.up.sql":   {Data: []byte("up")},
+				"0000_a.down.sql": {Data: []byte("down")},
+			}, "invalid migration version"),
+			Entry("mismatched names", fstest.MapFS{
+				"0001_a.up.sql":   {Data: []byte("up")},
+				"0001_b.down.sql": {Data: []byte("down")},
+			}, "mismatched names"),
+		)
+	})
+
+	Describe("Pending Migrations", func() {
+		It("should return unapplied migrations oldest first", func() {
+			pending := postgres.PendingMigrations(createMigrations(1, 2, 3, 4), createApplied(1, 3))
+
+			Expect(versionsOf(pending)).To(Equal([]uint{2, 4}))
+		})
+	})
+
+	Describe("Rollback Migrations", func() {
+		It("should return the newest applied migrations first", func() {
+			rollback, err := postgres.RollbackMigrations(createMigrations(1, 2, 3), createApplied(1, 2, 3), 2)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versionsOf(rollback)).To(Equal([]uint{3, 2}))
+		})
+
+		It("should stop at the oldest applied migration", func() {
+			rollback, err := postgres.RollbackMigrations(createMigrations(1, 2), createApplied(1), 5)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versionsOf(rollback)).To(Equal([]uint{1}))
+		})
+
+		It("should refuse to revert versions unknown to the build", func() {
+			_, err := postgres.RollbackMigrations(createMigrations(1, 2), createApplied(1, 2, 3), 1)
+
+			Expect(err).To(MatchError(ContainSubstring("migration 3 is applied but unknown")))
+		})
+	})
+
+	Describe("Migration Statuses", func() {
+		It("should merge applied, pending and unknown versions", func() {
+			statuses := postgres.MigrationStatuses(createMigrations(1, 2), createApplied(1, 5))
+
+			Expect(statuses).To(HaveLen(3))
+			Expect(statuses[0].Version).To(Equal(uint(1)))
+			Expect(statuses[0].Applied).To(BeTrue())
+			Expect(statuses[0].AppliedAt.Unix()).To(Equal(int64(1)))
+			Expect(statuses[1].Version).To(Equal(uint(2)))
+			Expect(statuses[1].Applied).To(BeFalse())
+			Expect(statuses[1].AppliedAt).To(BeNil())
+			Expect(statuses[2].Version).To(Equal(uint(5)))
+			Expect(statuses[2].Unknown).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,162 @@
+package domcobb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/metrics"
+	"github.com/joho/godotenv"
+)
+
+const migrateUsage = `Usage: server migrate [up | down [steps] | status]
+
+  up              apply every pending migration and bootstrap the log
+                  collection indexes and validator (default)
+  down [steps]    revert the newest applied migrations, 1 by default
+  status          list applied and pending migrations
+`
+
+// Migrate runs the migrate subcommand and returns the process exit code.
+func Migrate(args []string, stdout, stderr io.Writer) int {
+	action, steps, ok := parseMigrateArgs(args)
+	if !ok {
+		fmt.Fprint(stderr, migrateUsage)
+		return 2
+	}
+
+	// Like the relay, migrations can be configured from the environment
+	// alone.
+	_ = godotenv.Load()
+
+	migrations, err := postgres.GetMigrations()
+	if err != nil {
+		fmt.Fprintln(stderr, "Failed to load migrations: "+err.Error())
+		return 1
+	}
+	migrator := postgres.NewMigrator(postgres.GetDB(), migrations)
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up()
+		for _, migration := range applied {
+			fmt.Fprintf(stdout, "applied %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err.Error())
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(stdout, "no pending migrations")
+		}
+		if err := logger.EnsureCollection(context.Background()); err != nil {
+			fmt.Fprintln(stderr, "Failed to bootstrap log collection: "+err.Error())
+			return 1
+		}
+		fmt.Fprintln(stdout, "log collection indexes and validator are up to date")
+	case "down":
+		reverted, err := migrator.Down(steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(stdout, "reverted %d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, err.Error())
+			return 1
+		}
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			fmt.Fprintln(stderr, err.Error())
+			return 1
+		}
+		printMigrationStatuses(stdout, statuses)
+	}
+
+	return 0
+}
+
+func parseMigrateArgs(args []string) (string, int, bool) {
+	if len(args) == 0 {
+		return "up", 0, true
+	}
+
+	switch args[0] {
+	case "up", "status":
+		return args[0], 0, len(args) == 1
+	case "down":
+		if len(args) == 1 {
+			return "down", 1, true
+		}
+		steps, err := strconv.Atoi(args[1])
+		return "down", steps, err == nil && steps >= 1 && len(args) == 2
+	default:
+		return "", 0, false
+	}
+}
+
+// migrateOnStartup applies pending migrations when
+// POSTGRES_MIGRATE_ON_STARTUP is set. Replicas starting together wait on the
+// migration advisory lock, so only the first one applies anything.
+func migrateOnStartup() {
+	enabled, err := postgres.IsMigrateOnStartupEnabled()
+	if err != nil {
+		panic("Failed to get migrate on startup config: " + err.Error())
+	}
+	if !enabled {
+		return
+	}
+
+	migrations, err := postgres.GetMigrations()
+	if err != nil {
+		panic("Failed to load migrations: " + err.Error())
+	}
+	applied, err := postgres.NewMigrator(postgres.GetDB(), migrations).Up()
+	for _, migration := range applied {
+		fmt.Fprintf(os.Stdout, "applied migration %d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		panic("Failed to apply migrations: " + err.Error())
+	}
+}
+
+// bootstrapLogs installs the log collection's indexes and validator and, with
+// retention, starts removing expired entries.
+func bootstrapLogs() {
+	if err := logger.EnsureCollection(context.Background()); err != nil {
+		panic("Failed to bootstrap log collection: " + err.Error())
+	}
+
+	retainer, err := logger.GetRetainer()
+	if err != nil {
+		panic("Failed to get log retainer: " + err.Error())
+	}
+	if retainer != nil {
+		metrics.RegisterGauge("dom_cobb_log_retention_failures", "Number of failed log retention runs.", func() float64 {
+			return float64(retainer.Failures())
+		})
+		go retainer.Run(nil)
+	}
+}
+
+func printMigrationStatuses(w io.Writer, statuses []*postgres.MigrationStatus) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Local().Format(time.RFC3339)
+		}
+		if status.Unknown {
+			state = "applied (unknown to this build)"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	tw.Flush()
+}
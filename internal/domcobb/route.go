@@ -2,11 +2,19 @@ package domcobb
 
 import (
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/metrics"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/stream"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/swagger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(router *gin.Engine) {
 	flags.SetupRoutes(router)
+	logs.SetupRoutes(router)
+	stream.SetupRoutes(router)
+	webhooks.SetupRoutes(router)
 	swagger.SetupRoutes(router)
+	metrics.SetupRoutes(router)
 }
@@ -1,15 +1,32 @@
 package domcobb
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/rpc"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks"
+	"github.com/gin-gonic/gin"
+)
 
 func Run() {
-	r := gin.Default()
-
-	SetupRoutes(r)
-
 	port, err := GetAppPort()
 	if err != nil {
 		panic(err)
 	}
+
+	migrateOnStartup()
+	bootstrapLogs()
+
+	r := gin.Default()
+	r.Use(api.RequestIDMiddleware(), api.ActorMiddleware())
+
+	notifications, _ := postgres.GetListener().Subscribe()
+	go webhooks.GetDispatcher().Run(nil, notifications)
+
+	SetupRoutes(r)
+
+	go rpc.Run(flags.GetService(flags.GetRepository(), logger.NewService()))
 	r.Run(":" + port)
 }
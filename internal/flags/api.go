@@ -2,9 +2,12 @@ package flags
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
 	"github.com/gin-gonic/gin"
 )
 
@@ -27,7 +30,7 @@ type CreateFeatureFlagRequest struct {
 // @Accept json
 // @Produce json
 // @Param request body CreateFeatureFlagRequest true "Feature flag creation request"
-// @Success 201 {object} api.SuccessResponse"Feature flag created successfully"
+// @Success 201 {object} api.SuccessResponse{data=FeatureFlagData} "Feature flag created successfully"
 // @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
 // @Failure 404 {object} api.ErrorResponse "Not Found Error"
 // @Failure 409 {object} api.ErrorResponse "Conflict Error"
@@ -42,13 +45,19 @@ func CreateFeatureFlagAPI(c *gin.Context) {
 		return
 	}
 
-	err = service.CreateFeatureFlag(req)
+	flag, err := service.CreateFeatureFlag(c.Request.Context(), req)
 	if err != nil {
 		api.RespondAPIError(c, err)
 		return
 	}
 
-	api.RespondSuccess(c, http.StatusCreated, "Feature flag is created successfully", nil)
+	data, err := service.GetFeatureFlag(flag)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusCreated, "Feature flag is created successfully", data)
 }
 
 // @Description Request payload for updating a feature flag
@@ -77,7 +86,7 @@ func UpdateFeatureFlagAPI(c *gin.Context) {
 		return
 	}
 
-	err = service.UpdateFeatureFlag(flag, req)
+	err = service.UpdateFeatureFlag(c.Request.Context(), flag, req)
 	if err != nil {
 		api.RespondAPIError(c, err)
 		return
@@ -95,12 +104,19 @@ type FeatureFlagData struct {
 	Dependents   []uint `json:"dependents"`
 }
 
+// @Description Query parameters for the get feature flag request
+type GetFeatureFlagQueryParams struct {
+	AsOf time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
 // @Summary Get a feature flag
-// @Description Retrieve a feature flag by its ID including its dependencies and dependents
+// @Description Retrieve a feature flag by its ID including its dependencies and dependents, optionally as it was
+// @Description at a past time
 // @Tags feature-flags
 // @Accept json
 // @Produce json
 // @Param id path int true "Feature Flag ID"
+// @Param as_of query string false "RFC 3339 time to reconstruct the flag at"
 // @Success 200 {object} api.SuccessResponse{data=FeatureFlagData} "Feature flag retrieved successfully"
 // @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
 // @Failure 404 {object} api.ErrorResponse "Feature flag not found"
@@ -109,13 +125,24 @@ type FeatureFlagData struct {
 func GetFeatureFlagAPI(c *gin.Context) {
 	service := newFeatureFlagService()
 
+	query, err := service.ValidateGetFeatureFlagQuery(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
 	flag, err := service.ValidateGetFeatureFlagRequest(c)
 	if err != nil {
 		api.RespondAPIError(c, err)
 		return
 	}
 
-	data, err := service.GetFeatureFlag(flag)
+	var data *FeatureFlagData
+	if query.AsOf.IsZero() {
+		data, err = service.GetFeatureFlag(flag)
+	} else {
+		data, err = service.GetFeatureFlagAsOf(flag, query.AsOf)
+	}
 	if err != nil {
 		api.RespondAPIError(c, err)
 		return
@@ -124,25 +151,381 @@ func GetFeatureFlagAPI(c *gin.Context) {
 	api.RespondSuccess(c, http.StatusOK, "Feature flag is retrieved successfully", data)
 }
 
+// @Description Query parameters for paginated feature flags list request
+type ListFeatureFlagsQueryParams struct {
+	api.PaginationQueryParam
+	AsOf time.Time `form:"as_of" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// @Description Paginated response containing feature flags
+type ListFeatureFlagsData struct {
+	Flags []*FeatureFlagData `json:"flags"`
+	api.PaginationResponse
+}
+
+// @Summary List feature flags
+// @Description Retrieve a page of feature flags ordered by ID, each with its dependencies and dependents,
+// @Description optionally as they were at a past time
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param size query int false "Number of items per page (default: 10)" minimum(1) maximum(20)
+// @Param as_of query string false "RFC 3339 time to reconstruct the flags at"
+// @Success 200 {object} api.SuccessResponse{data=ListFeatureFlagsData} "Feature flags retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags [get]
+func ListFeatureFlagsAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	query, err := service.ValidateListFeatureFlagsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	var data *ListFeatureFlagsData
+	if query.AsOf.IsZero() {
+		data, err = service.ListFeatureFlags(query)
+	} else {
+		data, err = service.ListFeatureFlagsAsOf(query)
+	}
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flags are retrieved successfully", data)
+}
+
+// @Description Query parameters for the feature flags diff request
+type GetFeatureFlagsDiffQueryParams struct {
+	From time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To   time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// @Description State of a feature flag at a point in time
+type FeatureFlagStateData struct {
+	Name         string `json:"name"`
+	Active       bool   `json:"active"`
+	Dependencies []uint `json:"dependencies"`
+}
+
+// @Description A feature flag whose state changed, with the events that changed it
+type FeatureFlagDiff struct {
+	ID      uint                  `json:"id"`
+	Before  *FeatureFlagStateData `json:"before"`
+	After   *FeatureFlagStateData `json:"after"`
+	Changes []*FlagVersion        `json:"changes"`
+}
+
+// @Description Feature flags whose state changed between two times
+type FeatureFlagsDiffData struct {
+	From  time.Time          `json:"from"`
+	To    time.Time          `json:"to"`
+	Flags []*FeatureFlagDiff `json:"flags"`
+}
+
+// @Summary Diff feature flags between two times
+// @Description List every feature flag whose state or dependencies at to differ from those at from, with its state
+// @Description at both times and the changes in between with their reasons. before is null for flags created in
+// @Description between; flags that changed and changed back are left out.
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param from query string true "RFC 3339 start time, exclusive"
+// @Param to query string true "RFC 3339 end time, inclusive"
+// @Success 200 {object} api.SuccessResponse{data=FeatureFlagsDiffData} "Feature flags diff retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/diff [get]
+func GetFeatureFlagsDiffAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	query, err := service.ValidateGetFeatureFlagsDiffRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.GetFeatureFlagsDiff(query)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flags diff is retrieved successfully", data)
+}
+
+// @Description Request payload for replacing the dependencies of a feature flag
+type UpdateFeatureFlagDependenciesRequest struct {
+	Dependencies []uint `json:"dependencies"`
+	Reason       string `json:"reason" binding:"required,min=1,max=255"`
+}
+
+// @Summary Update feature flag dependencies
+// @Description Replace the dependencies of a feature flag. Dependencies must exist and must not create a cycle, and an
+// @Description active flag can only depend on active flags.
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param request body UpdateFeatureFlagDependenciesRequest true "New dependencies and the reason for the change"
+// @Success 200 {object} api.SuccessResponse "Feature flag dependencies updated successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/dependencies [put]
+func UpdateFeatureFlagDependenciesAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, req, err := service.ValidateUpdateFeatureFlagDependenciesRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	err = service.UpdateFeatureFlagDependencies(c.Request.Context(), flag, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag dependencies are updated successfully", nil)
+}
+
+// @Description Query parameters for paginated feature flag versions request
+type ListFeatureFlagVersionsQueryParams struct {
+	api.PaginationQueryParam
+}
+
+// @Description Paginated versions of a feature flag, newest first
+type ListFeatureFlagVersionsData struct {
+	Versions []*FlagVersion `json:"versions"`
+	api.PaginationResponse
+}
+
+// @Summary List feature flag versions
+// @Description Retrieve a page of the versions of a feature flag, newest first. Every change of its state or
+// @Description dependencies, including cascaded deactivations and rollbacks, records a version.
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param size query int false "Number of items per page (default: 10)" minimum(1) maximum(20)
+// @Success 200 {object} api.SuccessResponse{data=ListFeatureFlagVersionsData} "Feature flag versions retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/versions [get]
+func ListFeatureFlagVersionsAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, query, err := service.ValidateListFeatureFlagVersionsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.ListFeatureFlagVersions(flag, query)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag versions are retrieved successfully", data)
+}
+
+// @Description Query parameters for the feature flag versions diff request
+type GetFeatureFlagVersionsDiffQueryParams struct {
+	From uint `form:"from" binding:"required,min=1"`
+	To   uint `form:"to" binding:"required,min=1"`
+}
+
+// @Description Differences between two versions of a feature flag
+type FeatureFlagVersionsDiffData struct {
+	From                *FlagVersion `json:"from"`
+	To                  *FlagVersion `json:"to"`
+	ActiveChanged       bool         `json:"active_changed"`
+	AddedDependencies   []uint       `json:"added_dependencies"`
+	RemovedDependencies []uint       `json:"removed_dependencies"`
+}
+
+// @Summary Diff two feature flag versions
+// @Description Compare the state and dependencies of two versions of a feature flag
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param from query int true "Version to compare from" minimum(1)
+// @Param to query int true "Version to compare to" minimum(1)
+// @Success 200 {object} api.SuccessResponse{data=FeatureFlagVersionsDiffData} "Feature flag versions diff retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag or version not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/versions/diff [get]
+func GetFeatureFlagVersionsDiffAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, query, err := service.ValidateGetFeatureFlagVersionsDiffRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.GetFeatureFlagVersionsDiff(flag, query)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag versions diff is retrieved successfully", data)
+}
+
+// @Description Request payload for rolling a feature flag back to a version
+type RollbackFeatureFlagRequest struct {
+	Version uint   `json:"version" binding:"required,min=1"`
+	Reason  string `json:"reason" binding:"required,min=1,max=255"`
+}
+
+// @Summary Roll back a feature flag
+// @Description Restore the state and dependencies a feature flag had at a version. The rollback is validated like a
+// @Description toggle and a dependencies update, and rolling back to an inactive version deactivates the flag's
+// @Description dependents.
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param request body RollbackFeatureFlagRequest true "Version to restore and the reason for the change"
+// @Success 200 {object} api.SuccessResponse "Feature flag rolled back successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag or version not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/rollback [post]
+func RollbackFeatureFlagAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, version, req, err := service.ValidateRollbackFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	err = service.RollbackFeatureFlag(c.Request.Context(), flag, version, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag is rolled back successfully", nil)
+}
+
+// @Description A change set with the versions it wrote
+type FlagChangeData struct {
+	Change   *FlagChange    `json:"change"`
+	Versions []*FlagVersion `json:"versions"`
+}
+
+// @Summary Get a change set
+// @Description Retrieve a change and the versions it wrote: the flag it was made to and every dependent it
+// @Description auto-disabled. The change id of a version is listed with the versions of its flag.
+// @Tags changes
+// @Produce json
+// @Param id path int true "Change ID"
+// @Success 200 {object} api.SuccessResponse{data=FlagChangeData} "Change retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Change not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/changes/{id} [get]
+func GetFlagChangeAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	change, err := service.ValidateGetFlagChangeRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.GetFlagChange(change)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Change is retrieved successfully", data)
+}
+
+// @Description Request payload for undoing a change
+type UndoFlagChangeRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=255"`
+}
+
+// @Summary Undo a change set
+// @Description Restore every flag of a change, including the dependents it auto-disabled, to its version before
+// @Description the change in one transaction. The undo is refused when any of the flags was changed since, and is
+// @Description itself a change that can be undone. Flags the undo deactivates deactivate their dependents.
+// @Tags changes
+// @Accept json
+// @Produce json
+// @Param id path int true "Change ID"
+// @Param request body UndoFlagChangeRequest true "Reason for the undo"
+// @Success 200 {object} api.SuccessResponse{data=FlagChangeData} "Change undone successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error or change cannot be undone"
+// @Failure 404 {object} api.ErrorResponse "Change not found"
+// @Failure 409 {object} api.ErrorResponse "A flag of the change was changed since"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/changes/{id}/undo [post]
+func UndoFlagChangeAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	change, versions, req, err := service.ValidateUndoFlagChangeRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.UndoFlagChange(c.Request.Context(), change, versions, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Change is undone successfully", data)
+}
+
 // @Description Query parameters for paginated feature flag logs request
 type GetFeatureFlagLogsQueryParams struct {
-	api.PaginationQueryParam
+	logs.PageQueryParams
+	logs.FilterQueryParams
 }
 
 // @Description Paginated response containing feature flag logs
 type GetFeatureFlagLogsData struct {
 	Logs []*logger.LogEntry `json:"logs"`
 	api.PaginationResponse
+	logs.CursorPaginationResponse
 }
 
 // @Summary Get feature flag logs
-// @Description Retrieve paginated logs for a specific feature flag
+// @Description Retrieve paginated logs for a specific feature flag with the same filters and pagination as
+// @Description /api/v1/logs, by cursor when page is left out
 // @Tags feature-flags
 // @Accept json
 // @Produce json
 // @Param id path int true "Feature Flag ID"
-// @Param page query int false "Page number (default: 1)" minimum(1)
-// @Param size query int false "Number of items per page (default: 10)" minimum(1) maximum(20)
+// @Param page query int false "Page number, leave out to page by cursor" minimum(1)
+// @Param size query int true "Number of items per page" minimum(1) maximum(20)
+// @Param cursor query string false "Cursor of the page to fetch, from next or prev of a previous response"
+// @Param total query bool false "Count the matching entries when paging by cursor"
+// @Param from query string false "Only entries at or after this RFC 3339 time"
+// @Param to query string false "Only entries before this RFC 3339 time"
+// @Param action query string false "Only entries of this action" Enums(created, toggled, dependencies_updated, targets_added, targets_removed, auto_disabled, rolled_back, undone)
+// @Param actor query string false "Only entries made by this actor"
+// @Param reason query string false "Only entries whose reason contains this text, case-insensitively"
+// @Param cascade query bool false "Only cascaded entries when true, none when false"
+// @Param sort query string false "timestamp for oldest first, -timestamp for newest first (default)" Enums(timestamp, -timestamp)
 // @Success 200 {object} api.SuccessResponse{data=GetFeatureFlagLogsData} "Feature flag logs retrieved successfully"
 // @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
 // @Failure 404 {object} api.ErrorResponse "Feature flag not found"
@@ -165,3 +548,311 @@ func GetFeatureFlagLogsAPI(c *gin.Context) {
 
 	api.RespondSuccess(c, http.StatusOK, "Feature flag logs is retrieved successfully", data)
 }
+
+// @Description Individual targeting lists of a feature flag
+type FeatureFlagTargetsData struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// @Summary Get feature flag targets
+// @Description Retrieve the targeting keys that always receive the on (allow) or off (deny) variant of a feature flag
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Success 200 {object} api.SuccessResponse{data=FeatureFlagTargetsData} "Feature flag targets retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/targets [get]
+func GetFeatureFlagTargetsAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, err := service.ValidateGetFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.GetFeatureFlagTargets(flag)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag targets are retrieved successfully", data)
+}
+
+// @Description Request payload for adding or removing targeting keys of a feature flag list
+type FeatureFlagTargetsRequest struct {
+	TargetingKeys []string `json:"targeting_keys" binding:"required,min=1,max=10000,dive,min=1,max=255"`
+}
+
+// @Summary Add feature flag targets
+// @Description Add targeting keys to the allow or deny list of a feature flag. A key already in the other list is moved.
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param list path string true "Target list" Enums(allow, deny)
+// @Param request body FeatureFlagTargetsRequest true "Targeting keys to add"
+// @Success 200 {object} api.SuccessResponse "Feature flag targets added successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/targets/{list} [post]
+func AddFeatureFlagTargetsAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, list, req, err := service.ValidateFeatureFlagTargetsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	err = service.AddFeatureFlagTargets(c.Request.Context(), flag, list, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag targets are added successfully", nil)
+}
+
+// @Summary Remove feature flag targets
+// @Description Remove targeting keys from the allow or deny list of a feature flag
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param list path string true "Target list" Enums(allow, deny)
+// @Param request body FeatureFlagTargetsRequest true "Targeting keys to remove"
+// @Success 200 {object} api.SuccessResponse "Feature flag targets removed successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/targets/{list} [delete]
+func RemoveFeatureFlagTargetsAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, list, req, err := service.ValidateFeatureFlagTargetsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	err = service.RemoveFeatureFlagTargets(c.Request.Context(), flag, list, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag targets are removed successfully", nil)
+}
+
+// @Description Request payload for evaluating a feature flag
+type EvaluateFeatureFlagRequest struct {
+	Context evaluation.Context `json:"context"`
+}
+
+// @Description Feature flag evaluation result
+type EvaluateFeatureFlagData struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	evaluation.Result
+}
+
+// @Summary Evaluate a feature flag
+// @Description Evaluate a feature flag for the given context, walking its prerequisites and targeting lists
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param request body EvaluateFeatureFlagRequest true "Evaluation context"
+// @Success 200 {object} api.SuccessResponse{data=EvaluateFeatureFlagData} "Feature flag evaluated successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/evaluate [post]
+func EvaluateFeatureFlagAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, req, err := service.ValidateEvaluateFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.EvaluateFeatureFlag(flag, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag is evaluated successfully", data)
+}
+
+// @Description Request payload for evaluating every feature flag
+type EvaluateAllFeatureFlagsRequest struct {
+	Context evaluation.Context `json:"context"`
+}
+
+// @Description Evaluation results of every feature flag keyed by flag name
+type EvaluateAllFeatureFlagsData struct {
+	Flags map[string]*evaluation.Result `json:"flags"`
+}
+
+// @Summary Evaluate all feature flags
+// @Description Evaluate every feature flag for the given context in one round trip. The flag set, dependency graph
+// @Description and targeting lists are loaded once per call. The response is HTML-escaped JSON and safe to embed in a page.
+// @Tags evaluation
+// @Accept json
+// @Produce json
+// @Param request body EvaluateAllFeatureFlagsRequest true "Evaluation context"
+// @Success 200 {object} api.SuccessResponse{data=EvaluateAllFeatureFlagsData} "Feature flags evaluated successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/evaluate/all [post]
+func EvaluateAllFeatureFlagsAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	req, err := service.ValidateEvaluateAllFeatureFlagsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.EvaluateAllFeatureFlags(req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flags are evaluated successfully", data)
+}
+
+// @Description Feature flag evaluation result with the steps that led to it
+type ExplainFeatureFlagData struct {
+	EvaluateFeatureFlagData
+	Trace []*evaluation.TraceStep `json:"trace"`
+}
+
+// @Summary Explain a feature flag evaluation
+// @Description Evaluate a feature flag for the given context and return a step-by-step trace of the prerequisite
+// @Description checks, targeting list lookups and the final decision
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param request body EvaluateFeatureFlagRequest true "Evaluation context"
+// @Success 200 {object} api.SuccessResponse{data=ExplainFeatureFlagData} "Feature flag evaluation explained successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/{id}/explain [post]
+func ExplainFeatureFlagAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	flag, req, err := service.ValidateEvaluateFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.ExplainFeatureFlag(flag, req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag evaluation is explained successfully", data)
+}
+
+// @Summary Get feature flags configuration
+// @Description Retrieve the full evaluation configuration of every feature flag, including dependencies and
+// @Description targeting lists, for local evaluation by SDKs. Responds 304 when If-None-Match matches the ETag.
+// @Tags evaluation
+// @Accept json
+// @Produce json
+// @Param If-None-Match header string false "ETag of the configuration the client already has"
+// @Success 200 {object} api.SuccessResponse{data=evaluation.Config} "Feature flags configuration retrieved successfully"
+// @Success 304 "Feature flags configuration is not modified"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/config [get]
+func GetFeatureFlagsConfigAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	data, etag, err := service.GetFeatureFlagsConfig()
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flags configuration is retrieved successfully", data)
+}
+
+// @Summary Get a signed snapshot of the feature flags configuration
+// @Description Download a self-contained file with every flag, its dependencies and targeting lists, the change revision
+// @Description and a timestamp. The base64 payload is signed with the server's Ed25519 key; verify it with pkg/snapshot.
+// @Tags evaluation
+// @Produce json
+// @Success 200 {object} snapshot.File "Signed feature flags snapshot"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/snapshot [get]
+func GetFeatureFlagsSnapshotAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	file, err := service.GetFeatureFlagsSnapshot()
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="dom-cobb-snapshot.json"`)
+	c.JSON(http.StatusOK, file)
+}
+
+// @Description Public key that verifies snapshot signatures
+type SnapshotPublicKeyData struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"`
+}
+
+// @Summary Get the snapshot public key
+// @Description Retrieve the base64 encoded Ed25519 public key that verifies snapshot files
+// @Tags evaluation
+// @Produce json
+// @Success 200 {object} api.SuccessResponse{data=SnapshotPublicKeyData} "Snapshot public key retrieved successfully"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/snapshot/public-key [get]
+func GetFeatureFlagsSnapshotPublicKeyAPI(c *gin.Context) {
+	service := newFeatureFlagService()
+
+	data, err := service.GetFeatureFlagsSnapshotPublicKey()
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Snapshot public key is retrieved successfully", data)
+}
+
+// @Description Query parameters for the feature flag changes request
+type GetFeatureFlagChangesQueryParams struct {
+	Since uint64 `form:"since"`
+	Wait  uint   `form:"wait" binding:"max=60"`
+}
+
+// @Description Feature flags changed after a revision
+type FeatureFlagChangesData struct {
+	Revision uint64             `json:"revision"`
+	Flags    []*evaluation.Flag `json:"flags"`
+	Deleted  []uint             `json:"deleted"`
+}
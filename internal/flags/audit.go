@@ -0,0 +1,73 @@
+package flags
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+)
+
+func newLogEntry(ctx context.Context, action logger.Action, flag *FeatureFlag, reason, message string) *logger.LogEntry {
+	return &logger.LogEntry{
+		SchemaVersion: logger.SchemaVersion,
+		Action:        action,
+		EntityType:    logger.EntityTypeFlag,
+		EntityID:      flag.ID,
+		Actor:         logger.GetActor(ctx),
+		Reason:        reason,
+		RequestID:     logger.GetRequestID(ctx),
+		Message:       message,
+		Timestamp:     time.Now(),
+	}
+}
+
+func newFlagState(flag *FeatureFlag, dependencies []uint) *logger.FlagState {
+	state := &logger.FlagState{
+		Name:         flag.Name,
+		IsActive:     flag.IsActive,
+		Dependencies: make([]uint, len(dependencies)),
+	}
+	copy(state.Dependencies, dependencies)
+	return state
+}
+
+func newFlagStateFromVersion(version *FlagVersion) *logger.FlagState {
+	return &logger.FlagState{
+		Name:         version.Name,
+		IsActive:     version.Active,
+		Dependencies: version.Dependencies,
+	}
+}
+
+func (s *Service) getFlagDependencyIds(flag *FeatureFlag) ([]uint, error) {
+	dependencies, err := s.Repo.GetFlagDependencies(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencyIds := make([]uint, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		dependencyIds = append(dependencyIds, dependency.ID)
+	}
+	return dependencyIds, nil
+}
+
+// logAutoDisabled writes one entry per dependent that the deactivation of
+// flag switched off. The change is already committed, so a dependent whose
+// dependencies cannot be read is logged without them.
+func (s *Service) logAutoDisabled(ctx context.Context, flag *FeatureFlag, autoDisabled []*FeatureFlag, reason string) {
+	entries := make([]*logger.LogEntry, 0, len(autoDisabled))
+	for _, dependent := range autoDisabled {
+		entry := newLogEntry(ctx, logger.ActionAutoDisabled, dependent, reason, "Flag is auto disabled")
+		entry.CausedBy = flag.ID
+
+		dependencyIds, err := s.primary().getFlagDependencyIds(dependent)
+		if err == nil {
+			entry.After = newFlagState(dependent, dependencyIds)
+			entry.Before = newFlagState(dependent, dependencyIds)
+			entry.Before.IsActive = true
+		}
+		entries = append(entries, entry)
+	}
+	s.Logger.LogBatch(entries)
+}
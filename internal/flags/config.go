@@ -0,0 +1,39 @@
+package flags
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/snapshot"
+)
+
+func IsSnapshotEnabled() (bool, error) {
+	enabledStr, exists := os.LookupEnv("SNAPSHOT_ENABLED")
+	if !exists {
+		return false, nil
+	}
+	return strconv.ParseBool(enabledStr)
+}
+
+func GetSnapshotRefreshInterval() (time.Duration, error) {
+	intervalStr, exists := os.LookupEnv("SNAPSHOT_REFRESH_INTERVAL")
+	if !exists {
+		return -1, fmt.Errorf("Snapshot refresh interval is undefined")
+	}
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil {
+		return -1, err
+	}
+	return time.Duration(interval), nil
+}
+
+func GetSnapshotSigningKey() (ed25519.PrivateKey, error) {
+	key, exists := os.LookupEnv("SNAPSHOT_SIGNING_KEY")
+	if !exists || key == "" {
+		return nil, fmt.Errorf("Snapshot signing key is undefined")
+	}
+	return snapshot.ParsePrivateKey(key)
+}
@@ -0,0 +1,610 @@
+package flags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Service) ValidateGetFeatureFlagQuery(c *gin.Context) (*GetFeatureFlagQueryParams, *api.APIError) {
+	var query GetFeatureFlagQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	return &query, nil
+}
+
+// GetFeatureFlagAsOf returns flag as it was at the given time, with the
+// dependents it had then.
+func (s *Service) GetFeatureFlagAsOf(flag *FeatureFlag, at time.Time) (*FeatureFlagData, *api.APIError) {
+	states, err := s.Repo.GetFlagStatesAt(at)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	for _, flagData := range buildFeatureFlagsDataAsOf(states) {
+		if flagData.ID == flag.ID {
+			return flagData, nil
+		}
+	}
+	return nil, api.NotFoundError("Feature flag has no history at this time", "")
+}
+
+// ListFeatureFlagsAsOf returns a page of the flags that existed at the
+// time of the query.
+func (s *Service) ListFeatureFlagsAsOf(query *ListFeatureFlagsQueryParams) (*ListFeatureFlagsData, *api.APIError) {
+	states, err := s.Repo.GetFlagStatesAt(query.AsOf)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	flags := buildFeatureFlagsDataAsOf(states)
+	total := uint(len(flags))
+	offset := min((query.Page-1)*query.Size, total)
+	end := min(offset+query.Size, total)
+
+	return &ListFeatureFlagsData{
+		Flags: flags[offset:end],
+		PaginationResponse: api.PaginationResponse{
+			Page:       query.Page,
+			Size:       query.Size,
+			Total:      total,
+			TotalPages: (total + query.Size - 1) / query.Size,
+		},
+	}, nil
+}
+
+func buildFeatureFlagsDataAsOf(states []*FlagVersion) []*FeatureFlagData {
+	dependentIDs := make(map[uint][]uint)
+	for _, state := range states {
+		for _, dependencyId := range state.Dependencies {
+			dependentIDs[dependencyId] = append(dependentIDs[dependencyId], state.FlagID)
+		}
+	}
+
+	flags := make([]*FeatureFlagData, 0, len(states))
+	for _, state := range states {
+		flagData := &FeatureFlagData{
+			ID:           state.FlagID,
+			Name:         state.Name,
+			Active:       state.Active,
+			Dependencies: state.Dependencies,
+			Dependents:   dependentIDs[state.FlagID],
+		}
+		if flagData.Dependencies == nil {
+			flagData.Dependencies = []uint{}
+		}
+		if flagData.Dependents == nil {
+			flagData.Dependents = []uint{}
+		}
+		flags = append(flags, flagData)
+	}
+	return flags
+}
+
+func (s *Service) ValidateGetFeatureFlagsDiffRequest(c *gin.Context) (*GetFeatureFlagsDiffQueryParams, *api.APIError) {
+	var query GetFeatureFlagsDiffQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	if query.From.IsZero() || query.To.IsZero() {
+		return nil, api.BadRequestError("Invalid input format", "from and to are required")
+	}
+	if !query.From.Before(query.To) {
+		return nil, api.BadRequestError("Invalid input format", "from must be before to")
+	}
+
+	return &query, nil
+}
+
+// GetFeatureFlagsDiff lists the flags whose state at to differs from their
+// state at from, with the versions in between that explain it. Flags that
+// changed and changed back are left out.
+func (s *Service) GetFeatureFlagsDiff(query *GetFeatureFlagsDiffQueryParams) (*FeatureFlagsDiffData, *api.APIError) {
+	before, err := s.Repo.GetFlagStatesAt(query.From)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	after, err := s.Repo.GetFlagStatesAt(query.To)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	versions, err := s.Repo.GetFlagVersionsBetween(query.From, query.To)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	beforeByFlagId := make(map[uint]*FlagVersion, len(before))
+	for _, state := range before {
+		beforeByFlagId[state.FlagID] = state
+	}
+	afterByFlagId := make(map[uint]*FlagVersion, len(after))
+	for _, state := range after {
+		afterByFlagId[state.FlagID] = state
+	}
+
+	data := &FeatureFlagsDiffData{
+		From:  query.From,
+		To:    query.To,
+		Flags: []*FeatureFlagDiff{},
+	}
+	diffByFlagId := make(map[uint]*FeatureFlagDiff)
+	for _, version := range versions {
+		diff, exists := diffByFlagId[version.FlagID]
+		if !exists {
+			diff = &FeatureFlagDiff{
+				ID:      version.FlagID,
+				Before:  newFeatureFlagStateData(beforeByFlagId[version.FlagID]),
+				After:   newFeatureFlagStateData(afterByFlagId[version.FlagID]),
+				Changes: []*FlagVersion{},
+			}
+			diffByFlagId[version.FlagID] = diff
+			if !sameFeatureFlagState(diff.Before, diff.After) {
+				data.Flags = append(data.Flags, diff)
+			}
+		}
+		diff.Changes = append(diff.Changes, version)
+	}
+
+	return data, nil
+}
+
+func sameFeatureFlagState(a, b *FeatureFlagStateData) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name && a.Active == b.Active && utils.SameElements(a.Dependencies, b.Dependencies)
+}
+
+func newFeatureFlagStateData(state *FlagVersion) *FeatureFlagStateData {
+	if state == nil {
+		return nil
+	}
+
+	stateData := &FeatureFlagStateData{
+		Name:         state.Name,
+		Active:       state.Active,
+		Dependencies: state.Dependencies,
+	}
+	if stateData.Dependencies == nil {
+		stateData.Dependencies = []uint{}
+	}
+	return stateData
+}
+
+func (s *Service) ValidateListFeatureFlagVersionsRequest(
+	c *gin.Context,
+) (
+	*FeatureFlag,
+	*ListFeatureFlagVersionsQueryParams,
+	*api.APIError,
+) {
+	var query ListFeatureFlagVersionsQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	flag, apiErr := s.ValidateGetFeatureFlagRequest(c)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	return flag, &query, nil
+}
+
+func (s *Service) ListFeatureFlagVersions(
+	flag *FeatureFlag,
+	query *ListFeatureFlagVersionsQueryParams,
+) (
+	*ListFeatureFlagVersionsData,
+	*api.APIError,
+) {
+	versions, total, err := s.Repo.GetFlagVersions(flag, int((query.Page-1)*query.Size), int(query.Size))
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	return &ListFeatureFlagVersionsData{
+		Versions: versions,
+		PaginationResponse: api.PaginationResponse{
+			Page:       query.Page,
+			Size:       query.Size,
+			Total:      total,
+			TotalPages: (total + query.Size - 1) / query.Size,
+		},
+	}, nil
+}
+
+func (s *Service) getFeatureFlagVersion(flag *FeatureFlag, version uint) (*FlagVersion, *api.APIError) {
+	flagVersion, err := s.Repo.GetFlagVersion(flag, version)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if flagVersion == nil {
+		return nil, api.NotFoundError("Invalid flag version", "")
+	}
+
+	return flagVersion, nil
+}
+
+func (s *Service) ValidateGetFeatureFlagVersionsDiffRequest(
+	c *gin.Context,
+) (
+	*FeatureFlag,
+	*GetFeatureFlagVersionsDiffQueryParams,
+	*api.APIError,
+) {
+	var query GetFeatureFlagVersionsDiffQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	flag, apiErr := s.ValidateGetFeatureFlagRequest(c)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	return flag, &query, nil
+}
+
+func (s *Service) GetFeatureFlagVersionsDiff(
+	flag *FeatureFlag,
+	query *GetFeatureFlagVersionsDiffQueryParams,
+) (
+	*FeatureFlagVersionsDiffData,
+	*api.APIError,
+) {
+	from, apiErr := s.getFeatureFlagVersion(flag, query.From)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	to, apiErr := s.getFeatureFlagVersion(flag, query.To)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &FeatureFlagVersionsDiffData{
+		From:                from,
+		To:                  to,
+		ActiveChanged:       from.Active != to.Active,
+		AddedDependencies:   difference(to.Dependencies, from.Dependencies),
+		RemovedDependencies: difference(from.Dependencies, to.Dependencies),
+	}, nil
+}
+
+// difference returns the ids of a that are not in b.
+func difference(a, b []uint) []uint {
+	inB := make(map[uint]struct{}, len(b))
+	for _, id := range b {
+		inB[id] = struct{}{}
+	}
+
+	ids := []uint{}
+	for _, id := range a {
+		if _, exists := inB[id]; !exists {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s *Service) ValidateRollbackFeatureFlagRequest(
+	c *gin.Context,
+) (
+	*FeatureFlag,
+	*FlagVersion,
+	*RollbackFeatureFlagRequest,
+	*api.APIError,
+) {
+	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	var req RollbackFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	flag, version, apiErr := s.ValidateRollbackFeatureFlag(uint(flagId), &req)
+	if apiErr != nil {
+		return nil, nil, nil, apiErr
+	}
+
+	return flag, version, &req, nil
+}
+
+// ValidateRollbackFeatureFlag checks the state and dependencies of the
+// requested version like a toggle and a dependencies update would.
+// Targets are restored as they were recorded and need no checks.
+func (s *Service) ValidateRollbackFeatureFlag(
+	flagId uint,
+	req *RollbackFeatureFlagRequest,
+) (
+	*FeatureFlag,
+	*FlagVersion,
+	*api.APIError,
+) {
+	primary := s.primary()
+	flag, apiErr := primary.GetFeatureFlagById(flagId)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	version, apiErr := primary.getFeatureFlagVersion(flag, req.Version)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	dependencyIds, err := primary.getFlagDependencyIds(flag)
+	if err != nil {
+		return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	targetsRestored, apiErr := primary.hasFlagTargetsOf(flag, version)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+	if version.Active == flag.IsActive && utils.SameElements(dependencyIds, version.Dependencies) && targetsRestored {
+		return nil, nil, api.OKError(fmt.Sprintf("Flag is already at version %d", version.Version), "")
+	}
+
+	if apiErr := primary.validateFlagDependencies(flag, version.Dependencies, version.Active); apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	return flag, version, nil
+}
+
+// hasFlagTargetsOf reports whether flag has the targets version recorded,
+// true for versions that recorded none.
+func (s *Service) hasFlagTargetsOf(flag *FeatureFlag, version *FlagVersion) (bool, *api.APIError) {
+	if !version.HasTargets() {
+		return true, nil
+	}
+
+	targets, apiErr := s.GetFeatureFlagTargets(flag)
+	if apiErr != nil {
+		return false, apiErr
+	}
+	return utils.SameElements(targets.Allow, version.Allow) && utils.SameElements(targets.Deny, version.Deny), nil
+}
+
+func (s *Service) RollbackFeatureFlag(
+	ctx context.Context,
+	flag *FeatureFlag,
+	version *FlagVersion,
+	req *RollbackFeatureFlagRequest,
+) *api.APIError {
+	dependencyIds, err := s.primary().getFlagDependencyIds(flag)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+	before := newFlagState(flag, dependencyIds)
+
+	autoDisabled, err := s.Repo.RollbackFlag(ctx, flag, version, req.Reason)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	entry := newLogEntry(ctx, logger.ActionRolledBack, flag, req.Reason, "Feature Flag is rolled back successfully")
+	entry.Before = before
+	entry.After = newFlagState(flag, version.Dependencies)
+	entry.Metadata = map[string]any{"version": version.Version}
+	s.Logger.Log(entry)
+	s.logAutoDisabled(ctx, flag, autoDisabled, req.Reason)
+
+	return nil
+}
+
+func parseFlagChangeId(c *gin.Context) (uint64, *api.APIError) {
+	changeId, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, api.BadRequestError("Invalid input format", err.Error())
+	}
+	return changeId, nil
+}
+
+func (s *Service) getFlagChange(changeId uint64) (*FlagChange, *api.APIError) {
+	change, err := s.Repo.GetFlagChange(changeId)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if change == nil {
+		return nil, api.NotFoundError("Invalid change id", "")
+	}
+
+	return change, nil
+}
+
+func (s *Service) ValidateGetFlagChangeRequest(c *gin.Context) (*FlagChange, *api.APIError) {
+	changeId, apiErr := parseFlagChangeId(c)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	return s.getFlagChange(changeId)
+}
+
+func (s *Service) GetFlagChange(change *FlagChange) (*FlagChangeData, *api.APIError) {
+	versions, err := s.Repo.GetFlagChangeVersions(change)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	return &FlagChangeData{Change: change, Versions: versions}, nil
+}
+
+func (s *Service) ValidateUndoFlagChangeRequest(
+	c *gin.Context,
+) (
+	*FlagChange,
+	[]*FlagVersion,
+	*UndoFlagChangeRequest,
+	*api.APIError,
+) {
+	changeId, apiErr := parseFlagChangeId(c)
+	if apiErr != nil {
+		return nil, nil, nil, apiErr
+	}
+	var req UndoFlagChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	change, versions, apiErr := s.ValidateUndoFlagChange(changeId)
+	if apiErr != nil {
+		return nil, nil, nil, apiErr
+	}
+
+	return change, versions, &req, nil
+}
+
+// ValidateUndoFlagChange checks that every flag of the change is still at
+// the last version the change wrote for it, and validates the versions
+// before the change together: a flag restored to active may depend on a
+// flag the same undo restores to active.
+func (s *Service) ValidateUndoFlagChange(changeId uint64) (*FlagChange, []*FlagVersion, *api.APIError) {
+	primary := s.primary()
+	change, apiErr := primary.getFlagChange(changeId)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	versions, err := primary.Repo.GetFlagChangeVersions(change)
+	if err != nil {
+		return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	changedFlags := getChangedFlags(versions)
+	flagsById := make(map[uint]*FeatureFlag, len(changedFlags))
+	priors := make([]*FlagVersion, 0, len(changedFlags))
+	restoredActive := make(map[uint]bool, len(changedFlags))
+	for _, changed := range changedFlags {
+		flag, apiErr := primary.GetFeatureFlagById(changed.last.FlagID)
+		if apiErr != nil {
+			return nil, nil, apiErr
+		}
+		flagsById[flag.ID] = flag
+
+		latest, _, err := primary.Repo.GetFlagVersions(flag, 0, 1)
+		if err != nil {
+			return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+		}
+		if len(latest) == 0 || latest[0].Version != changed.last.Version {
+			return nil, nil, api.ConflictError(
+				"Change is superseded",
+				fmt.Sprintf("Feature flag %d was changed after the change", flag.ID),
+			)
+		}
+
+		if changed.first.Version <= 1 {
+			return nil, nil, api.BadRequestError(
+				"Change cannot be undone",
+				fmt.Sprintf("Feature flag %d has no version before the change", flag.ID),
+			)
+		}
+		prior, apiErr := primary.getFeatureFlagVersion(flag, changed.first.Version-1)
+		if apiErr != nil {
+			return nil, nil, apiErr
+		}
+		priors = append(priors, prior)
+		restoredActive[flag.ID] = prior.Active
+	}
+
+	for i, prior := range priors {
+		flag := flagsById[prior.FlagID]
+		if !utils.SameElements(changedFlags[i].last.Dependencies, prior.Dependencies) {
+			if apiErr := primary.validateFlagDependencies(flag, prior.Dependencies, false); apiErr != nil {
+				return nil, nil, apiErr
+			}
+		}
+		if !prior.Active || len(prior.Dependencies) == 0 {
+			continue
+		}
+
+		dependencyFlags, err := primary.Repo.GetFlagByIds(prior.Dependencies)
+		if err != nil {
+			return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+		}
+		var inactiveIds []uint
+		for _, dependencyFlag := range dependencyFlags {
+			active, restored := restoredActive[dependencyFlag.ID]
+			if !restored {
+				active = dependencyFlag.IsActive
+			}
+			if !active {
+				inactiveIds = append(inactiveIds, dependencyFlag.ID)
+			}
+		}
+		if len(inactiveIds) > 0 {
+			return nil, nil, api.BadRequestError(
+				"Dependency validation failed",
+				fmt.Sprintf("Active feature flag cannot depend on inactive flags. Inactive dependency IDs: %v", inactiveIds),
+			)
+		}
+	}
+
+	return change, versions, nil
+}
+
+// UndoFlagChange restores the flags of change to their versions before it.
+// versions are the versions the change wrote, the state logged as before.
+func (s *Service) UndoFlagChange(
+	ctx context.Context,
+	change *FlagChange,
+	versions []*FlagVersion,
+	req *UndoFlagChangeRequest,
+) (*FlagChangeData, *api.APIError) {
+	undo, undone, err := s.Repo.UndoFlagChange(ctx, change, req.Reason)
+	switch {
+	case errors.Is(err, ErrChangeSuperseded):
+		return nil, api.ConflictError("Change is superseded", err.Error())
+	case errors.Is(err, ErrChangeNotUndoable):
+		return nil, api.BadRequestError("Change cannot be undone", err.Error())
+	case err != nil:
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	before := make(map[uint]*FlagVersion, len(versions))
+	for _, version := range versions {
+		before[version.FlagID] = version
+	}
+
+	entries := make([]*logger.LogEntry, 0, len(undone))
+	for _, version := range undone {
+		flag := &FeatureFlag{Name: version.Name, IsActive: version.Active}
+		flag.ID = version.FlagID
+
+		var entry *logger.LogEntry
+		if version.Change == FlagVersionUndone {
+			entry = newLogEntry(ctx, logger.ActionUndone, flag, req.Reason, "Feature Flag change is undone successfully")
+			if prior, exists := before[version.FlagID]; exists {
+				entry.Before = newFlagStateFromVersion(prior)
+			}
+			entry.Metadata = map[string]any{"version": version.RolledBackTo}
+		} else {
+			entry = newLogEntry(ctx, logger.ActionAutoDisabled, flag, req.Reason, "Flag is auto disabled")
+			entry.CausedBy = version.CausedBy
+			entry.Before = newFlagStateFromVersion(version)
+			entry.Before.IsActive = true
+			entry.Metadata = map[string]any{}
+		}
+		entry.After = newFlagStateFromVersion(version)
+		entry.Metadata["change_id"] = undo.ID
+		entry.Metadata["undoes"] = change.ID
+		entries = append(entries, entry)
+	}
+	s.Logger.LogBatch(entries)
+
+	return &FlagChangeData{Change: undo, Versions: undone}, nil
+}
@@ -18,6 +18,86 @@ type FlagDependency struct {
 	CreatedAt       time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
 }
 
+const (
+	TargetListAllow = "allow"
+	TargetListDeny  = "deny"
+)
+
+type FlagTarget struct {
+	FlagID       uint      `gorm:"primaryKey;not null" json:"flag_id"`
+	TargetingKey string    `gorm:"primaryKey;size:255;not null" json:"targeting_key"`
+	List         string    `gorm:"size:8;not null" json:"list"`
+	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+const (
+	FlagEventCreated      = "created"
+	FlagEventToggled      = "toggled"
+	FlagEventAutoDisabled = "auto_disabled"
+	FlagEventTargets      = "targets_changed"
+	FlagEventDependencies = "dependencies_changed"
+)
+
+type FlagEvent struct {
+	Revision     uint64 `gorm:"primaryKey;autoIncrement" json:"revision"`
+	Type         string `gorm:"size:32;not null" json:"type"`
+	FlagID       uint   `gorm:"not null;index" json:"flag_id"`
+	Name         string `gorm:"size:255;not null" json:"name"`
+	Active       bool   `gorm:"not null" json:"active"`
+	Dependencies []uint `gorm:"type:jsonb;serializer:json" json:"dependencies,omitempty"`
+	// PreviousDependencies are the dependencies a dependencies_changed
+	// event replaced.
+	PreviousDependencies []uint    `gorm:"type:jsonb;serializer:json" json:"previous_dependencies,omitempty"`
+	Reason               string    `gorm:"size:255;not null;default:''" json:"reason,omitempty"`
+	Actor                string    `gorm:"size:255;not null;default:''" json:"actor,omitempty"`
+	CausedBy             uint      `gorm:"not null;default:0" json:"caused_by,omitempty"`
+	CreatedAt            time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+const (
+	// FlagVersionImported is the baseline version of flags that existed
+	// before versions were recorded.
+	FlagVersionImported   = "imported"
+	FlagVersionRolledBack = "rolled_back"
+	FlagVersionUndone     = "undone"
+)
+
+// FlagChange is one mutation of the flags, the change set of the versions
+// it wrote: the flag it was made to and every dependent it auto-disabled.
+type FlagChange struct {
+	ID     uint64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type   string `gorm:"size:32;not null" json:"type"`
+	FlagID uint   `gorm:"not null" json:"flag_id"`
+	Reason string `gorm:"size:255;not null;default:''" json:"reason"`
+	Actor  string `gorm:"size:255;not null;default:''" json:"actor"`
+	// Undoes is the change an undo reverted.
+	Undoes    uint64    `gorm:"not null;default:0" json:"undoes,omitempty"`
+	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// FlagVersion is the state of a flag after a change. Versions are numbered
+// per flag; the other change types are the FlagEvent types.
+type FlagVersion struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement" json:"-"`
+	ChangeID     uint64 `gorm:"not null;default:0;index" json:"change_id,omitempty"`
+	FlagID       uint   `gorm:"not null;uniqueIndex:idx_flag_versions_flag_id_version" json:"flag_id"`
+	Version      uint   `gorm:"not null;uniqueIndex:idx_flag_versions_flag_id_version" json:"version"`
+	Change       string `gorm:"size:32;not null" json:"change"`
+	Name         string `gorm:"size:255;not null" json:"name"`
+	Active       bool   `gorm:"not null" json:"active"`
+	Dependencies []uint `gorm:"type:jsonb;serializer:json;not null" json:"dependencies"`
+	// Allow and Deny are the targeting lists of the flag, nil in versions
+	// recorded before the lists were versioned.
+	Allow        []string  `gorm:"type:jsonb;serializer:json" json:"allow"`
+	Deny         []string  `gorm:"type:jsonb;serializer:json" json:"deny"`
+	Reason       string    `gorm:"size:255;not null;default:''" json:"reason"`
+	Actor        string    `gorm:"size:255;not null;default:''" json:"actor"`
+	CausedBy     uint      `gorm:"not null;default:0" json:"caused_by,omitempty"`
+	RolledBackTo uint      `gorm:"not null;default:0" json:"rolled_back_to,omitempty"`
+	Revision     uint64    `gorm:"not null;default:0" json:"revision"`
+	CreatedAt    time.Time `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
 func (FeatureFlag) TableName() string {
 	return "feature_flags"
 }
@@ -25,3 +105,25 @@ func (FeatureFlag) TableName() string {
 func (FlagDependency) TableName() string {
 	return "flag_dependencies"
 }
+
+func (FlagTarget) TableName() string {
+	return "flag_targets"
+}
+
+func (FlagEvent) TableName() string {
+	return "flag_events"
+}
+
+func (FlagVersion) TableName() string {
+	return "flag_versions"
+}
+
+// HasTargets reports whether version recorded the targeting lists of its
+// flag.
+func (v *FlagVersion) HasTargets() bool {
+	return v.Allow != nil || v.Deny != nil
+}
+
+func (FlagChange) TableName() string {
+	return "flag_changes"
+}
@@ -4,29 +4,81 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/mongodb"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
-	"go.mongodb.org/mongo-driver/bson"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/metrics"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IRepository interface {
+	// Primary returns the repository that reads from Postgres itself, never
+	// from a snapshot, for the checks of writes.
+	Primary() IRepository
 	GetFlagByName(name string) (*FeatureFlag, error)
 	GetFlagByIds(flagIds []uint) ([]*FeatureFlag, error)
 	GetFlagById(flagId uint) (*FeatureFlag, error)
 	GetFlagDependencies(flag *FeatureFlag) ([]*FeatureFlag, error)
 	GetFlagDependents(flag *FeatureFlag) ([]*FeatureFlag, error)
-	GetFeatureFlagLogs(flag *FeatureFlag, page, size uint) ([]*logger.LogEntry, uint, uint, error)
-	CreateFlag(name string, active bool, dependecnyFlagIds []uint) (*FeatureFlag, error)
-	UpdateFlag(flag *FeatureFlag, active bool) error
+	GetFeatureFlagLogs(flag *FeatureFlag, filter *logger.Filter, page, size uint) ([]*logger.LogEntry, uint, uint, error)
+	GetFeatureFlagLogsPage(
+		flag *FeatureFlag,
+		filter *logger.Filter,
+		cursor *logger.PageCursor,
+		size uint,
+	) (*logger.LogPage, error)
+	CountFeatureFlagLogs(flag *FeatureFlag, filter *logger.Filter) (uint, error)
+	CreateFlag(ctx context.Context, name string, active bool, dependecnyFlagIds []uint) (*FeatureFlag, error)
+	UpdateFlag(ctx context.Context, flag *FeatureFlag, active bool, reason string) ([]*FeatureFlag, error)
+	GetAllFlags() ([]*FeatureFlag, error)
+	GetAllDependencies() ([]*FlagDependency, error)
+	GetAllFlagTargets() ([]*FlagTarget, error)
+	GetAllFlagTargetsByKey(targetingKey string) ([]*FlagTarget, error)
+	GetAllTransitiveDependencies(flag *FeatureFlag) ([]*FeatureFlag, error)
+	GetDependenciesByFlagIds(flagIds []uint) ([]*FlagDependency, error)
+	GetFlagTargets(flag *FeatureFlag) ([]*FlagTarget, error)
+	GetFlagTargetsByKey(flagIds []uint, targetingKey string) ([]*FlagTarget, error)
+	AddFlagTargets(ctx context.Context, flag *FeatureFlag, list string, targetingKeys []string) error
+	RemoveFlagTargets(ctx context.Context, flag *FeatureFlag, list string, targetingKeys []string) (uint, error)
+	GetLatestFlagEventRevision() (uint64, error)
+	GetFlagEventsSince(revision uint64, limit int) ([]*FlagEvent, error)
+	GetChangedFlagIds(since, until uint64) ([]uint, error)
+	GetFlagTargetsByFlagIds(flagIds []uint) ([]*FlagTarget, error)
+	GetFlags(offset, limit int) ([]*FeatureFlag, uint, error)
+	UpdateFlagDependencies(ctx context.Context, flag *FeatureFlag, dependencyFlagIds []uint, reason string) error
+	GetFlagStatesAt(at time.Time) ([]*FlagVersion, error)
+	GetFlagVersionsBetween(from, to time.Time) ([]*FlagVersion, error)
+	GetFlagVersions(flag *FeatureFlag, offset, limit int) ([]*FlagVersion, uint, error)
+	GetFlagVersion(flag *FeatureFlag, version uint) (*FlagVersion, error)
+	RollbackFlag(ctx context.Context, flag *FeatureFlag, version *FlagVersion, reason string) ([]*FeatureFlag, error)
+	GetFlagChange(changeId uint64) (*FlagChange, error)
+	GetFlagChangeVersions(change *FlagChange) ([]*FlagVersion, error)
+	UndoFlagChange(ctx context.Context, change *FlagChange, reason string) (*FlagChange, []*FlagVersion, error)
 }
 
+var (
+	// ErrChangeSuperseded is returned when undoing a change one of whose
+	// flags was changed again since.
+	ErrChangeSuperseded = errors.New("a flag of the change was changed since")
+	// ErrChangeNotUndoable is returned when undoing a change without a
+	// prior version to restore, such as the creation of a flag.
+	ErrChangeNotUndoable = errors.New("the change has no prior version to restore")
+)
+
+const (
+	targetBatchSize = 1000
+	// flagWritersLockKey serializes flag writers so revisions become
+	// visible in order and readers polling "revision > n" never skip one.
+	flagWritersLockKey = 0x636f6262
+)
+
 type Repository struct {
 	db         *gorm.DB
 	collection *mongo.Collection
@@ -43,14 +95,58 @@ func GetRepository() IRepository {
 		if err != nil {
 			panic("Failed to get logger collection: " + err.Error())
 		}
-		repo = &Repository{
-			db:         postgres.GetDB(),
-			collection: mongodb.GetCollection(loggerCollection),
+		repo = NewRepository(postgres.GetDB(), mongodb.GetCollection(loggerCollection))
+
+		snapshotEnabled, err := IsSnapshotEnabled()
+		if err != nil {
+			panic("Failed to get snapshot config: " + err.Error())
+		}
+		if snapshotEnabled {
+			repo = newCachedRepository(repo)
 		}
 	})
 	return repo
 }
 
+func NewRepository(db *gorm.DB, collection *mongo.Collection) *Repository {
+	return &Repository{
+		db:         db,
+		collection: collection,
+	}
+}
+
+func (r *Repository) Primary() IRepository {
+	return r
+}
+
+func newCachedRepository(repo IRepository) *CachedRepository {
+	refreshInterval, err := GetSnapshotRefreshInterval()
+	if err != nil {
+		panic("Failed to get snapshot refresh interval: " + err.Error())
+	}
+	cached, err := NewCachedRepository(repo, refreshInterval*time.Second)
+	if err != nil {
+		panic("Failed to load flag snapshot: " + err.Error())
+	}
+
+	notifications, _ := postgres.GetListener().Subscribe()
+	go cached.Run(nil, notifications)
+
+	metrics.RegisterGauge("dom_cobb_snapshot_age_seconds", "Seconds since the flag snapshot was last confirmed current.", func() float64 {
+		return cached.Age().Seconds()
+	})
+	metrics.RegisterGauge("dom_cobb_snapshot_revision", "Flag event revision of the loaded snapshot.", func() float64 {
+		return float64(cached.Snapshot().Revision)
+	})
+	metrics.RegisterGauge("dom_cobb_snapshot_sync_failures", "Number of failed flag snapshot syncs.", func() float64 {
+		return float64(cached.SyncFailures())
+	})
+	metrics.RegisterGauge("dom_cobb_snapshot_flags", "Number of flags in the loaded snapshot.", func() float64 {
+		return float64(len(cached.Snapshot().flags))
+	})
+	return cached
+}
+
 func (r *Repository) GetFlagByName(name string) (*FeatureFlag, error) {
 	var flag FeatureFlag
 	err := r.db.Where("name = ?", name).First(&flag).Error
@@ -116,35 +212,48 @@ func (r *Repository) GetFlagDependents(flag *FeatureFlag) ([]*FeatureFlag, error
 	return flags, nil
 }
 
-func (r *Repository) CreateFlag(name string, active bool, dependecnyFlagIds []uint) (*FeatureFlag, error) {
+func (r *Repository) CreateFlag(ctx context.Context, name string, active bool, dependecnyFlagIds []uint) (*FeatureFlag, error) {
 	flag := FeatureFlag{
 		Name:     name,
 		IsActive: active,
 	}
 
-	if len(dependecnyFlagIds) == 0 {
-		err := r.db.Create(&flag).Error
-		return &flag, err
-	}
-
 	tx := r.db.Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
+	if err := lockFlagWriters(tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
 
 	if err := tx.Create(&flag).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	var dependencyFlags []FlagDependency
-	for _, depFlagID := range dependecnyFlagIds {
-		dependencyFlags = append(dependencyFlags, FlagDependency{
-			FlagID:          flag.ID,
-			DependsOnFlagID: depFlagID,
-		})
+	if len(dependecnyFlagIds) > 0 {
+		var dependencyFlags []FlagDependency
+		for _, depFlagID := range dependecnyFlagIds {
+			dependencyFlags = append(dependencyFlags, FlagDependency{
+				FlagID:          flag.ID,
+				DependsOnFlagID: depFlagID,
+			})
+		}
+		if err := tx.Create(&dependencyFlags).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
-	if err := tx.Create(&dependencyFlags).Error; err != nil {
+
+	event := newFlagEvent(FlagEventCreated, &flag, active)
+	event.Dependencies = dependecnyFlagIds
+	if err := createFlagEvents(ctx, tx, event); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := createFlagChange(tx, newFlagChange(ctx, FlagEventCreated, flag.ID, ""), newFlagVersion(event)); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -157,77 +266,142 @@ func (r *Repository) CreateFlag(name string, active bool, dependecnyFlagIds []ui
 	return &flag, nil
 }
 
-func (r *Repository) UpdateFlag(flag *FeatureFlag, active bool) error {
+// UpdateFlag sets the state of flag. Deactivating it also deactivates its
+// transitive dependents; the ones that were active are returned.
+func (r *Repository) UpdateFlag(ctx context.Context, flag *FeatureFlag, active bool, reason string) ([]*FeatureFlag, error) {
 	if active {
-		return r.activateFlag(flag)
+		return nil, r.activateFlag(ctx, flag, reason)
 	}
-	return r.deactivateFlag(flag)
+	return r.deactivateFlag(ctx, flag, reason)
 }
 
-func (r *Repository) activateFlag(flag *FeatureFlag) error {
-	err := r.db.Model(flag).Update("is_active", true).Error
+func (r *Repository) activateFlag(ctx context.Context, flag *FeatureFlag, reason string) error {
+	tx := r.db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if err := lockFlagWriters(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err := tx.Model(flag).Update("is_active", true).Error
 	if err != nil {
+		tx.Rollback()
 		return err
 	}
+
+	event := newFlagEvent(FlagEventToggled, flag, true)
+	event.Reason = reason
+	err = createFlagEvents(ctx, tx, event)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = createFlagChange(tx, newFlagChange(ctx, FlagEventToggled, flag.ID, reason), newFlagVersion(event))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
 	flag.IsActive = true
 	return nil
 }
 
-func (r *Repository) deactivateFlag(flag *FeatureFlag) error {
+func (r *Repository) deactivateFlag(ctx context.Context, flag *FeatureFlag, reason string) ([]*FeatureFlag, error) {
 	tx := r.db.Begin()
 	if tx.Error != nil {
-		return tx.Error
+		return nil, tx.Error
+	}
+	if err := lockFlagWriters(tx); err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	allTransitiveDependents, err := r.getAllTransitiveDependents(flag)
+	events, autoDisabled, err := deactivateFlagAndDependents(tx, flag, reason)
 	if err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
-	flagIDs := []uint{flag.ID}
-	for _, dependent := range allTransitiveDependents {
-		flagIDs = append(flagIDs, dependent.ID)
+	err = createFlagEvents(ctx, tx, events...)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
-	err = tx.Model(&FeatureFlag{}).Where("id IN ? AND is_active = true", flagIDs).Update("is_active", false).Error
+	versions := make([]*FlagVersion, 0, len(events))
+	for _, event := range events {
+		versions = append(versions, newFlagVersion(event))
+	}
+	err = createFlagChange(tx, newFlagChange(ctx, FlagEventToggled, flag.ID, reason), versions...)
 	if err != nil {
 		tx.Rollback()
-		return err
+		return nil, err
 	}
 
 	err = tx.Commit().Error
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	logEntries := make([]*logger.LogEntry, 0, len(allTransitiveDependents))
-	for _, flagDependent := range allTransitiveDependents {
-		flagDependent.IsActive = false
-		logEntries = append(logEntries, &logger.LogEntry{
-			Message: "Flag is auto disabled",
-			Metadata: map[string]any{
-				"flag_id":           flagDependent.ID,
-				"dependecy_flag_id": flag.ID,
-			},
-			Timestamp: time.Now(),
-		})
+	for _, dependent := range autoDisabled {
+		dependent.IsActive = false
 	}
-	logger.NewService().LogBatch(logEntries)
-
 	flag.IsActive = false
-	return nil
+	return autoDisabled, nil
+}
+
+// deactivateFlagAndDependents switches flag and its transitive dependents
+// off in tx. It returns the events to record, the toggle of flag first,
+// and the dependents that were active.
+func deactivateFlagAndDependents(tx *gorm.DB, flag *FeatureFlag, reason string) ([]*FlagEvent, []*FeatureFlag, error) {
+	allTransitiveDependents, err := getAllTransitiveDependents(tx, flag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flagIDs := []uint{flag.ID}
+	events := []*FlagEvent{newFlagEvent(FlagEventToggled, flag, false)}
+	autoDisabled := make([]*FeatureFlag, 0, len(allTransitiveDependents))
+	for _, dependent := range allTransitiveDependents {
+		flagIDs = append(flagIDs, dependent.ID)
+		if dependent.IsActive {
+			event := newFlagEvent(FlagEventAutoDisabled, dependent, false)
+			event.CausedBy = flag.ID
+			events = append(events, event)
+			autoDisabled = append(autoDisabled, dependent)
+		}
+	}
+	for _, event := range events {
+		event.Reason = reason
+	}
+
+	err = tx.Model(&FeatureFlag{}).Where("id IN ? AND is_active = true", flagIDs).Update("is_active", false).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return events, autoDisabled, nil
 }
 
-func (r *Repository) getAllTransitiveDependents(flag *FeatureFlag) ([]*FeatureFlag, error) {
+// getAllTransitiveDependents returns every flag that depends on flag,
+// directly or through other flags, once, however many paths reach it.
+func getAllTransitiveDependents(db *gorm.DB, flag *FeatureFlag) ([]*FeatureFlag, error) {
 	var dependentFlags []*FeatureFlag
-	err := r.db.Raw(`
+	err := db.Raw(`
 		WITH RECURSIVE dependents AS (
 			SELECT flag_id as id
 			FROM flag_dependencies 
 			WHERE depends_on_flag_id = ?
-			
-			UNION ALL
+
+			UNION
 
 			SELECT fd.flag_id as id
 			FROM flag_dependencies fd
@@ -240,36 +414,879 @@ func (r *Repository) getAllTransitiveDependents(flag *FeatureFlag) ([]*FeatureFl
 	return dependentFlags, err
 }
 
-func (r *Repository) GetFeatureFlagLogs(flag *FeatureFlag, page, size uint) ([]*logger.LogEntry, uint, uint, error) {
-	ctx := context.Background()
+func (r *Repository) GetAllFlags() ([]*FeatureFlag, error) {
+	var flags []*FeatureFlag
+	err := r.db.Order("id").Find(&flags).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+func (r *Repository) GetAllDependencies() ([]*FlagDependency, error) {
+	var dependencies []*FlagDependency
+	err := r.db.Find(&dependencies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+func (r *Repository) GetAllFlagTargets() ([]*FlagTarget, error) {
+	var targets []*FlagTarget
+	err := r.db.Order("flag_id, targeting_key").Find(&targets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func (r *Repository) GetAllFlagTargetsByKey(targetingKey string) ([]*FlagTarget, error) {
+	var targets []*FlagTarget
+	err := r.db.Where("targeting_key = ?", targetingKey).Find(&targets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func (r *Repository) GetAllTransitiveDependencies(flag *FeatureFlag) ([]*FeatureFlag, error) {
+	var dependencyFlags []*FeatureFlag
+	err := r.db.Raw(`
+		WITH RECURSIVE dependencies AS (
+			SELECT depends_on_flag_id as id
+			FROM flag_dependencies
+			WHERE flag_id = ?
+
+			UNION
+
+			SELECT fd.depends_on_flag_id as id
+			FROM flag_dependencies fd
+			INNER JOIN dependencies d ON fd.flag_id = d.id
+		)
+		SELECT f.* FROM feature_flags f
+		INNER JOIN dependencies d ON f.id = d.id
+		WHERE f.deleted_at IS NULL
+		ORDER BY f.id
+	`, flag.ID).Scan(&dependencyFlags).Error
+	return dependencyFlags, err
+}
+
+func (r *Repository) GetDependenciesByFlagIds(flagIds []uint) ([]*FlagDependency, error) {
+	var dependencies []*FlagDependency
+	err := r.db.Where("flag_id IN ?", flagIds).Find(&dependencies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return dependencies, nil
+}
+
+func (r *Repository) GetFlagTargets(flag *FeatureFlag) ([]*FlagTarget, error) {
+	var targets []*FlagTarget
+	err := r.db.Where("flag_id = ?", flag.ID).Order("targeting_key").Find(&targets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func (r *Repository) GetFlagTargetsByKey(flagIds []uint, targetingKey string) ([]*FlagTarget, error) {
+	var targets []*FlagTarget
+	err := r.db.Where("targeting_key = ? AND flag_id IN ?", targetingKey, flagIds).Find(&targets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func (r *Repository) AddFlagTargets(ctx context.Context, flag *FeatureFlag, list string, targetingKeys []string) error {
+	targets := make([]*FlagTarget, 0, len(targetingKeys))
+	for _, targetingKey := range targetingKeys {
+		targets = append(targets, &FlagTarget{
+			FlagID:       flag.ID,
+			TargetingKey: targetingKey,
+			List:         list,
+		})
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockFlagWriters(tx); err != nil {
+			return err
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "flag_id"}, {Name: "targeting_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"list"}),
+		}).CreateInBatches(targets, targetBatchSize).Error
+		if err != nil {
+			return err
+		}
+
+		return createFlagTargetsChange(ctx, tx, flag)
+	})
+}
+
+func (r *Repository) RemoveFlagTargets(
+	ctx context.Context,
+	flag *FeatureFlag,
+	list string,
+	targetingKeys []string,
+) (uint, error) {
+	var removed uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockFlagWriters(tx); err != nil {
+			return err
+		}
+
+		for _, chunk := range utils.Chunk(targetingKeys, targetBatchSize) {
+			result := tx.Where("flag_id = ? AND list = ? AND targeting_key IN ?", flag.ID, list, chunk).
+				Delete(&FlagTarget{})
+			if result.Error != nil {
+				return result.Error
+			}
+			removed += uint(result.RowsAffected)
+		}
+		if removed == 0 {
+			return nil
+		}
+
+		return createFlagTargetsChange(ctx, tx, flag)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
+
+// createFlagTargetsChange records a change of the targeting lists of flag
+// with the version holding them.
+func createFlagTargetsChange(ctx context.Context, tx *gorm.DB, flag *FeatureFlag) error {
+	event := newFlagEvent(FlagEventTargets, flag, flag.IsActive)
+	if err := createFlagEvents(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return createFlagChange(tx, newFlagChange(ctx, FlagEventTargets, flag.ID, ""), newFlagVersion(event))
+}
+
+func newFlagEvent(eventType string, flag *FeatureFlag, active bool) *FlagEvent {
+	return &FlagEvent{
+		Type:   eventType,
+		FlagID: flag.ID,
+		Name:   flag.Name,
+		Active: active,
+	}
+}
+
+// lockFlagWriters takes the lock serializing flag writers. Every write
+// transaction takes it first, before it locks any row, so writers queue on
+// it alone and cannot deadlock on each other's rows.
+func lockFlagWriters(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?)", flagWritersLockKey).Error
+}
+
+// createFlagEvents writes events made by the actor of ctx. Callers hold
+// the flag writers lock.
+func createFlagEvents(ctx context.Context, tx *gorm.DB, events ...*FlagEvent) error {
+	actor := logger.GetActor(ctx)
+	for _, event := range events {
+		event.Actor = actor
+	}
+	if err := tx.Create(events).Error; err != nil {
+		return err
+	}
+
+	revision := events[len(events)-1].Revision
+	return postgres.Notify(tx, postgres.ChangesChannel, strconv.FormatUint(revision, 10))
+}
+
+func newFlagVersion(event *FlagEvent) *FlagVersion {
+	return &FlagVersion{
+		Change:   event.Type,
+		FlagID:   event.FlagID,
+		Name:     event.Name,
+		Active:   event.Active,
+		Reason:   event.Reason,
+		CausedBy: event.CausedBy,
+		Revision: event.Revision,
+	}
+}
+
+func newFlagChange(ctx context.Context, changeType string, flagId uint, reason string) *FlagChange {
+	return &FlagChange{
+		Type:   changeType,
+		FlagID: flagId,
+		Reason: reason,
+		Actor:  logger.GetActor(ctx),
+	}
+}
+
+// createFlagChange records change with the versions it wrote.
+func createFlagChange(tx *gorm.DB, change *FlagChange, versions ...*FlagVersion) error {
+	if err := tx.Create(change).Error; err != nil {
+		return err
+	}
+	for _, version := range versions {
+		version.ChangeID = change.ID
+	}
+	return createFlagVersions(tx, change.Actor, versions...)
+}
+
+// createFlagVersions numbers versions after the latest version of their
+// flag and records the dependencies and targets their flag has in tx. A
+// change writes one version per flag. Callers hold the flag writers lock,
+// which keeps the numbers of a flag unique.
+func createFlagVersions(tx *gorm.DB, actor string, versions ...*FlagVersion) error {
+	flagIds := make([]uint, 0, len(versions))
+	versioned := make(map[uint]bool, len(versions))
+	for _, version := range versions {
+		if versioned[version.FlagID] {
+			return fmt.Errorf("flag with id %d has several versions in one change", version.FlagID)
+		}
+		versioned[version.FlagID] = true
+		flagIds = append(flagIds, version.FlagID)
+	}
+
+	var dependencies []*FlagDependency
+	err := tx.Where("flag_id IN ?", flagIds).Order("flag_id, depends_on_flag_id").Find(&dependencies).Error
+	if err != nil {
+		return err
+	}
+	dependencyIds := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		dependencyIds[dependency.FlagID] = append(dependencyIds[dependency.FlagID], dependency.DependsOnFlagID)
+	}
+
+	var targets []*FlagTarget
+	err = tx.Where("flag_id IN ?", flagIds).Order("flag_id, targeting_key").Find(&targets).Error
+	if err != nil {
+		return err
+	}
+	allow := make(map[uint][]string)
+	deny := make(map[uint][]string)
+	for _, target := range targets {
+		if target.List == TargetListAllow {
+			allow[target.FlagID] = append(allow[target.FlagID], target.TargetingKey)
+		} else {
+			deny[target.FlagID] = append(deny[target.FlagID], target.TargetingKey)
+		}
+	}
+
+	var latest []*FlagVersion
+	err = tx.Model(&FlagVersion{}).
+		Select("flag_id, MAX(version) AS version").
+		Where("flag_id IN ?", flagIds).
+		Group("flag_id").
+		Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+	latestVersions := make(map[uint]uint, len(latest))
+	for _, version := range latest {
+		latestVersions[version.FlagID] = version.Version
+	}
+
+	for _, version := range versions {
+		latestVersions[version.FlagID]++
+		version.Version = latestVersions[version.FlagID]
+		version.Actor = actor
+		version.Dependencies = dependencyIds[version.FlagID]
+		if version.Dependencies == nil {
+			version.Dependencies = []uint{}
+		}
+		version.Allow = append([]string{}, allow[version.FlagID]...)
+		version.Deny = append([]string{}, deny[version.FlagID]...)
+	}
+	return tx.Create(versions).Error
+}
+
+func (r *Repository) GetLatestFlagEventRevision() (uint64, error) {
+	var revision uint64
+	err := r.db.Model(&FlagEvent{}).Select("COALESCE(MAX(revision), 0)").Scan(&revision).Error
+	return revision, err
+}
+
+func (r *Repository) GetFlagEventsSince(revision uint64, limit int) ([]*FlagEvent, error) {
+	var events []*FlagEvent
+	err := r.db.Where("revision > ?", revision).Order("revision").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *Repository) GetChangedFlagIds(since, until uint64) ([]uint, error) {
+	var flagIds []uint
+	err := r.db.Model(&FlagEvent{}).
+		Distinct("flag_id").
+		Where("revision > ? AND revision <= ?", since, until).
+		Order("flag_id").
+		Pluck("flag_id", &flagIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return flagIds, nil
+}
+
+func (r *Repository) GetFlagTargetsByFlagIds(flagIds []uint) ([]*FlagTarget, error) {
+	var targets []*FlagTarget
+	err := r.db.Where("flag_id IN ?", flagIds).Order("flag_id, targeting_key").Find(&targets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func (r *Repository) GetFlags(offset, limit int) ([]*FeatureFlag, uint, error) {
+	var total int64
+	if err := r.db.Model(&FeatureFlag{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var flags []*FeatureFlag
+	err := r.db.Order("id").Offset(offset).Limit(limit).Find(&flags).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return flags, uint(total), nil
+}
+
+func (r *Repository) UpdateFlagDependencies(
+	ctx context.Context,
+	flag *FeatureFlag,
+	dependencyFlagIds []uint,
+	reason string,
+) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockFlagWriters(tx); err != nil {
+			return err
+		}
+
+		previousIds, err := getDependencyIds(tx, flag)
+		if err != nil {
+			return err
+		}
+		if err := replaceFlagDependencies(tx, flag, dependencyFlagIds); err != nil {
+			return err
+		}
+
+		event := newFlagEvent(FlagEventDependencies, flag, flag.IsActive)
+		event.Dependencies = dependencyFlagIds
+		event.PreviousDependencies = previousIds
+		event.Reason = reason
+		if err := createFlagEvents(ctx, tx, event); err != nil {
+			return err
+		}
+
+		return createFlagChange(tx, newFlagChange(ctx, FlagEventDependencies, flag.ID, reason), newFlagVersion(event))
+	})
+}
+
+// getDependencyIds returns the ids of the flags flag depends on in tx.
+func getDependencyIds(tx *gorm.DB, flag *FeatureFlag) ([]uint, error) {
+	dependencyIds := []uint{}
+	err := tx.Model(&FlagDependency{}).
+		Where("flag_id = ?", flag.ID).
+		Order("depends_on_flag_id").
+		Pluck("depends_on_flag_id", &dependencyIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return dependencyIds, nil
+}
+
+func replaceFlagDependencies(tx *gorm.DB, flag *FeatureFlag, dependencyFlagIds []uint) error {
+	if err := tx.Where("flag_id = ?", flag.ID).Delete(&FlagDependency{}).Error; err != nil {
+		return err
+	}
+	if len(dependencyFlagIds) == 0 {
+		return nil
+	}
+
+	dependencies := make([]*FlagDependency, 0, len(dependencyFlagIds))
+	for _, dependencyFlagId := range dependencyFlagIds {
+		dependencies = append(dependencies, &FlagDependency{
+			FlagID:          flag.ID,
+			DependsOnFlagID: dependencyFlagId,
+		})
+	}
+	return tx.Create(&dependencies).Error
+}
+
+// restoreFlagTargets sets the targeting lists of flag to those of version
+// in tx and returns the event to record, nil when they did not change or
+// version did not record them.
+func restoreFlagTargets(tx *gorm.DB, flag *FeatureFlag, version *FlagVersion, reason string) (*FlagEvent, error) {
+	if !version.HasTargets() {
+		return nil, nil
+	}
+
+	var targets []*FlagTarget
+	if err := tx.Where("flag_id = ?", flag.ID).Find(&targets).Error; err != nil {
+		return nil, err
+	}
+	var allow, deny []string
+	for _, target := range targets {
+		if target.List == TargetListAllow {
+			allow = append(allow, target.TargetingKey)
+		} else {
+			deny = append(deny, target.TargetingKey)
+		}
+	}
+	if utils.SameElements(allow, version.Allow) && utils.SameElements(deny, version.Deny) {
+		return nil, nil
+	}
+
+	if err := tx.Where("flag_id = ?", flag.ID).Delete(&FlagTarget{}).Error; err != nil {
+		return nil, err
+	}
+	restored := make([]*FlagTarget, 0, len(version.Allow)+len(version.Deny))
+	for _, targetingKey := range version.Allow {
+		restored = append(restored, &FlagTarget{FlagID: flag.ID, TargetingKey: targetingKey, List: TargetListAllow})
+	}
+	for _, targetingKey := range version.Deny {
+		restored = append(restored, &FlagTarget{FlagID: flag.ID, TargetingKey: targetingKey, List: TargetListDeny})
+	}
+	if len(restored) > 0 {
+		if err := tx.CreateInBatches(restored, targetBatchSize).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	event := newFlagEvent(FlagEventTargets, flag, flag.IsActive)
+	event.Reason = reason
+	return event, nil
+}
+
+// GetFlagStatesAt returns the last version of every flag at or before at,
+// ordered by flag id. The imported baseline of a flag holds from the time
+// the flag was created, since nothing older was recorded.
+func (r *Repository) GetFlagStatesAt(at time.Time) ([]*FlagVersion, error) {
+	var versions []*FlagVersion
+	err := r.db.Raw(`
+		SELECT DISTINCT ON (v.flag_id) v.*
+		FROM flag_versions v
+		JOIN feature_flags f ON f.id = v.flag_id
+		WHERE v.created_at <= ? OR (v.change = ? AND f.created_at <= ?)
+		ORDER BY v.flag_id, v.version DESC
+	`, at, FlagVersionImported, at).Scan(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// GetFlagVersionsBetween returns the versions recorded after from and up
+// to to, in the order they were written. Imported baselines are not
+// changes and are left out.
+func (r *Repository) GetFlagVersionsBetween(from, to time.Time) ([]*FlagVersion, error) {
+	var versions []*FlagVersion
+	err := r.db.
+		Where("created_at > ? AND created_at <= ? AND change <> ?", from, to, FlagVersionImported).
+		Order("id").
+		Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+func (r *Repository) GetFeatureFlagLogs(
+	flag *FeatureFlag,
+	filter *logger.Filter,
+	page, size uint,
+) ([]*logger.LogEntry, uint, uint, error) {
 	pager := &mongodb.Pager{
 		Page: page,
 		Size: size,
 	}
 
-	filter := bson.M{"metadata.flag_id": flag.ID}
+	flagFilter := *filter
+	flagFilter.FlagIds = []uint{flag.ID}
 
-	total, err := r.collection.CountDocuments(ctx, filter)
+	logs, err := logger.FindLogs(context.Background(), r.collection, &flagFilter, pager)
 	if err != nil {
 		return nil, 0, 0, err
 	}
-	pager.SetTotal(uint(total))
 
-	findOptions := options.Find()
-	findOptions.SetLimit(int64(pager.GetLimit()))
-	findOptions.SetSkip(int64(pager.GetOffset()))
-	findOptions.SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	return logs, pager.Total, pager.TotalPages, nil
+}
+
+func (r *Repository) GetFeatureFlagLogsPage(
+	flag *FeatureFlag,
+	filter *logger.Filter,
+	cursor *logger.PageCursor,
+	size uint,
+) (*logger.LogPage, error) {
+	flagFilter := *filter
+	flagFilter.FlagIds = []uint{flag.ID}
+
+	return logger.FindLogsPage(context.Background(), r.collection, &flagFilter, cursor, size)
+}
+
+func (r *Repository) CountFeatureFlagLogs(flag *FeatureFlag, filter *logger.Filter) (uint, error) {
+	flagFilter := *filter
+	flagFilter.FlagIds = []uint{flag.ID}
+
+	return logger.CountLogs(context.Background(), r.collection, &flagFilter)
+}
+
+func (r *Repository) GetFlagVersions(flag *FeatureFlag, offset, limit int) ([]*FlagVersion, uint, error) {
+	var total int64
+	if err := r.db.Model(&FlagVersion{}).Where("flag_id = ?", flag.ID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var versions []*FlagVersion
+	err := r.db.Where("flag_id = ?", flag.ID).Order("version DESC").Offset(offset).Limit(limit).Find(&versions).Error
+	if err != nil {
+		return nil, 0, err
+	}
 
-	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	return versions, uint(total), nil
+}
+
+func (r *Repository) GetFlagVersion(flag *FeatureFlag, version uint) (*FlagVersion, error) {
+	var flagVersion FlagVersion
+	err := r.db.Where("flag_id = ? AND version = ?", flag.ID, version).First(&flagVersion).Error
 	if err != nil {
-		return nil, 0, 0, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var logs []*logger.LogEntry
-	if err = cursor.All(ctx, &logs); err != nil {
-		return nil, 0, 0, err
+	return &flagVersion, nil
+}
+
+// RollbackFlag restores the state, dependencies and targets of version to
+// flag in one transaction, recorded as the same events a toggle, a
+// dependencies update and a targets change would write. Deactivating the
+// flag also deactivates its transitive dependents; the ones that were
+// active are returned.
+func (r *Repository) RollbackFlag(
+	ctx context.Context,
+	flag *FeatureFlag,
+	version *FlagVersion,
+	reason string,
+) ([]*FeatureFlag, error) {
+	var autoDisabled []*FeatureFlag
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockFlagWriters(tx); err != nil {
+			return err
+		}
+
+		var events []*FlagEvent
+
+		dependencyIds, err := getDependencyIds(tx, flag)
+		if err != nil {
+			return err
+		}
+		if !utils.SameElements(dependencyIds, version.Dependencies) {
+			if err := replaceFlagDependencies(tx, flag, version.Dependencies); err != nil {
+				return err
+			}
+			event := newFlagEvent(FlagEventDependencies, flag, flag.IsActive)
+			event.Dependencies = version.Dependencies
+			event.PreviousDependencies = dependencyIds
+			event.Reason = reason
+			events = append(events, event)
+		}
+
+		event, err := restoreFlagTargets(tx, flag, version, reason)
+		if err != nil {
+			return err
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+
+		switch {
+		case version.Active && !flag.IsActive:
+			if err := tx.Model(flag).Update("is_active", true).Error; err != nil {
+				return err
+			}
+			event := newFlagEvent(FlagEventToggled, flag, true)
+			event.Reason = reason
+			events = append(events, event)
+		case !version.Active && flag.IsActive:
+			deactivated, disabled, err := deactivateFlagAndDependents(tx, flag, reason)
+			if err != nil {
+				return err
+			}
+			events = append(events, deactivated...)
+			autoDisabled = disabled
+		}
+
+		if err := createFlagEvents(ctx, tx, events...); err != nil {
+			return err
+		}
+
+		rolledBack := &FlagVersion{
+			Change:       FlagVersionRolledBack,
+			FlagID:       flag.ID,
+			Name:         flag.Name,
+			Active:       version.Active,
+			Reason:       reason,
+			RolledBackTo: version.Version,
+		}
+		versions := []*FlagVersion{rolledBack}
+		for _, event := range events {
+			if event.FlagID == flag.ID {
+				rolledBack.Revision = event.Revision
+			} else {
+				versions = append(versions, newFlagVersion(event))
+			}
+		}
+		return createFlagChange(tx, newFlagChange(ctx, FlagVersionRolledBack, flag.ID, reason), versions...)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return logs, pager.Total, pager.TotalPages, nil
+	for _, dependent := range autoDisabled {
+		dependent.IsActive = false
+	}
+	flag.IsActive = version.Active
+	return autoDisabled, nil
+}
+
+func (r *Repository) GetFlagChange(changeId uint64) (*FlagChange, error) {
+	var change FlagChange
+	err := r.db.Where("id = ?", changeId).First(&change).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &change, nil
+}
+
+func (r *Repository) GetFlagChangeVersions(change *FlagChange) ([]*FlagVersion, error) {
+	var versions []*FlagVersion
+	err := r.db.Where("change_id = ?", change.ID).Order("id").Find(&versions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// changedFlag holds the first and last versions one change wrote for a
+// flag. A change writes one version per flag, but changes recorded before
+// cascades were deduplicated can hold several.
+type changedFlag struct {
+	first *FlagVersion
+	last  *FlagVersion
+}
+
+// getChangedFlags groups the versions of a change by flag, in the order
+// the flags first appear.
+func getChangedFlags(versions []*FlagVersion) []*changedFlag {
+	var changed []*changedFlag
+	byFlagId := make(map[uint]*changedFlag, len(versions))
+	for _, version := range versions {
+		flag, exists := byFlagId[version.FlagID]
+		if !exists {
+			flag = &changedFlag{first: version, last: version}
+			byFlagId[version.FlagID] = flag
+			changed = append(changed, flag)
+		}
+		if version.Version < flag.first.Version {
+			flag.first = version
+		}
+		if version.Version > flag.last.Version {
+			flag.last = version
+		}
+	}
+	return changed
+}
+
+// UndoFlagChange restores every flag of change to its version before the
+// change in one transaction, and returns the undo change with the versions
+// it wrote. Flags that go inactive deactivate their dependents, which are
+// part of the undo. It fails with ErrChangeSuperseded when a flag of the
+// change was changed since.
+func (r *Repository) UndoFlagChange(
+	ctx context.Context,
+	change *FlagChange,
+	reason string,
+) (*FlagChange, []*FlagVersion, error) {
+	undo := newFlagChange(ctx, FlagVersionUndone, change.FlagID, reason)
+	undo.Undoes = change.ID
+
+	var versions []*FlagVersion
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Other writers wait for this lock before they touch a flag, so
+		// the versions checked below stay the latest until the undo commits.
+		if err := lockFlagWriters(tx); err != nil {
+			return err
+		}
+
+		var changed []*FlagVersion
+		if err := tx.Where("change_id = ?", change.ID).Order("id").Find(&changed).Error; err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			return ErrChangeNotUndoable
+		}
+
+		changedFlags := getChangedFlags(changed)
+		previous := make([]*FlagVersion, 0, len(changedFlags))
+		for _, flag := range changedFlags {
+			var latest uint
+			err := tx.Model(&FlagVersion{}).
+				Select("MAX(version)").
+				Where("flag_id = ?", flag.last.FlagID).
+				Scan(&latest).Error
+			if err != nil {
+				return err
+			}
+			if latest != flag.last.Version {
+				return ErrChangeSuperseded
+			}
+
+			var prior FlagVersion
+			err = tx.Where("flag_id = ? AND version = ?", flag.first.FlagID, flag.first.Version-1).First(&prior).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrChangeNotUndoable
+			}
+			if err != nil {
+				return err
+			}
+			previous = append(previous, &prior)
+		}
+
+		events, err := restoreFlagVersions(tx, previous, reason)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			if err := createFlagEvents(ctx, tx, events...); err != nil {
+				return err
+			}
+		}
+
+		restored := make(map[uint]*FlagVersion, len(previous))
+		for _, prior := range previous {
+			restored[prior.FlagID] = &FlagVersion{
+				Change:       FlagVersionUndone,
+				FlagID:       prior.FlagID,
+				Name:         prior.Name,
+				Active:       prior.Active,
+				Reason:       reason,
+				RolledBackTo: prior.Version,
+			}
+			versions = append(versions, restored[prior.FlagID])
+		}
+		for _, event := range events {
+			if version, exists := restored[event.FlagID]; exists {
+				version.Revision = event.Revision
+			} else {
+				versions = append(versions, newFlagVersion(event))
+			}
+		}
+		return createFlagChange(tx, undo, versions...)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return undo, versions, nil
+}
+
+// restoreFlagVersions sets the flags of versions to their state,
+// dependencies and targets in tx and returns the events to record. Flags
+// are activated before others are deactivated, whose dependents are
+// deactivated too.
+func restoreFlagVersions(tx *gorm.DB, versions []*FlagVersion, reason string) ([]*FlagEvent, error) {
+	flagIds := make([]uint, 0, len(versions))
+	for _, version := range versions {
+		flagIds = append(flagIds, version.FlagID)
+	}
+
+	var flags []*FeatureFlag
+	if err := tx.Where("id IN ?", flagIds).Find(&flags).Error; err != nil {
+		return nil, err
+	}
+	flagsById := make(map[uint]*FeatureFlag, len(flags))
+	for _, flag := range flags {
+		flagsById[flag.ID] = flag
+	}
+
+	var events []*FlagEvent
+	var deactivate []*FeatureFlag
+	for _, version := range versions {
+		flag, exists := flagsById[version.FlagID]
+		if !exists {
+			return nil, fmt.Errorf("flag with id %d not found", version.FlagID)
+		}
+
+		dependencyIds, err := getDependencyIds(tx, flag)
+		if err != nil {
+			return nil, err
+		}
+		if !utils.SameElements(dependencyIds, version.Dependencies) {
+			if err := replaceFlagDependencies(tx, flag, version.Dependencies); err != nil {
+				return nil, err
+			}
+			event := newFlagEvent(FlagEventDependencies, flag, flag.IsActive)
+			event.Dependencies = version.Dependencies
+			event.PreviousDependencies = dependencyIds
+			event.Reason = reason
+			events = append(events, event)
+		}
+
+		event, err := restoreFlagTargets(tx, flag, version, reason)
+		if err != nil {
+			return nil, err
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+
+		switch {
+		case version.Active && !flag.IsActive:
+			if err := tx.Model(flag).Update("is_active", true).Error; err != nil {
+				return nil, err
+			}
+			event := newFlagEvent(FlagEventToggled, flag, true)
+			event.Reason = reason
+			events = append(events, event)
+		case !version.Active && flag.IsActive:
+			deactivate = append(deactivate, flag)
+		}
+	}
+
+	autoDisabled := make(map[uint]struct{})
+	for _, flag := range deactivate {
+		if _, exists := autoDisabled[flag.ID]; exists {
+			continue
+		}
+		deactivated, dependents, err := deactivateFlagAndDependents(tx, flag, reason)
+		if err != nil {
+			return nil, err
+		}
+		for _, dependent := range dependents {
+			autoDisabled[dependent.ID] = struct{}{}
+		}
+		events = append(events, deactivated...)
+	}
+	return events, nil
 }
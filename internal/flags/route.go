@@ -1,15 +1,33 @@
 package flags
 
 import (
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(router *gin.Engine) {
 	{
 		v1 := router.Group("/api/v1")
-		v1.POST("/flags", CreateFeatureFlagAPI)
-		v1.PATCH("/flags/:id", UpdateFeatureFlagAPI)
+		v1.POST("/flags", api.AuditActorMiddleware(), CreateFeatureFlagAPI)
+		v1.GET("/flags", ListFeatureFlagsAPI)
+		v1.GET("/flags/diff", GetFeatureFlagsDiffAPI)
+		v1.PATCH("/flags/:id", api.AuditActorMiddleware(), UpdateFeatureFlagAPI)
 		v1.GET("/flags/:id", GetFeatureFlagAPI)
 		v1.GET("/flags/:id/logs", GetFeatureFlagLogsAPI)
+		v1.PUT("/flags/:id/dependencies", api.AuditActorMiddleware(), UpdateFeatureFlagDependenciesAPI)
+		v1.GET("/flags/:id/versions", ListFeatureFlagVersionsAPI)
+		v1.GET("/flags/:id/versions/diff", GetFeatureFlagVersionsDiffAPI)
+		v1.POST("/flags/:id/rollback", api.AuditActorMiddleware(), RollbackFeatureFlagAPI)
+		v1.GET("/flags/:id/targets", GetFeatureFlagTargetsAPI)
+		v1.POST("/flags/:id/targets/:list", api.AuditActorMiddleware(), AddFeatureFlagTargetsAPI)
+		v1.DELETE("/flags/:id/targets/:list", api.AuditActorMiddleware(), RemoveFeatureFlagTargetsAPI)
+		v1.POST("/flags/:id/evaluate", EvaluateFeatureFlagAPI)
+		v1.POST("/flags/:id/explain", ExplainFeatureFlagAPI)
+		v1.GET("/changes/:id", GetFlagChangeAPI)
+		v1.POST("/changes/:id/undo", api.AuditActorMiddleware(), UndoFlagChangeAPI)
+		v1.POST("/evaluate/all", EvaluateAllFeatureFlagsAPI)
+		v1.GET("/config", GetFeatureFlagsConfigAPI)
+		v1.GET("/snapshot", GetFeatureFlagsSnapshotAPI)
+		v1.GET("/snapshot/public-key", GetFeatureFlagsSnapshotPublicKeyAPI)
 	}
 }
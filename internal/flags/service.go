@@ -1,6 +1,12 @@
 package flags
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"sync"
@@ -8,6 +14,10 @@ import (
 
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/snapshot"
 	"github.com/gin-gonic/gin"
 )
 
@@ -31,6 +41,13 @@ func GetService(repo IRepository, logger logger.IService) *Service {
 	return service
 }
 
+// primary returns a copy of s that reads from Postgres rather than the
+// snapshot, for the checks and before states of writes: the snapshot can lag
+// behind writes made on other replicas.
+func (s *Service) primary() *Service {
+	return &Service{Repo: s.Repo.Primary(), Logger: s.Logger}
+}
+
 func (s *Service) ValidateCreateFeatureFlagRequest(c *gin.Context) (*CreateFeatureFlagRequest, *api.APIError) {
 	var req CreateFeatureFlagRequest
 
@@ -38,53 +55,58 @@ func (s *Service) ValidateCreateFeatureFlagRequest(c *gin.Context) (*CreateFeatu
 		return nil, api.BadRequestError("Invalid input format", err.Error())
 	}
 
-	flag, err := s.Repo.GetFlagByName(req.Name)
+	if err := s.ValidateCreateFeatureFlag(&req); err != nil {
+		return nil, err
+	}
+
+	return &req, nil
+}
+
+func (s *Service) ValidateCreateFeatureFlag(req *CreateFeatureFlagRequest) *api.APIError {
+	primary := s.primary()
+	flag, err := primary.Repo.GetFlagByName(req.Name)
 	if err != nil {
-		return nil, api.InternalServerError("Internal Server Error", err.Error())
+		return api.InternalServerError("Internal Server Error", err.Error())
 	}
 	if flag != nil {
-		return nil, api.ConflictError("Feature flag already exists", "A feature flag with this name already exists")
+		return api.ConflictError("Feature flag already exists", "A feature flag with this name already exists")
 	}
 
 	if len(req.FeatureFlagIDDependencies) == 0 {
-		return &req, nil
+		return nil
 	}
 
-	dependencyFlags, err := s.Repo.GetFlagByIds(req.FeatureFlagIDDependencies)
+	dependencyFlags, err := primary.Repo.GetFlagByIds(req.FeatureFlagIDDependencies)
 	if err != nil {
-		return nil, api.InternalServerError("Internal Server Error", err.Error())
+		return api.InternalServerError("Internal Server Error", err.Error())
 	}
 	if len(dependencyFlags) != len(req.FeatureFlagIDDependencies) {
-		return nil, api.NotFoundError("Invalid dependency feature flag ids", "")
+		return api.NotFoundError("Invalid dependency feature flag ids", "")
 	}
 
 	if req.IsActive {
 		if canActivate, inactiveIds := s.canActivateFlag(dependencyFlags); !canActivate {
-			return nil, api.BadRequestError(
+			return api.BadRequestError(
 				"Dependency validation failed",
 				fmt.Sprintf("Cannot activate feature flag. Missing dependency IDs: %v", inactiveIds),
 			)
 		}
 	}
 
-	return &req, nil
+	return nil
 }
 
-func (s *Service) CreateFeatureFlag(req *CreateFeatureFlagRequest) *api.APIError {
-	flag, err := s.Repo.CreateFlag(req.Name, req.IsActive, req.FeatureFlagIDDependencies)
+func (s *Service) CreateFeatureFlag(ctx context.Context, req *CreateFeatureFlagRequest) (*FeatureFlag, *api.APIError) {
+	flag, err := s.Repo.CreateFlag(ctx, req.Name, req.IsActive, req.FeatureFlagIDDependencies)
 	if err != nil {
-		return api.InternalServerError("Internal Server Error", err.Error())
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
 	}
 
-	s.Logger.Log(&logger.LogEntry{
-		Message: "Feature Flag is created successfully",
-		Metadata: map[string]any{
-			"flag_id": flag.ID,
-		},
-		Timestamp: time.Now(),
-	})
+	entry := newLogEntry(ctx, logger.ActionCreated, flag, "", "Feature Flag is created successfully")
+	entry.After = newFlagState(flag, req.FeatureFlagIDDependencies)
+	s.Logger.Log(entry)
 
-	return nil
+	return flag, nil
 }
 
 func (s *Service) ValidateUpdateFeatureFlagRequest(
@@ -102,37 +124,45 @@ func (s *Service) ValidateUpdateFeatureFlagRequest(
 	if err := c.ShouldBindJSON(&req); err != nil {
 		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
 	}
-	flag, err := s.Repo.GetFlagById(uint(flagId))
-	if err != nil {
-		return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+
+	flag, apiErr := s.ValidateUpdateFeatureFlag(uint(flagId), &req)
+	if apiErr != nil {
+		return nil, nil, apiErr
 	}
-	if flag == nil {
-		return nil, nil, api.NotFoundError("Invalid flag id", "")
+
+	return flag, &req, nil
+}
+
+func (s *Service) ValidateUpdateFeatureFlag(flagId uint, req *UpdateFeatureFlagRequest) (*FeatureFlag, *api.APIError) {
+	primary := s.primary()
+	flag, apiErr := primary.GetFeatureFlagById(flagId)
+	if apiErr != nil {
+		return nil, apiErr
 	}
 	if req.IsActive == flag.IsActive {
 		status := "active"
 		if !flag.IsActive {
 			status = "inactive"
 		}
-		return nil, nil, api.OKError(fmt.Sprintf("Flag is already %s", status), "")
+		return nil, api.OKError(fmt.Sprintf("Flag is already %s", status), "")
 	}
 
 	if !req.IsActive {
-		return flag, &req, nil
+		return flag, nil
 	}
 
-	flagDependencies, err := s.Repo.GetFlagDependencies(flag)
+	flagDependencies, err := primary.Repo.GetFlagDependencies(flag)
 	if err != nil {
-		return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
 	}
 	if canActivate, inactiveIds := s.canActivateFlag(flagDependencies); !canActivate {
-		return nil, nil, api.BadRequestError(
+		return nil, api.BadRequestError(
 			"Dependency validation failed",
 			fmt.Sprintf("Cannot activate feature flag. Missing dependency IDs: %v", inactiveIds),
 		)
 	}
 
-	return flag, &req, nil
+	return flag, nil
 }
 
 func (s *Service) canActivateFlag(flagDependencies []*FeatureFlag) (bool, []uint) {
@@ -145,21 +175,23 @@ func (s *Service) canActivateFlag(flagDependencies []*FeatureFlag) (bool, []uint
 	return len(inactiveIds) == 0, inactiveIds
 }
 
-func (s *Service) UpdateFeatureFlag(flag *FeatureFlag, req *UpdateFeatureFlagRequest) *api.APIError {
-	err := s.Repo.UpdateFlag(flag, req.IsActive)
+func (s *Service) UpdateFeatureFlag(ctx context.Context, flag *FeatureFlag, req *UpdateFeatureFlagRequest) *api.APIError {
+	dependencyIds, err := s.primary().getFlagDependencyIds(flag)
 	if err != nil {
 		return api.InternalServerError("Internal Server Error", err.Error())
 	}
+	before := newFlagState(flag, dependencyIds)
 
-	s.Logger.Log(&logger.LogEntry{
-		Message: "Feature Flag is toggled successfully",
-		Metadata: map[string]any{
-			"flag_id": flag.ID,
-			"active":  flag.IsActive,
-			"reason":  req.Reason,
-		},
-		Timestamp: time.Now(),
-	})
+	autoDisabled, err := s.Repo.UpdateFlag(ctx, flag, req.IsActive, req.Reason)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	entry := newLogEntry(ctx, logger.ActionToggled, flag, req.Reason, "Feature Flag is toggled successfully")
+	entry.Before = before
+	entry.After = newFlagState(flag, dependencyIds)
+	s.Logger.Log(entry)
+	s.logAutoDisabled(ctx, flag, autoDisabled, req.Reason)
 
 	return nil
 }
@@ -170,7 +202,11 @@ func (s *Service) ValidateGetFeatureFlagRequest(c *gin.Context) (*FeatureFlag, *
 		return nil, api.BadRequestError("Invalid input format", err.Error())
 	}
 
-	flag, err := s.Repo.GetFlagById(uint(flagId))
+	return s.GetFeatureFlagById(uint(flagId))
+}
+
+func (s *Service) GetFeatureFlagById(flagId uint) (*FeatureFlag, *api.APIError) {
+	flag, err := s.Repo.GetFlagById(flagId)
 	if err != nil {
 		return nil, api.InternalServerError("Internal Server Error", err.Error())
 	}
@@ -211,29 +247,206 @@ func (s *Service) GetFeatureFlag(flag *FeatureFlag) (*FeatureFlagData, *api.APIE
 	}, nil
 }
 
-func (s *Service) ValidateGetFeatureFlagLogsRequest(
+func (s *Service) ValidateListFeatureFlagsRequest(c *gin.Context) (*ListFeatureFlagsQueryParams, *api.APIError) {
+	var query ListFeatureFlagsQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	return &query, nil
+}
+
+func (s *Service) ListFeatureFlags(query *ListFeatureFlagsQueryParams) (*ListFeatureFlagsData, *api.APIError) {
+	flags, total, err := s.Repo.GetFlags(int((query.Page-1)*query.Size), int(query.Size))
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	dependencies, err := s.Repo.GetAllDependencies()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	dependencyIDs := make(map[uint][]uint)
+	dependentIDs := make(map[uint][]uint)
+	for _, dependency := range dependencies {
+		dependencyIDs[dependency.FlagID] = append(dependencyIDs[dependency.FlagID], dependency.DependsOnFlagID)
+		dependentIDs[dependency.DependsOnFlagID] = append(dependentIDs[dependency.DependsOnFlagID], dependency.FlagID)
+	}
+
+	data := &ListFeatureFlagsData{
+		Flags: make([]*FeatureFlagData, 0, len(flags)),
+		PaginationResponse: api.PaginationResponse{
+			Page:       query.Page,
+			Size:       query.Size,
+			Total:      total,
+			TotalPages: (total + query.Size - 1) / query.Size,
+		},
+	}
+	for _, flag := range flags {
+		flagData := &FeatureFlagData{
+			ID:           flag.ID,
+			Name:         flag.Name,
+			Active:       flag.IsActive,
+			Dependencies: dependencyIDs[flag.ID],
+			Dependents:   dependentIDs[flag.ID],
+		}
+		if flagData.Dependencies == nil {
+			flagData.Dependencies = []uint{}
+		}
+		if flagData.Dependents == nil {
+			flagData.Dependents = []uint{}
+		}
+		data.Flags = append(data.Flags, flagData)
+	}
+
+	return data, nil
+}
+
+func (s *Service) ValidateUpdateFeatureFlagDependenciesRequest(
 	c *gin.Context,
 ) (
-	*GetFeatureFlagLogsQueryParams,
 	*FeatureFlag,
+	*UpdateFeatureFlagDependenciesRequest,
 	*api.APIError,
 ) {
 	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
 	}
-	flag, err := s.Repo.GetFlagById(uint(flagId))
+	var req UpdateFeatureFlagDependenciesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	flag, apiErr := s.ValidateUpdateFeatureFlagDependencies(uint(flagId), &req)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	return flag, &req, nil
+}
+
+func (s *Service) ValidateUpdateFeatureFlagDependencies(
+	flagId uint,
+	req *UpdateFeatureFlagDependenciesRequest,
+) (
+	*FeatureFlag,
+	*api.APIError,
+) {
+	req.Dependencies = utils.Unique(req.Dependencies)
+	if req.Dependencies == nil {
+		req.Dependencies = []uint{}
+	}
+
+	primary := s.primary()
+	flag, apiErr := primary.GetFeatureFlagById(flagId)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+	if apiErr := primary.validateFlagDependencies(flag, req.Dependencies, flag.IsActive); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return flag, nil
+}
+
+// validateFlagDependencies checks that flag can depend on dependencyIds
+// while being active or not: they must exist, must not create a cycle, and
+// must all be active for an active flag.
+func (s *Service) validateFlagDependencies(flag *FeatureFlag, dependencyIds []uint, active bool) *api.APIError {
+	if len(dependencyIds) == 0 {
+		return nil
+	}
+
+	dependencyFlags, err := s.Repo.GetFlagByIds(dependencyIds)
 	if err != nil {
-		return nil, nil, api.InternalServerError("Internal Server Error", err.Error())
+		return api.InternalServerError("Internal Server Error", err.Error())
 	}
-	if flag == nil {
-		return nil, nil, api.NotFoundError("Invalid flag id", "")
+	if len(dependencyFlags) != len(dependencyIds) {
+		return api.NotFoundError("Invalid dependency feature flag ids", "")
+	}
+
+	for _, dependencyFlag := range dependencyFlags {
+		if dependencyFlag.ID == flag.ID {
+			return api.BadRequestError("Dependency validation failed", "A feature flag cannot depend on itself")
+		}
+		transitiveDependencies, err := s.Repo.GetAllTransitiveDependencies(dependencyFlag)
+		if err != nil {
+			return api.InternalServerError("Internal Server Error", err.Error())
+		}
+		for _, transitiveDependency := range transitiveDependencies {
+			if transitiveDependency.ID == flag.ID {
+				return api.BadRequestError(
+					"Dependency validation failed",
+					fmt.Sprintf("Circular dependency detected through feature flag %d", dependencyFlag.ID),
+				)
+			}
+		}
+	}
+
+	if active {
+		if canActivate, inactiveIds := s.canActivateFlag(dependencyFlags); !canActivate {
+			return api.BadRequestError(
+				"Dependency validation failed",
+				fmt.Sprintf("Active feature flag cannot depend on inactive flags. Inactive dependency IDs: %v", inactiveIds),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) UpdateFeatureFlagDependencies(
+	ctx context.Context,
+	flag *FeatureFlag,
+	req *UpdateFeatureFlagDependenciesRequest,
+) *api.APIError {
+	dependencyIds, err := s.primary().getFlagDependencyIds(flag)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	err = s.Repo.UpdateFlagDependencies(ctx, flag, req.Dependencies, req.Reason)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	entry := newLogEntry(ctx, logger.ActionDependenciesUpdated, flag, req.Reason,
+		"Feature Flag dependencies are updated successfully")
+	entry.Before = newFlagState(flag, dependencyIds)
+	entry.After = newFlagState(flag, req.Dependencies)
+	s.Logger.Log(entry)
+
+	return nil
+}
+
+func (s *Service) ValidateGetFeatureFlagLogsRequest(
+	c *gin.Context,
+) (
+	*GetFeatureFlagLogsQueryParams,
+	*FeatureFlag,
+	*api.APIError,
+) {
+	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	flag, apiErr := s.GetFeatureFlagById(uint(flagId))
+	if apiErr != nil {
+		return nil, nil, apiErr
 	}
 
 	var query GetFeatureFlagLogsQueryParams
 	if err := c.ShouldBindQuery(&query); err != nil {
 		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
 	}
+	if apiErr := logs.ValidateFilterQueryParams(&query.FilterQueryParams); apiErr != nil {
+		return nil, nil, apiErr
+	}
+	if apiErr := logs.ValidatePageQueryParams(&query.PageQueryParams, &query.FilterQueryParams); apiErr != nil {
+		return nil, nil, apiErr
+	}
 
 	return &query, flag, nil
 }
@@ -245,7 +458,11 @@ func (s *Service) GetFeatureFlagLogs(
 	*GetFeatureFlagLogsData,
 	*api.APIError,
 ) {
-	logs, total, totalPages, err := s.Repo.GetFeatureFlagLogs(flag, query.Page, query.Size)
+	if query.Page == 0 {
+		return s.getFeatureFlagLogsPage(flag, query)
+	}
+
+	logs, total, totalPages, err := s.Repo.GetFeatureFlagLogs(flag, query.ToFilter(), query.Page, query.Size)
 	if err != nil {
 		return nil, api.InternalServerError("Internal Server Error", err.Error())
 	}
@@ -260,3 +477,470 @@ func (s *Service) GetFeatureFlagLogs(
 		},
 	}, nil
 }
+
+func (s *Service) getFeatureFlagLogsPage(
+	flag *FeatureFlag,
+	query *GetFeatureFlagLogsQueryParams,
+) (
+	*GetFeatureFlagLogsData,
+	*api.APIError,
+) {
+	filter := query.ToFilter()
+	page, err := s.Repo.GetFeatureFlagLogsPage(flag, filter, query.PageCursor(), query.Size)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data := &GetFeatureFlagLogsData{
+		Logs:                     page.Logs,
+		PaginationResponse:       api.PaginationResponse{Size: query.Size},
+		CursorPaginationResponse: logs.NewCursorPaginationResponse(page),
+	}
+	if query.Total {
+		total, err := s.Repo.CountFeatureFlagLogs(flag, filter)
+		if err != nil {
+			return nil, api.InternalServerError("Internal Server Error", err.Error())
+		}
+		data.Total = total
+		data.TotalPages = (total + query.Size - 1) / query.Size
+	}
+	return data, nil
+}
+
+func (s *Service) GetFeatureFlagTargets(flag *FeatureFlag) (*FeatureFlagTargetsData, *api.APIError) {
+	targets, err := s.Repo.GetFlagTargets(flag)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data := &FeatureFlagTargetsData{
+		Allow: []string{},
+		Deny:  []string{},
+	}
+	for _, target := range targets {
+		if target.List == TargetListAllow {
+			data.Allow = append(data.Allow, target.TargetingKey)
+		} else {
+			data.Deny = append(data.Deny, target.TargetingKey)
+		}
+	}
+
+	return data, nil
+}
+
+func (s *Service) ValidateFeatureFlagTargetsRequest(
+	c *gin.Context,
+) (
+	*FeatureFlag,
+	string,
+	*FeatureFlagTargetsRequest,
+	*api.APIError,
+) {
+	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, "", nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	list := c.Param("list")
+	if list != TargetListAllow && list != TargetListDeny {
+		return nil, "", nil, api.BadRequestError(
+			"Invalid target list",
+			fmt.Sprintf("Target list must be either %s or %s", TargetListAllow, TargetListDeny),
+		)
+	}
+	var req FeatureFlagTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, "", nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	req.TargetingKeys = utils.Unique(req.TargetingKeys)
+
+	flag, err := s.Repo.Primary().GetFlagById(uint(flagId))
+	if err != nil {
+		return nil, "", nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if flag == nil {
+		return nil, "", nil, api.NotFoundError("Invalid flag id", "")
+	}
+
+	return flag, list, &req, nil
+}
+
+func (s *Service) AddFeatureFlagTargets(
+	ctx context.Context,
+	flag *FeatureFlag,
+	list string,
+	req *FeatureFlagTargetsRequest,
+) *api.APIError {
+	err := s.Repo.AddFlagTargets(ctx, flag, list, req.TargetingKeys)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	entry := newLogEntry(ctx, logger.ActionTargetsAdded, flag, "", "Feature Flag targets are added successfully")
+	entry.Metadata = map[string]any{
+		"list":           list,
+		"targeting_keys": req.TargetingKeys,
+		"count":          len(req.TargetingKeys),
+	}
+	s.Logger.Log(entry)
+
+	return nil
+}
+
+func (s *Service) RemoveFeatureFlagTargets(
+	ctx context.Context,
+	flag *FeatureFlag,
+	list string,
+	req *FeatureFlagTargetsRequest,
+) *api.APIError {
+	removed, err := s.Repo.RemoveFlagTargets(ctx, flag, list, req.TargetingKeys)
+	if err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	entry := newLogEntry(ctx, logger.ActionTargetsRemoved, flag, "", "Feature Flag targets are removed successfully")
+	entry.Metadata = map[string]any{
+		"list":           list,
+		"targeting_keys": req.TargetingKeys,
+		"count":          removed,
+	}
+	s.Logger.Log(entry)
+
+	return nil
+}
+
+func (s *Service) ValidateEvaluateFeatureFlagRequest(
+	c *gin.Context,
+) (
+	*FeatureFlag,
+	*EvaluateFeatureFlagRequest,
+	*api.APIError,
+) {
+	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	var req EvaluateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	flag, apiErr := s.GetFeatureFlagById(uint(flagId))
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	return flag, &req, nil
+}
+
+func (s *Service) EvaluateFeatureFlag(
+	flag *FeatureFlag,
+	req *EvaluateFeatureFlagRequest,
+) (
+	*EvaluateFeatureFlagData,
+	*api.APIError,
+) {
+	evaluator, err := s.getFlagEvaluator(flag, req.Context)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	result := evaluator.Evaluate(flag.ID, req.Context)
+
+	return &EvaluateFeatureFlagData{
+		ID:     flag.ID,
+		Name:   flag.Name,
+		Result: *result,
+	}, nil
+}
+
+func (s *Service) ExplainFeatureFlag(
+	flag *FeatureFlag,
+	req *EvaluateFeatureFlagRequest,
+) (
+	*ExplainFeatureFlagData,
+	*api.APIError,
+) {
+	evaluator, err := s.getFlagEvaluator(flag, req.Context)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	result, trace := evaluator.Explain(flag.ID, req.Context)
+
+	return &ExplainFeatureFlagData{
+		EvaluateFeatureFlagData: EvaluateFeatureFlagData{
+			ID:     flag.ID,
+			Name:   flag.Name,
+			Result: *result,
+		},
+		Trace: trace,
+	}, nil
+}
+
+func (s *Service) getFlagEvaluator(flag *FeatureFlag, ctx evaluation.Context) (*evaluation.Evaluator, error) {
+	dependencies, err := s.Repo.GetAllTransitiveDependencies(flag)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluationFlags, err := s.getEvaluationFlags(append([]*FeatureFlag{flag}, dependencies...), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluation.NewEvaluator(evaluationFlags), nil
+}
+
+func (s *Service) getEvaluationFlags(flags []*FeatureFlag, ctx evaluation.Context) ([]*evaluation.Flag, error) {
+	flagIds := make([]uint, 0, len(flags))
+	for _, flag := range flags {
+		flagIds = append(flagIds, flag.ID)
+	}
+
+	dependencies, err := s.Repo.GetDependenciesByFlagIds(flagIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*FlagTarget
+	if ctx.TargetingKey != "" {
+		targets, err = s.Repo.GetFlagTargetsByKey(flagIds, ctx.TargetingKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return buildEvaluationFlags(flags, dependencies, targets), nil
+}
+
+func buildEvaluationFlags(
+	flags []*FeatureFlag,
+	dependencies []*FlagDependency,
+	targets []*FlagTarget,
+) []*evaluation.Flag {
+	evaluationFlags := make(map[uint]*evaluation.Flag, len(flags))
+	result := make([]*evaluation.Flag, 0, len(flags))
+	for _, flag := range flags {
+		evaluationFlag := &evaluation.Flag{
+			ID:           flag.ID,
+			Name:         flag.Name,
+			Active:       flag.IsActive,
+			Dependencies: []uint{},
+		}
+		evaluationFlags[flag.ID] = evaluationFlag
+		result = append(result, evaluationFlag)
+	}
+
+	for _, dependency := range dependencies {
+		if evaluationFlag, ok := evaluationFlags[dependency.FlagID]; ok {
+			evaluationFlag.Dependencies = append(evaluationFlag.Dependencies, dependency.DependsOnFlagID)
+		}
+	}
+
+	for _, target := range targets {
+		evaluationFlag, ok := evaluationFlags[target.FlagID]
+		if !ok {
+			continue
+		}
+		if target.List == TargetListAllow {
+			evaluationFlag.Allow = append(evaluationFlag.Allow, target.TargetingKey)
+		} else {
+			evaluationFlag.Deny = append(evaluationFlag.Deny, target.TargetingKey)
+		}
+	}
+
+	return result
+}
+
+func (s *Service) ValidateEvaluateAllFeatureFlagsRequest(c *gin.Context) (*EvaluateAllFeatureFlagsRequest, *api.APIError) {
+	var req EvaluateAllFeatureFlagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	return &req, nil
+}
+
+func (s *Service) EvaluateAllFeatureFlags(
+	req *EvaluateAllFeatureFlagsRequest,
+) (
+	*EvaluateAllFeatureFlagsData,
+	*api.APIError,
+) {
+	flags, err := s.Repo.GetAllFlags()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	dependencies, err := s.Repo.GetAllDependencies()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	var targets []*FlagTarget
+	if req.Context.TargetingKey != "" {
+		targets, err = s.Repo.GetAllFlagTargetsByKey(req.Context.TargetingKey)
+		if err != nil {
+			return nil, api.InternalServerError("Internal Server Error", err.Error())
+		}
+	}
+
+	results := evaluation.NewEvaluator(buildEvaluationFlags(flags, dependencies, targets)).
+		EvaluateAll(req.Context)
+
+	data := &EvaluateAllFeatureFlagsData{
+		Flags: make(map[string]*evaluation.Result, len(flags)),
+	}
+	for _, flag := range flags {
+		data.Flags[flag.Name] = results[flag.ID]
+	}
+
+	return data, nil
+}
+
+func (s *Service) GetFeatureFlagsConfig() (*evaluation.Config, string, *api.APIError) {
+	flags, err := s.Repo.GetAllFlags()
+	if err != nil {
+		return nil, "", api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	dependencies, err := s.Repo.GetAllDependencies()
+	if err != nil {
+		return nil, "", api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	targets, err := s.Repo.GetAllFlagTargets()
+	if err != nil {
+		return nil, "", api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	config := &evaluation.Config{
+		Flags: buildEvaluationFlags(flags, dependencies, targets),
+	}
+
+	body, err := json.Marshal(config)
+	if err != nil {
+		return nil, "", api.InternalServerError("Internal Server Error", err.Error())
+	}
+	hash := sha256.Sum256(body)
+
+	return config, fmt.Sprintf("%q", hex.EncodeToString(hash[:])), nil
+}
+
+func (s *Service) GetFeatureFlagsSnapshot() (*snapshot.File, *api.APIError) {
+	key, err := GetSnapshotSigningKey()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	// The revision is read before the configuration, so the snapshot is at
+	// least as new as the revision it claims.
+	revision, err := s.Repo.GetLatestFlagEventRevision()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	config, _, apiErr := s.GetFeatureFlagsConfig()
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	file, err := snapshot.Sign(&snapshot.Snapshot{
+		Revision:  revision,
+		CreatedAt: time.Now().UTC(),
+		Config:    config,
+	}, key)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	return file, nil
+}
+
+func (s *Service) GetFeatureFlagsSnapshotPublicKey() (*SnapshotPublicKeyData, *api.APIError) {
+	key, err := GetSnapshotSigningKey()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	return &SnapshotPublicKeyData{
+		Algorithm: snapshot.AlgorithmEd25519,
+		PublicKey: base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}, nil
+}
+
+func (s *Service) ValidateGetFeatureFlagChangesRequest(c *gin.Context) (*GetFeatureFlagChangesQueryParams, *api.APIError) {
+	var query GetFeatureFlagChangesQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	return &query, nil
+}
+
+// GetFeatureFlagChangesUntil returns the changes after since, including
+// the change with revision. The snapshot can lag a change just notified,
+// so they are read from Postgres when the snapshot has not reached it.
+func (s *Service) GetFeatureFlagChangesUntil(since, revision uint64) (*FeatureFlagChangesData, *api.APIError) {
+	data, apiErr := s.GetFeatureFlagChanges(since)
+	if apiErr != nil || data.Revision >= revision {
+		return data, apiErr
+	}
+	return s.primary().GetFeatureFlagChanges(since)
+}
+
+func (s *Service) GetFeatureFlagChanges(since uint64) (*FeatureFlagChangesData, *api.APIError) {
+	revision, err := s.Repo.GetLatestFlagEventRevision()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data := &FeatureFlagChangesData{
+		Revision: revision,
+		Flags:    []*evaluation.Flag{},
+		Deleted:  []uint{},
+	}
+	if since >= revision {
+		return data, nil
+	}
+
+	changedIds, err := s.Repo.GetChangedFlagIds(since, revision)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if len(changedIds) == 0 {
+		return data, nil
+	}
+
+	flags, err := s.Repo.GetFlagByIds(changedIds)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	existing := make(map[uint]struct{}, len(flags))
+	flagIds := make([]uint, 0, len(flags))
+	for _, flag := range flags {
+		existing[flag.ID] = struct{}{}
+		flagIds = append(flagIds, flag.ID)
+	}
+	for _, flagId := range changedIds {
+		if _, ok := existing[flagId]; !ok {
+			data.Deleted = append(data.Deleted, flagId)
+		}
+	}
+	if len(flagIds) == 0 {
+		return data, nil
+	}
+
+	dependencies, err := s.Repo.GetDependenciesByFlagIds(flagIds)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	targets, err := s.Repo.GetFlagTargetsByFlagIds(flagIds)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data.Flags = buildEvaluationFlags(flags, dependencies, targets)
+	return data, nil
+}
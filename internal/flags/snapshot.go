@@ -0,0 +1,433 @@
+package flags
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+)
+
+// Snapshot is an immutable copy of every flag, dependency edge and target at
+// a given revision. Flags are copied on read because callers update them in
+// place; dependencies and targets are shared read-only.
+type Snapshot struct {
+	Revision uint64
+	LoadedAt time.Time
+
+	flags        []*FeatureFlag
+	flagsById    map[uint]*FeatureFlag
+	flagsByName  map[string]*FeatureFlag
+	dependencies []*FlagDependency
+	dependsOn    map[uint][]uint
+	dependents   map[uint][]uint
+	targets      []*FlagTarget
+	targetsById  map[uint][]*FlagTarget
+	targetsByKey map[string][]*FlagTarget
+}
+
+func newSnapshot(revision uint64, flags []*FeatureFlag, dependencies []*FlagDependency, targets []*FlagTarget) *Snapshot {
+	s := &Snapshot{
+		Revision:     revision,
+		LoadedAt:     time.Now(),
+		flags:        flags,
+		flagsById:    make(map[uint]*FeatureFlag, len(flags)),
+		flagsByName:  make(map[string]*FeatureFlag, len(flags)),
+		dependencies: dependencies,
+		dependsOn:    make(map[uint][]uint),
+		dependents:   make(map[uint][]uint),
+		targets:      targets,
+		targetsById:  make(map[uint][]*FlagTarget),
+		targetsByKey: make(map[string][]*FlagTarget),
+	}
+	for _, flag := range flags {
+		s.flagsById[flag.ID] = flag
+		s.flagsByName[flag.Name] = flag
+	}
+	for _, dependency := range dependencies {
+		s.dependsOn[dependency.FlagID] = append(s.dependsOn[dependency.FlagID], dependency.DependsOnFlagID)
+		s.dependents[dependency.DependsOnFlagID] = append(s.dependents[dependency.DependsOnFlagID], dependency.FlagID)
+	}
+	for _, target := range targets {
+		s.targetsById[target.FlagID] = append(s.targetsById[target.FlagID], target)
+		s.targetsByKey[target.TargetingKey] = append(s.targetsByKey[target.TargetingKey], target)
+	}
+	return s
+}
+
+func (s *Snapshot) getFlags(flagIds []uint) []*FeatureFlag {
+	flags := make([]*FeatureFlag, 0, len(flagIds))
+	for _, flagId := range flagIds {
+		if flag, ok := s.flagsById[flagId]; ok {
+			flagCopy := *flag
+			flags = append(flags, &flagCopy)
+		}
+	}
+	return flags
+}
+
+// CachedRepository serves flag, dependency and target reads from an
+// in-memory snapshot and passes everything else through. The snapshot is
+// replaced whenever a newer revision is seen, and kept as is when Postgres
+// cannot be reached so reads keep working during short outages.
+type CachedRepository struct {
+	IRepository
+	refreshInterval time.Duration
+
+	mu           sync.Mutex
+	snapshot     atomic.Pointer[Snapshot]
+	verifiedAt   atomic.Int64
+	syncFailures atomic.Uint64
+}
+
+func NewCachedRepository(repo IRepository, refreshInterval time.Duration) (*CachedRepository, error) {
+	c := &CachedRepository{
+		IRepository:     repo,
+		refreshInterval: refreshInterval,
+	}
+	if err := c.Refresh(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *CachedRepository) Run(stop <-chan struct{}, notifications <-chan postgres.Notification) {
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case notification := <-notifications:
+			revision, err := strconv.ParseUint(notification.Payload, 10, 64)
+			if notification.Resync || err != nil || revision > c.Snapshot().Revision {
+				c.syncAfterWrite()
+			}
+		case <-ticker.C:
+			c.syncAfterWrite()
+		}
+	}
+}
+
+// Sync reloads the snapshot if the latest revision in Postgres differs from
+// the cached one, and otherwise only marks the snapshot as verified. Failed
+// syncs are counted by SyncFailures.
+func (c *CachedRepository) Sync() error {
+	if err := c.sync(); err != nil {
+		c.syncFailures.Add(1)
+		return err
+	}
+	return nil
+}
+
+func (c *CachedRepository) sync() error {
+	revision, err := c.IRepository.GetLatestFlagEventRevision()
+	if err != nil {
+		return err
+	}
+	if revision == c.Snapshot().Revision {
+		c.verifiedAt.Store(time.Now().UnixNano())
+		return nil
+	}
+	return c.Refresh()
+}
+
+func (c *CachedRepository) Refresh() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The revision is read first: a change committed while the rest is
+	// loading is picked up again by the next sync.
+	revision, err := c.IRepository.GetLatestFlagEventRevision()
+	if err != nil {
+		return err
+	}
+	flags, err := c.IRepository.GetAllFlags()
+	if err != nil {
+		return err
+	}
+	dependencies, err := c.IRepository.GetAllDependencies()
+	if err != nil {
+		return err
+	}
+	targets, err := c.IRepository.GetAllFlagTargets()
+	if err != nil {
+		return err
+	}
+
+	snapshot := newSnapshot(revision, flags, dependencies, targets)
+	c.snapshot.Store(snapshot)
+	c.verifiedAt.Store(snapshot.LoadedAt.UnixNano())
+	return nil
+}
+
+func (c *CachedRepository) Snapshot() *Snapshot {
+	return c.snapshot.Load()
+}
+
+// SyncFailures is the number of syncs that failed, including the syncs
+// after writes, whose failure cannot fail the committed write.
+func (c *CachedRepository) SyncFailures() uint64 {
+	return c.syncFailures.Load()
+}
+
+// Age is the time since the snapshot was last confirmed to match Postgres.
+func (c *CachedRepository) Age() time.Duration {
+	return time.Since(time.Unix(0, c.verifiedAt.Load()))
+}
+
+// GetLatestFlagEventRevision reports the snapshot revision rather than the
+// latest one in Postgres, so delta sync never pairs a revision with flag
+// state older than it.
+func (c *CachedRepository) GetLatestFlagEventRevision() (uint64, error) {
+	return c.Snapshot().Revision, nil
+}
+
+// Primary passes through to Postgres, so that writes are never checked
+// against a snapshot lagging behind writes made on other replicas.
+func (c *CachedRepository) Primary() IRepository {
+	return c.IRepository.Primary()
+}
+
+func (c *CachedRepository) GetFlagByName(name string) (*FeatureFlag, error) {
+	flag, ok := c.Snapshot().flagsByName[name]
+	if !ok {
+		return nil, nil
+	}
+	flagCopy := *flag
+	return &flagCopy, nil
+}
+
+func (c *CachedRepository) GetFlagByIds(flagIds []uint) ([]*FeatureFlag, error) {
+	return c.Snapshot().getFlags(flagIds), nil
+}
+
+func (c *CachedRepository) GetFlagById(flagId uint) (*FeatureFlag, error) {
+	flag, ok := c.Snapshot().flagsById[flagId]
+	if !ok {
+		return nil, fmt.Errorf("flag with id %d not found", flagId)
+	}
+	flagCopy := *flag
+	return &flagCopy, nil
+}
+
+func (c *CachedRepository) GetFlagDependencies(flag *FeatureFlag) ([]*FeatureFlag, error) {
+	snapshot := c.Snapshot()
+	return snapshot.getFlags(snapshot.dependsOn[flag.ID]), nil
+}
+
+func (c *CachedRepository) GetFlagDependents(flag *FeatureFlag) ([]*FeatureFlag, error) {
+	snapshot := c.Snapshot()
+	return snapshot.getFlags(snapshot.dependents[flag.ID]), nil
+}
+
+func (c *CachedRepository) GetAllFlags() ([]*FeatureFlag, error) {
+	snapshot := c.Snapshot()
+	flags := make([]*FeatureFlag, 0, len(snapshot.flags))
+	for _, flag := range snapshot.flags {
+		flagCopy := *flag
+		flags = append(flags, &flagCopy)
+	}
+	return flags, nil
+}
+
+func (c *CachedRepository) GetAllDependencies() ([]*FlagDependency, error) {
+	return c.Snapshot().dependencies, nil
+}
+
+func (c *CachedRepository) GetAllFlagTargets() ([]*FlagTarget, error) {
+	return c.Snapshot().targets, nil
+}
+
+func (c *CachedRepository) GetAllFlagTargetsByKey(targetingKey string) ([]*FlagTarget, error) {
+	return c.Snapshot().targetsByKey[targetingKey], nil
+}
+
+func (c *CachedRepository) GetAllTransitiveDependencies(flag *FeatureFlag) ([]*FeatureFlag, error) {
+	snapshot := c.Snapshot()
+
+	visited := make(map[uint]bool)
+	queue := append([]uint{}, snapshot.dependsOn[flag.ID]...)
+	for len(queue) > 0 {
+		flagId := queue[0]
+		queue = queue[1:]
+		if visited[flagId] {
+			continue
+		}
+		visited[flagId] = true
+		queue = append(queue, snapshot.dependsOn[flagId]...)
+	}
+
+	flagIds := make([]uint, 0, len(visited))
+	for flagId := range visited {
+		flagIds = append(flagIds, flagId)
+	}
+	sort.Slice(flagIds, func(i, j int) bool { return flagIds[i] < flagIds[j] })
+
+	return snapshot.getFlags(flagIds), nil
+}
+
+func (c *CachedRepository) GetDependenciesByFlagIds(flagIds []uint) ([]*FlagDependency, error) {
+	snapshot := c.Snapshot()
+	var dependencies []*FlagDependency
+	for _, flagId := range flagIds {
+		for _, dependsOnFlagId := range snapshot.dependsOn[flagId] {
+			dependencies = append(dependencies, &FlagDependency{FlagID: flagId, DependsOnFlagID: dependsOnFlagId})
+		}
+	}
+	return dependencies, nil
+}
+
+func (c *CachedRepository) GetFlagTargets(flag *FeatureFlag) ([]*FlagTarget, error) {
+	return c.Snapshot().targetsById[flag.ID], nil
+}
+
+func (c *CachedRepository) GetFlagTargetsByKey(flagIds []uint, targetingKey string) ([]*FlagTarget, error) {
+	inFlagIds := make(map[uint]bool, len(flagIds))
+	for _, flagId := range flagIds {
+		inFlagIds[flagId] = true
+	}
+
+	var targets []*FlagTarget
+	for _, target := range c.Snapshot().targetsByKey[targetingKey] {
+		if inFlagIds[target.FlagID] {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+func (c *CachedRepository) GetFlagTargetsByFlagIds(flagIds []uint) ([]*FlagTarget, error) {
+	snapshot := c.Snapshot()
+	var targets []*FlagTarget
+	for _, flagId := range flagIds {
+		targets = append(targets, snapshot.targetsById[flagId]...)
+	}
+	return targets, nil
+}
+
+func (c *CachedRepository) GetFlags(offset, limit int) ([]*FeatureFlag, uint, error) {
+	snapshot := c.Snapshot()
+	total := len(snapshot.flags)
+	start := min(offset, total)
+	end := min(start+limit, total)
+
+	flags := make([]*FeatureFlag, 0, end-start)
+	for _, flag := range snapshot.flags[start:end] {
+		flagCopy := *flag
+		flags = append(flags, &flagCopy)
+	}
+	return flags, uint(total), nil
+}
+
+// Writes go to Postgres and then sync the snapshot right away, so a replica
+// reads its own writes without waiting for the notification.
+
+// syncAfterWrite syncs the snapshot after a committed write. A failure is
+// not returned, as the write cannot be taken back: Sync counts it in
+// SyncFailures, exported as a metric, and the snapshot age keeps growing
+// until a sync succeeds. Writes are checked against Primary, so a stale
+// snapshot never lets an invalid write through.
+func (c *CachedRepository) syncAfterWrite() {
+	_ = c.Sync()
+}
+
+func (c *CachedRepository) CreateFlag(
+	ctx context.Context,
+	name string,
+	active bool,
+	dependecnyFlagIds []uint,
+) (*FeatureFlag, error) {
+	flag, err := c.IRepository.CreateFlag(ctx, name, active, dependecnyFlagIds)
+	if err != nil {
+		return nil, err
+	}
+	c.syncAfterWrite()
+	return flag, nil
+}
+
+func (c *CachedRepository) UpdateFlag(
+	ctx context.Context,
+	flag *FeatureFlag,
+	active bool,
+	reason string,
+) ([]*FeatureFlag, error) {
+	autoDisabled, err := c.IRepository.UpdateFlag(ctx, flag, active, reason)
+	if err != nil {
+		return nil, err
+	}
+	c.syncAfterWrite()
+	return autoDisabled, nil
+}
+
+func (c *CachedRepository) AddFlagTargets(
+	ctx context.Context,
+	flag *FeatureFlag,
+	list string,
+	targetingKeys []string,
+) error {
+	if err := c.IRepository.AddFlagTargets(ctx, flag, list, targetingKeys); err != nil {
+		return err
+	}
+	c.syncAfterWrite()
+	return nil
+}
+
+func (c *CachedRepository) RemoveFlagTargets(
+	ctx context.Context,
+	flag *FeatureFlag,
+	list string,
+	targetingKeys []string,
+) (uint, error) {
+	removed, err := c.IRepository.RemoveFlagTargets(ctx, flag, list, targetingKeys)
+	if err != nil {
+		return 0, err
+	}
+	c.syncAfterWrite()
+	return removed, nil
+}
+
+func (c *CachedRepository) UpdateFlagDependencies(
+	ctx context.Context,
+	flag *FeatureFlag,
+	dependencyFlagIds []uint,
+	reason string,
+) error {
+	if err := c.IRepository.UpdateFlagDependencies(ctx, flag, dependencyFlagIds, reason); err != nil {
+		return err
+	}
+	c.syncAfterWrite()
+	return nil
+}
+
+func (c *CachedRepository) RollbackFlag(
+	ctx context.Context,
+	flag *FeatureFlag,
+	version *FlagVersion,
+	reason string,
+) ([]*FeatureFlag, error) {
+	autoDisabled, err := c.IRepository.RollbackFlag(ctx, flag, version, reason)
+	if err != nil {
+		return nil, err
+	}
+	c.syncAfterWrite()
+	return autoDisabled, nil
+}
+
+func (c *CachedRepository) UndoFlagChange(
+	ctx context.Context,
+	change *FlagChange,
+	reason string,
+) (*FlagChange, []*FlagVersion, error) {
+	undo, versions, err := c.IRepository.UndoFlagChange(ctx, change, reason)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.syncAfterWrite()
+	return undo, versions, nil
+}
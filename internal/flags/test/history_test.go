@@ -0,0 +1,553 @@
+package flags_test
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	mockFlags "github.com/ArshiAbolghasemi/dom-cobb/internal/flags/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	mockLogger "github.com/ArshiAbolghasemi/dom-cobb/internal/logger/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/testutils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("History", func() {
+	var (
+		repo    *mockFlags.MockRepository
+		service *flags.Service
+		from    time.Time
+		to      time.Time
+	)
+
+	BeforeEach(func() {
+		repo = &mockFlags.MockRepository{}
+		service = &flags.Service{Repo: repo, Logger: &mockLogger.MockLogger{}}
+		from = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+		to = time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)
+	})
+
+	Describe("List Feature Flags As Of", func() {
+		It("should return a page of the flags as they were with their dependents", func() {
+			repo.On("GetFlagStatesAt", from).Return([]*flags.FlagVersion{
+				{FlagID: 1, Name: "checkout", Active: true},
+				{FlagID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}},
+				{FlagID: 3, Name: "wallet", Active: false, Dependencies: []uint{1, 2}},
+			}, nil)
+
+			result, err := service.ListFeatureFlagsAsOf(&flags.ListFeatureFlagsQueryParams{
+				PaginationQueryParam: api.PaginationQueryParam{Page: 1, Size: 2},
+				AsOf:                 from,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Total).To(Equal(uint(3)))
+			Expect(result.TotalPages).To(Equal(uint(2)))
+			Expect(result.Flags).To(Equal([]*flags.FeatureFlagData{
+				{ID: 1, Name: "checkout", Active: true, Dependencies: []uint{}, Dependents: []uint{2, 3}},
+				{ID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}, Dependents: []uint{3}},
+			}))
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should return an empty page past the last flag", func() {
+			repo.On("GetFlagStatesAt", from).Return([]*flags.FlagVersion{{FlagID: 1, Name: "checkout"}}, nil)
+
+			result, err := service.ListFeatureFlagsAsOf(&flags.ListFeatureFlagsQueryParams{
+				PaginationQueryParam: api.PaginationQueryParam{Page: 3, Size: 10},
+				AsOf:                 from,
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Flags).To(BeEmpty())
+			Expect(result.Total).To(Equal(uint(1)))
+		})
+	})
+
+	Describe("Get Feature Flag As Of", func() {
+		It("should return the flag as it was", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(true))
+			repo.On("GetFlagStatesAt", from).Return([]*flags.FlagVersion{
+				{FlagID: 1, Name: "checkout", Active: true},
+				{FlagID: 2, Name: "new-cart", Active: false, Dependencies: []uint{1}},
+			}, nil)
+
+			result, err := service.GetFeatureFlagAsOf(flag, from)
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&flags.FeatureFlagData{
+				ID: 2, Name: "new-cart", Active: false, Dependencies: []uint{1}, Dependents: []uint{},
+			}))
+		})
+
+		It("should return not found for a flag created later", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(4))
+			repo.On("GetFlagStatesAt", from).Return([]*flags.FlagVersion{{FlagID: 1, Name: "checkout"}}, nil)
+
+			result, err := service.GetFeatureFlagAsOf(flag, from)
+
+			Expect(result).To(BeNil())
+			Expect(err.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Validate Get Feature Flags Diff Request", func() {
+		It("should bind both times", func() {
+			c, _ := testutils.CreateJSONRequest(http.MethodGet,
+				"/api/v1/flags/diff?from=2026-03-01T10:00:00Z&to=2026-03-01T11:00:00Z", nil)
+
+			query, err := service.ValidateGetFeatureFlagsDiffRequest(c)
+
+			Expect(err).To(BeNil())
+			Expect(query.From).To(BeTemporally("==", from))
+			Expect(query.To).To(BeTemporally("==", to))
+		})
+
+		DescribeTable("should reject invalid times",
+			func(url string) {
+				c, _ := testutils.CreateJSONRequest(http.MethodGet, url, nil)
+
+				query, err := service.ValidateGetFeatureFlagsDiffRequest(c)
+
+				Expect(query).To(BeNil())
+				Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			},
+			Entry("missing from", "/api/v1/flags/diff?to=2026-03-01T11:00:00Z"),
+			Entry("missing to", "/api/v1/flags/diff?from=2026-03-01T10:00:00Z"),
+			Entry("malformed time", "/api/v1/flags/diff?from=yesterday&to=2026-03-01T11:00:00Z"),
+			Entry("from after to", "/api/v1/flags/diff?from=2026-03-01T11:00:00Z&to=2026-03-01T10:00:00Z"),
+		)
+	})
+
+	Describe("Get Feature Flags Diff", func() {
+		It("should list changed flags with their state at both times and the changes in between", func() {
+			toggled := &flags.FlagVersion{
+				FlagID: 1, Version: 2, Change: flags.FlagEventToggled, Name: "checkout", Reason: "incident",
+			}
+			autoDisabled := &flags.FlagVersion{
+				FlagID: 2, Version: 3, Change: flags.FlagEventAutoDisabled, Name: "new-cart", Reason: "incident",
+				CausedBy: 1,
+			}
+			created := &flags.FlagVersion{
+				FlagID: 4, Version: 1, Change: flags.FlagEventCreated, Name: "coupons", Dependencies: []uint{1},
+			}
+			repo.On("GetFlagStatesAt", from).Return([]*flags.FlagVersion{
+				{FlagID: 1, Name: "checkout", Active: true},
+				{FlagID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}},
+				{FlagID: 3, Name: "wallet", Active: true},
+			}, nil)
+			repo.On("GetFlagStatesAt", to).Return([]*flags.FlagVersion{
+				{FlagID: 1, Name: "checkout", Active: false},
+				{FlagID: 2, Name: "new-cart", Active: false, Dependencies: []uint{1}},
+				{FlagID: 3, Name: "wallet", Active: true},
+				{FlagID: 4, Name: "coupons", Active: false, Dependencies: []uint{1}},
+			}, nil)
+			repo.On("GetFlagVersionsBetween", from, to).Return([]*flags.FlagVersion{toggled, autoDisabled, created}, nil)
+
+			result, err := service.GetFeatureFlagsDiff(&flags.GetFeatureFlagsDiffQueryParams{From: from, To: to})
+
+			Expect(err).To(BeNil())
+			Expect(result.Flags).To(Equal([]*flags.FeatureFlagDiff{
+				{
+					ID:      1,
+					Before:  &flags.FeatureFlagStateData{Name: "checkout", Active: true, Dependencies: []uint{}},
+					After:   &flags.FeatureFlagStateData{Name: "checkout", Active: false, Dependencies: []uint{}},
+					Changes: []*flags.FlagVersion{toggled},
+				},
+				{
+					ID:      2,
+					Before:  &flags.FeatureFlagStateData{Name: "new-cart", Active: true, Dependencies: []uint{1}},
+					After:   &flags.FeatureFlagStateData{Name: "new-cart", Active: false, Dependencies: []uint{1}},
+					Changes: []*flags.FlagVersion{autoDisabled},
+				},
+				{
+					ID:      4,
+					After:   &flags.FeatureFlagStateData{Name: "coupons", Active: false, Dependencies: []uint{1}},
+					Changes: []*flags.FlagVersion{created},
+				},
+			}))
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should leave out flags that changed back", func() {
+			state := &flags.FlagVersion{FlagID: 1, Name: "checkout", Active: true, Dependencies: []uint{2}}
+			repo.On("GetFlagStatesAt", from).Return([]*flags.FlagVersion{state}, nil)
+			repo.On("GetFlagStatesAt", to).Return([]*flags.FlagVersion{state}, nil)
+			repo.On("GetFlagVersionsBetween", from, to).Return([]*flags.FlagVersion{
+				{FlagID: 1, Version: 2, Change: flags.FlagEventToggled, Name: "checkout", Active: false},
+				{FlagID: 1, Version: 3, Change: flags.FlagEventToggled, Name: "checkout", Active: true},
+			}, nil)
+
+			result, err := service.GetFeatureFlagsDiff(&flags.GetFeatureFlagsDiffQueryParams{From: from, To: to})
+
+			Expect(err).To(BeNil())
+			Expect(result.Flags).To(BeEmpty())
+		})
+	})
+
+	Describe("Get Feature Flag Versions Diff", func() {
+		It("should compare the state and dependencies of both versions", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(3))
+			repo.On("GetFlagVersion", flag, uint(1)).Return(&flags.FlagVersion{
+				FlagID: 3, Version: 1, Active: true, Dependencies: []uint{1, 2},
+			}, nil)
+			repo.On("GetFlagVersion", flag, uint(4)).Return(&flags.FlagVersion{
+				FlagID: 3, Version: 4, Active: false, Dependencies: []uint{2, 5},
+			}, nil)
+
+			result, err := service.GetFeatureFlagVersionsDiff(flag, &flags.GetFeatureFlagVersionsDiffQueryParams{From: 1, To: 4})
+
+			Expect(err).To(BeNil())
+			Expect(result.ActiveChanged).To(BeTrue())
+			Expect(result.AddedDependencies).To(Equal([]uint{5}))
+			Expect(result.RemovedDependencies).To(Equal([]uint{1}))
+		})
+
+		It("should return not found for an unknown version", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(3))
+			repo.On("GetFlagVersion", flag, uint(1)).Return(nil, nil)
+
+			result, err := service.GetFeatureFlagVersionsDiff(flag, &flags.GetFeatureFlagVersionsDiffQueryParams{From: 1, To: 2})
+
+			Expect(result).To(BeNil())
+			Expect(err.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Validate Rollback Feature Flag", func() {
+		var flag *flags.FeatureFlag
+
+		BeforeEach(func() {
+			flag = mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithIsActive(false))
+			repo.On("GetFlagById", uint(3)).Return(flag, nil)
+		})
+
+		It("should accept a version whose dependencies are active", func() {
+			version := &flags.FlagVersion{FlagID: 3, Version: 2, Active: true, Dependencies: []uint{1}}
+			dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			repo.On("GetFlagVersion", flag, uint(2)).Return(version, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("GetAllTransitiveDependencies", dependency).Return([]*flags.FeatureFlag{}, nil)
+
+			resultFlag, resultVersion, err := service.ValidateRollbackFeatureFlag(3, &flags.RollbackFeatureFlagRequest{
+				Version: 2,
+				Reason:  "revert",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(resultFlag).To(Equal(flag))
+			Expect(resultVersion).To(Equal(version))
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should reject an active version whose dependencies are inactive now", func() {
+			dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(false))
+			repo.On("GetFlagVersion", flag, uint(2)).Return(&flags.FlagVersion{
+				FlagID: 3, Version: 2, Active: true, Dependencies: []uint{1},
+			}, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("GetAllTransitiveDependencies", dependency).Return([]*flags.FeatureFlag{}, nil)
+
+			_, _, err := service.ValidateRollbackFeatureFlag(3, &flags.RollbackFeatureFlagRequest{Version: 2, Reason: "revert"})
+
+			Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(err.Message).To(ContainSubstring("Inactive dependency IDs: [1]"))
+		})
+
+		It("should reject a version whose dependencies would create a cycle", func() {
+			dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1))
+			repo.On("GetFlagVersion", flag, uint(2)).Return(&flags.FlagVersion{
+				FlagID: 3, Version: 2, Active: false, Dependencies: []uint{1},
+			}, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("GetAllTransitiveDependencies", dependency).Return(mockFlags.CreateFeatureFlagByIds([]uint{3}), nil)
+
+			_, _, err := service.ValidateRollbackFeatureFlag(3, &flags.RollbackFeatureFlagRequest{Version: 2, Reason: "revert"})
+
+			Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(err.Message).To(ContainSubstring("Circular dependency"))
+		})
+
+		It("should report a version matching the current state", func() {
+			dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1))
+			repo.On("GetFlagVersion", flag, uint(2)).Return(&flags.FlagVersion{
+				FlagID: 3, Version: 2, Active: false, Dependencies: []uint{1},
+			}, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{dependency}, nil)
+
+			_, _, err := service.ValidateRollbackFeatureFlag(3, &flags.RollbackFeatureFlagRequest{Version: 2, Reason: "revert"})
+
+			Expect(err).To(Equal(api.OKError("Flag is already at version 2", "")))
+		})
+
+		It("should accept a version that differs only in its targets", func() {
+			dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			version := &flags.FlagVersion{
+				FlagID: 3, Version: 2, Active: false, Dependencies: []uint{1},
+				Allow: []string{"user-1"}, Deny: []string{},
+			}
+			repo.On("GetFlagVersion", flag, uint(2)).Return(version, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("GetFlagTargets", flag).Return([]*flags.FlagTarget{
+				{FlagID: 3, TargetingKey: "user-2", List: flags.TargetListAllow},
+			}, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("GetAllTransitiveDependencies", dependency).Return([]*flags.FeatureFlag{}, nil)
+
+			_, resultVersion, err := service.ValidateRollbackFeatureFlag(3, &flags.RollbackFeatureFlagRequest{Version: 2, Reason: "revert"})
+
+			Expect(err).To(BeNil())
+			Expect(resultVersion).To(Equal(version))
+		})
+
+		It("should return not found for an unknown version", func() {
+			repo.On("GetFlagVersion", flag, uint(9)).Return(nil, nil)
+
+			_, _, err := service.ValidateRollbackFeatureFlag(3, &flags.RollbackFeatureFlagRequest{Version: 9, Reason: "revert"})
+
+			Expect(err.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Rollback Feature Flag", func() {
+		It("should log the rollback and the dependents it switched off", func() {
+			log := &mockLogger.MockLogger{}
+			service.Logger = log
+			ctx := logger.WithActor(context.Background(), "alice")
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"), mockFlags.WithIsActive(true))
+			dependent := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(false))
+			version := &flags.FlagVersion{FlagID: 1, Version: 4, Active: false, Dependencies: []uint{}}
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagDependencies", dependent).Return([]*flags.FeatureFlag{flag}, nil)
+			repo.On("RollbackFlag", flag, version, "revert").Run(func(args mock.Arguments) {
+				args.Get(0).(*flags.FeatureFlag).IsActive = false
+			}).Return([]*flags.FeatureFlag{dependent}, nil)
+
+			var entry *logger.LogEntry
+			log.On("Log", mock.AnythingOfType("*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entry = args.Get(0).(*logger.LogEntry)
+			}).Return(nil)
+			var entries []*logger.LogEntry
+			log.On("LogBatch", mock.AnythingOfType("[]*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entries = args.Get(0).([]*logger.LogEntry)
+			}).Return(nil)
+
+			err := service.RollbackFeatureFlag(ctx, flag, version, &flags.RollbackFeatureFlagRequest{Version: 4, Reason: "revert"})
+
+			Expect(err).To(BeNil())
+			Expect(entry.Action).To(Equal(logger.ActionRolledBack))
+			Expect(entry.Actor).To(Equal("alice"))
+			Expect(entry.Before).To(Equal(&logger.FlagState{Name: "checkout", IsActive: true, Dependencies: []uint{}}))
+			Expect(entry.After).To(Equal(&logger.FlagState{Name: "checkout", IsActive: false, Dependencies: []uint{}}))
+			Expect(entry.Metadata).To(HaveKeyWithValue("version", uint(4)))
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].CausedBy).To(Equal(uint(1)))
+			repo.AssertExpectations(GinkgoT())
+			log.AssertExpectations(GinkgoT())
+		})
+	})
+
+	Describe("Validate Undo Flag Change", func() {
+		var (
+			change     *flags.FlagChange
+			checkout   *flags.FeatureFlag
+			newCart    *flags.FeatureFlag
+			checkoutV2 *flags.FlagVersion
+			newCartV3  *flags.FlagVersion
+		)
+
+		BeforeEach(func() {
+			change = &flags.FlagChange{ID: 7, Type: flags.FlagEventToggled, FlagID: 1}
+			checkout = mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(false))
+			newCart = mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(false))
+			checkoutV2 = &flags.FlagVersion{ChangeID: 7, FlagID: 1, Version: 2, Active: false, Dependencies: []uint{}}
+			newCartV3 = &flags.FlagVersion{
+				ChangeID: 7, FlagID: 2, Version: 3, Change: flags.FlagEventAutoDisabled, Dependencies: []uint{1},
+			}
+			repo.On("GetFlagChange", uint64(7)).Return(change, nil)
+			repo.On("GetFlagChangeVersions", change).Return([]*flags.FlagVersion{checkoutV2, newCartV3}, nil)
+			repo.On("GetFlagById", uint(1)).Return(checkout, nil)
+			repo.On("GetFlagById", uint(2)).Return(newCart, nil)
+		})
+
+		It("should accept a dependent restored together with its dependency", func() {
+			repo.On("GetFlagVersions", checkout, 0, 1).Return([]*flags.FlagVersion{checkoutV2}, uint(2), nil)
+			repo.On("GetFlagVersions", newCart, 0, 1).Return([]*flags.FlagVersion{newCartV3}, uint(3), nil)
+			repo.On("GetFlagVersion", checkout, uint(1)).Return(&flags.FlagVersion{
+				FlagID: 1, Version: 1, Active: true, Dependencies: []uint{},
+			}, nil)
+			repo.On("GetFlagVersion", newCart, uint(2)).Return(&flags.FlagVersion{
+				FlagID: 2, Version: 2, Active: true, Dependencies: []uint{1},
+			}, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{checkout}, nil)
+
+			resultChange, resultVersions, err := service.ValidateUndoFlagChange(7)
+
+			Expect(err).To(BeNil())
+			Expect(resultChange).To(Equal(change))
+			Expect(resultVersions).To(Equal([]*flags.FlagVersion{checkoutV2, newCartV3}))
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should accept a diamond cascade that recorded a dependent twice", func() {
+			repo.ExpectedCalls = nil
+			diamond := []*flags.FeatureFlag{
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(false)),
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(false)),
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithIsActive(false)),
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(4), mockFlags.WithIsActive(false)),
+			}
+			dependencies := [][]uint{{}, {1}, {1}, {2, 3}}
+			var versions []*flags.FlagVersion
+			for i, flag := range diamond {
+				versions = append(versions, &flags.FlagVersion{
+					ChangeID: 7, FlagID: flag.ID, Version: 2, Dependencies: dependencies[i],
+				})
+				repo.On("GetFlagById", flag.ID).Return(flag, nil)
+				repo.On("GetFlagVersion", flag, uint(1)).Return(&flags.FlagVersion{
+					FlagID: flag.ID, Version: 1, Active: true, Dependencies: dependencies[i],
+				}, nil)
+			}
+			dLatest := &flags.FlagVersion{ChangeID: 7, FlagID: 4, Version: 3, Dependencies: []uint{2, 3}}
+			versions = append(versions, dLatest)
+			for _, version := range versions[:3] {
+				repo.On("GetFlagVersions", diamond[version.FlagID-1], 0, 1).Return([]*flags.FlagVersion{version}, uint(2), nil)
+			}
+			repo.On("GetFlagVersions", diamond[3], 0, 1).Return([]*flags.FlagVersion{dLatest}, uint(3), nil)
+			repo.On("GetFlagChange", uint64(7)).Return(change, nil)
+			repo.On("GetFlagChangeVersions", change).Return(versions, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{diamond[0]}, nil)
+			repo.On("GetFlagByIds", []uint{2, 3}).Return([]*flags.FeatureFlag{diamond[1], diamond[2]}, nil)
+
+			_, resultVersions, err := service.ValidateUndoFlagChange(7)
+
+			Expect(err).To(BeNil())
+			Expect(resultVersions).To(Equal(versions))
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should reject a change whose flags were changed since", func() {
+			repo.On("GetFlagVersions", checkout, 0, 1).Return([]*flags.FlagVersion{{FlagID: 1, Version: 3}}, uint(3), nil)
+
+			_, _, err := service.ValidateUndoFlagChange(7)
+
+			Expect(err.StatusCode).To(Equal(http.StatusConflict))
+			Expect(err.Message).To(ContainSubstring("Feature flag 1"))
+		})
+
+		It("should reject restoring an active flag whose dependency stays inactive", func() {
+			repo.ExpectedCalls = nil
+			repo.On("GetFlagChange", uint64(7)).Return(change, nil)
+			repo.On("GetFlagChangeVersions", change).Return([]*flags.FlagVersion{newCartV3}, nil)
+			repo.On("GetFlagById", uint(2)).Return(newCart, nil)
+			repo.On("GetFlagVersions", newCart, 0, 1).Return([]*flags.FlagVersion{newCartV3}, uint(3), nil)
+			repo.On("GetFlagVersion", newCart, uint(2)).Return(&flags.FlagVersion{
+				FlagID: 2, Version: 2, Active: true, Dependencies: []uint{1},
+			}, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{checkout}, nil)
+
+			_, _, err := service.ValidateUndoFlagChange(7)
+
+			Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(err.Message).To(ContainSubstring("Inactive dependency IDs: [1]"))
+		})
+
+		It("should reject a change without a version before it", func() {
+			repo.ExpectedCalls = nil
+			created := &flags.FlagVersion{ChangeID: 7, FlagID: 1, Version: 1, Change: flags.FlagEventCreated}
+			repo.On("GetFlagChange", uint64(7)).Return(change, nil)
+			repo.On("GetFlagChangeVersions", change).Return([]*flags.FlagVersion{created}, nil)
+			repo.On("GetFlagById", uint(1)).Return(checkout, nil)
+			repo.On("GetFlagVersions", checkout, 0, 1).Return([]*flags.FlagVersion{created}, uint(1), nil)
+
+			_, _, err := service.ValidateUndoFlagChange(7)
+
+			Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(err.Error).To(Equal("Change cannot be undone"))
+		})
+
+		It("should return not found for an unknown change", func() {
+			repo.On("GetFlagChange", uint64(9)).Return(nil, nil)
+
+			_, _, err := service.ValidateUndoFlagChange(9)
+
+			Expect(err.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Undo Flag Change", func() {
+		var (
+			change   *flags.FlagChange
+			versions []*flags.FlagVersion
+		)
+
+		BeforeEach(func() {
+			change = &flags.FlagChange{ID: 7, Type: flags.FlagEventDependencies, FlagID: 1}
+			versions = []*flags.FlagVersion{
+				{ChangeID: 7, FlagID: 1, Version: 2, Name: "checkout", Active: true, Dependencies: []uint{3}},
+			}
+		})
+
+		It("should link the entries of the undo and its cascade to the change", func() {
+			log := &mockLogger.MockLogger{}
+			service.Logger = log
+			ctx := logger.WithActor(context.Background(), "alice")
+			undo := &flags.FlagChange{ID: 8, Type: flags.FlagVersionUndone, FlagID: 1, Undoes: 7}
+			undone := []*flags.FlagVersion{
+				{
+					ChangeID: 8, FlagID: 1, Version: 3, Change: flags.FlagVersionUndone, Name: "checkout",
+					Active: false, Dependencies: []uint{}, RolledBackTo: 1,
+				},
+				{
+					ChangeID: 8, FlagID: 2, Version: 5, Change: flags.FlagEventAutoDisabled, Name: "new-cart",
+					Active: false, Dependencies: []uint{1}, CausedBy: 1,
+				},
+			}
+			repo.On("UndoFlagChange", change, "revert").Return(undo, undone, nil)
+
+			var entries []*logger.LogEntry
+			log.On("LogBatch", mock.AnythingOfType("[]*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entries = args.Get(0).([]*logger.LogEntry)
+			}).Return(nil)
+
+			result, err := service.UndoFlagChange(ctx, change, versions, &flags.UndoFlagChangeRequest{Reason: "revert"})
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(&flags.FlagChangeData{Change: undo, Versions: undone}))
+			Expect(entries).To(HaveLen(2))
+			Expect(entries[0].Action).To(Equal(logger.ActionUndone))
+			Expect(entries[0].Actor).To(Equal("alice"))
+			Expect(entries[0].EntityID).To(Equal(uint(1)))
+			Expect(entries[0].Before).To(Equal(&logger.FlagState{Name: "checkout", IsActive: true, Dependencies: []uint{3}}))
+			Expect(entries[0].After).To(Equal(&logger.FlagState{Name: "checkout", IsActive: false, Dependencies: []uint{}}))
+			Expect(entries[0].Metadata).To(Equal(map[string]any{
+				"change_id": uint64(8), "undoes": uint64(7), "version": uint(1),
+			}))
+			Expect(entries[1].Action).To(Equal(logger.ActionAutoDisabled))
+			Expect(entries[1].EntityID).To(Equal(uint(2)))
+			Expect(entries[1].CausedBy).To(Equal(uint(1)))
+			Expect(entries[1].Before.IsActive).To(BeTrue())
+			Expect(entries[1].Metadata).To(Equal(map[string]any{"change_id": uint64(8), "undoes": uint64(7)}))
+			repo.AssertExpectations(GinkgoT())
+			log.AssertExpectations(GinkgoT())
+		})
+
+		It("should return conflict when a flag changed before the undo committed", func() {
+			repo.On("UndoFlagChange", change, "revert").Return(nil, nil, flags.ErrChangeSuperseded)
+
+			result, err := service.UndoFlagChange(context.Background(), change, versions, &flags.UndoFlagChangeRequest{
+				Reason: "revert",
+			})
+
+			Expect(result).To(BeNil())
+			Expect(err.StatusCode).To(Equal(http.StatusConflict))
+		})
+	})
+})
@@ -1,6 +1,9 @@
 package mock
 
 import (
+	"context"
+	"time"
+
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
 	"github.com/stretchr/testify/mock"
@@ -50,15 +53,24 @@ func (m *MockRepository) GetFlagDependents(flag *flags.FeatureFlag) ([]*flags.Fe
 	return args.Get(0).([]*flags.FeatureFlag), args.Error(1)
 }
 
-func (m *MockRepository) GetFeatureFlagLogs(flag *flags.FeatureFlag, page, size uint) ([]*logger.LogEntry, uint, uint, error) {
-	args := m.Called(flag, page, size)
+func (m *MockRepository) GetFeatureFlagLogs(
+	flag *flags.FeatureFlag,
+	filter *logger.Filter,
+	page, size uint,
+) ([]*logger.LogEntry, uint, uint, error) {
+	args := m.Called(flag, filter, page, size)
 	if args.Get(0) == nil {
 		return nil, args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
 	}
 	return args.Get(0).([]*logger.LogEntry), args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
 }
 
-func (m *MockRepository) CreateFlag(name string, isActive bool, dependencies []uint) (*flags.FeatureFlag, error) {
+func (m *MockRepository) CreateFlag(
+	ctx context.Context,
+	name string,
+	isActive bool,
+	dependencies []uint,
+) (*flags.FeatureFlag, error) {
 	args := m.Called(name, isActive, dependencies)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -66,7 +78,241 @@ func (m *MockRepository) CreateFlag(name string, isActive bool, dependencies []u
 	return args.Get(0).(*flags.FeatureFlag), args.Error(1)
 }
 
-func (m *MockRepository) UpdateFlag(flag *flags.FeatureFlag, isActive bool) error {
-	args := m.Called(flag, isActive)
+func (m *MockRepository) UpdateFlag(
+	ctx context.Context,
+	flag *flags.FeatureFlag,
+	isActive bool,
+	reason string,
+) ([]*flags.FeatureFlag, error) {
+	args := m.Called(flag, isActive, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) GetAllTransitiveDependencies(flag *flags.FeatureFlag) ([]*flags.FeatureFlag, error) {
+	args := m.Called(flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) GetDependenciesByFlagIds(ids []uint) ([]*flags.FlagDependency, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagDependency), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagTargets(flag *flags.FeatureFlag) ([]*flags.FlagTarget, error) {
+	args := m.Called(flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagTarget), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagTargetsByKey(ids []uint, targetingKey string) ([]*flags.FlagTarget, error) {
+	args := m.Called(ids, targetingKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagTarget), args.Error(1)
+}
+
+func (m *MockRepository) AddFlagTargets(
+	ctx context.Context,
+	flag *flags.FeatureFlag,
+	list string,
+	targetingKeys []string,
+) error {
+	args := m.Called(flag, list, targetingKeys)
 	return args.Error(0)
 }
+
+func (m *MockRepository) RemoveFlagTargets(
+	ctx context.Context,
+	flag *flags.FeatureFlag,
+	list string,
+	targetingKeys []string,
+) (uint, error) {
+	args := m.Called(flag, list, targetingKeys)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockRepository) GetAllFlags() ([]*flags.FeatureFlag, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) GetAllDependencies() ([]*flags.FlagDependency, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagDependency), args.Error(1)
+}
+
+func (m *MockRepository) GetAllFlagTargetsByKey(targetingKey string) ([]*flags.FlagTarget, error) {
+	args := m.Called(targetingKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagTarget), args.Error(1)
+}
+
+func (m *MockRepository) GetAllFlagTargets() ([]*flags.FlagTarget, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagTarget), args.Error(1)
+}
+
+func (m *MockRepository) GetLatestFlagEventRevision() (uint64, error) {
+	args := m.Called()
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagEventsSince(revision uint64, limit int) ([]*flags.FlagEvent, error) {
+	args := m.Called(revision, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagEvent), args.Error(1)
+}
+
+func (m *MockRepository) GetChangedFlagIds(since, until uint64) ([]uint, error) {
+	args := m.Called(since, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagTargetsByFlagIds(ids []uint) ([]*flags.FlagTarget, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagTarget), args.Error(1)
+}
+
+func (m *MockRepository) GetFlags(offset, limit int) ([]*flags.FeatureFlag, uint, error) {
+	args := m.Called(offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(uint), args.Error(2)
+	}
+	return args.Get(0).([]*flags.FeatureFlag), args.Get(1).(uint), args.Error(2)
+}
+
+func (m *MockRepository) UpdateFlagDependencies(
+	ctx context.Context,
+	flag *flags.FeatureFlag,
+	dependencyFlagIds []uint,
+	reason string,
+) error {
+	args := m.Called(flag, dependencyFlagIds, reason)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetFlagStatesAt(at time.Time) ([]*flags.FlagVersion, error) {
+	args := m.Called(at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagVersion), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagVersionsBetween(from, to time.Time) ([]*flags.FlagVersion, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagVersion), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagVersions(flag *flags.FeatureFlag, offset, limit int) ([]*flags.FlagVersion, uint, error) {
+	args := m.Called(flag, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(uint), args.Error(2)
+	}
+	return args.Get(0).([]*flags.FlagVersion), args.Get(1).(uint), args.Error(2)
+}
+
+func (m *MockRepository) GetFlagVersion(flag *flags.FeatureFlag, version uint) (*flags.FlagVersion, error) {
+	args := m.Called(flag, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*flags.FlagVersion), args.Error(1)
+}
+
+func (m *MockRepository) RollbackFlag(
+	ctx context.Context,
+	flag *flags.FeatureFlag,
+	version *flags.FlagVersion,
+	reason string,
+) ([]*flags.FeatureFlag, error) {
+	args := m.Called(flag, version, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FeatureFlag), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagChange(changeId uint64) (*flags.FlagChange, error) {
+	args := m.Called(changeId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*flags.FlagChange), args.Error(1)
+}
+
+func (m *MockRepository) GetFlagChangeVersions(change *flags.FlagChange) ([]*flags.FlagVersion, error) {
+	args := m.Called(change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*flags.FlagVersion), args.Error(1)
+}
+
+func (m *MockRepository) UndoFlagChange(
+	ctx context.Context,
+	change *flags.FlagChange,
+	reason string,
+) (*flags.FlagChange, []*flags.FlagVersion, error) {
+	args := m.Called(change, reason)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*flags.FlagChange), args.Get(1).([]*flags.FlagVersion), args.Error(2)
+}
+
+func (m *MockRepository) GetFeatureFlagLogsPage(
+	flag *flags.FeatureFlag,
+	filter *logger.Filter,
+	cursor *logger.PageCursor,
+	size uint,
+) (*logger.LogPage, error) {
+	args := m.Called(flag, filter, cursor, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*logger.LogPage), args.Error(1)
+}
+
+func (m *MockRepository) CountFeatureFlagLogs(flag *flags.FeatureFlag, filter *logger.Filter) (uint, error) {
+	args := m.Called(flag, filter)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockRepository) Primary() flags.IRepository {
+	return m
+}
@@ -0,0 +1,136 @@
+package flags_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	gormPostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTestRepository returns a repository on a fresh schema of the Postgres
+// database configured by the environment, and skips the spec without one.
+func newTestRepository() *flags.Repository {
+	if _, exists := os.LookupEnv("POSTGRES_HOST"); !exists {
+		Skip("Postgres is not configured")
+	}
+	dsn, err := postgres.GetDSN()
+	Expect(err).NotTo(HaveOccurred())
+
+	db, err := gorm.Open(gormPostgres.Open(dsn), &gorm.Config{})
+	Expect(err).NotTo(HaveOccurred())
+	schema := fmt.Sprintf("flags_test_%d", time.Now().UnixNano())
+	Expect(db.Exec("CREATE SCHEMA " + schema).Error).To(Succeed())
+	DeferCleanup(func() {
+		Expect(db.Exec("DROP SCHEMA " + schema + " CASCADE").Error).To(Succeed())
+	})
+
+	db, err = gorm.Open(gormPostgres.Open(dsn+" search_path="+schema), &gorm.Config{})
+	Expect(err).NotTo(HaveOccurred())
+	migrations, err := postgres.GetMigrations()
+	Expect(err).NotTo(HaveOccurred())
+	_, err = postgres.NewMigrator(db, migrations).Up()
+	Expect(err).NotTo(HaveOccurred())
+
+	return flags.NewRepository(db, nil)
+}
+
+func flagEventsOf(repo *flags.Repository, flagId uint, eventType string) []*flags.FlagEvent {
+	events, err := repo.GetFlagEventsSince(0, 1000)
+	Expect(err).NotTo(HaveOccurred())
+
+	var matched []*flags.FlagEvent
+	for _, event := range events {
+		if event.FlagID == flagId && event.Type == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+var _ = Describe("Repository", func() {
+	var (
+		ctx  context.Context
+		repo *flags.Repository
+
+		a, b, c, d *flags.FeatureFlag
+	)
+
+	// createDiamond creates a with b and c depending on it, and d depending
+	// on both, so that two paths lead from a to d.
+	createDiamond := func() {
+		var err error
+		a, err = repo.CreateFlag(ctx, "a", true, nil)
+		Expect(err).NotTo(HaveOccurred())
+		b, err = repo.CreateFlag(ctx, "b", true, []uint{a.ID})
+		Expect(err).NotTo(HaveOccurred())
+		c, err = repo.CreateFlag(ctx, "c", true, []uint{a.ID})
+		Expect(err).NotTo(HaveOccurred())
+		d, err = repo.CreateFlag(ctx, "d", true, []uint{b.ID, c.ID})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		repo = newTestRepository()
+		createDiamond()
+	})
+
+	Describe("Update flag", func() {
+		It("should auto disable a dependent reached by two paths once", func() {
+			autoDisabled, err := repo.UpdateFlag(ctx, a, false, "incident")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(autoDisabled).To(HaveLen(3))
+			for _, dependent := range []*flags.FeatureFlag{b, c, d} {
+				events := flagEventsOf(repo, dependent.ID, flags.FlagEventAutoDisabled)
+				Expect(events).To(HaveLen(1), dependent.Name)
+				Expect(events[0].CausedBy).To(Equal(a.ID))
+			}
+		})
+
+		It("should version a dependent reached by two paths once", func() {
+			_, err := repo.UpdateFlag(ctx, a, false, "incident")
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, total, err := repo.GetFlagVersions(d, 0, 10)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(total).To(Equal(uint(2)))
+			Expect(versions[0].Version).To(Equal(uint(2)))
+			Expect(versions[0].Change).To(Equal(flags.FlagEventAutoDisabled))
+		})
+	})
+
+	Describe("Undo flag change", func() {
+		It("should undo a cascade through a diamond", func() {
+			_, err := repo.UpdateFlag(ctx, a, false, "incident")
+			Expect(err).NotTo(HaveOccurred())
+			versions, _, err := repo.GetFlagVersions(a, 0, 1)
+			Expect(err).NotTo(HaveOccurred())
+			change, err := repo.GetFlagChange(versions[0].ChangeID)
+			Expect(err).NotTo(HaveOccurred())
+
+			changed, err := repo.GetFlagChangeVersions(change)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changed).To(HaveLen(4))
+
+			undo, undone, err := repo.UndoFlagChange(ctx, change, "revert")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(undo.Undoes).To(Equal(change.ID))
+			Expect(undone).To(HaveLen(4))
+			for _, flag := range []*flags.FeatureFlag{a, b, c, d} {
+				restored, err := repo.GetFlagById(flag.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(restored.IsActive).To(BeTrue(), flag.Name)
+			}
+		})
+	})
+})
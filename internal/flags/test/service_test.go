@@ -1,14 +1,21 @@
 package flags_test
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"net/http"
 
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
 	mockFlags "github.com/ArshiAbolghasemi/dom-cobb/internal/flags/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
 	mockLogger "github.com/ArshiAbolghasemi/dom-cobb/internal/logger/test/mock"
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/testutils"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/snapshot"
 	"github.com/brianvoe/gofakeit/v7"
+	"github.com/gin-gonic/gin"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/stretchr/testify/mock"
@@ -254,8 +261,9 @@ var _ = Describe("Service", func() {
 				repo.On("CreateFlag", req.Name, req.IsActive, req.FeatureFlagIDDependencies).Return(flag, nil)
 				logger.On("Log", mock.AnythingOfType("*logger.LogEntry")).Return(nil)
 
-				result := service.CreateFeatureFlag(req)
+				created, result := service.CreateFeatureFlag(context.Background(), req)
 				Expect(result).To(BeNil())
+				Expect(created).To(Equal(flag))
 			})
 		})
 
@@ -270,9 +278,458 @@ var _ = Describe("Service", func() {
 				err := gofakeit.ErrorDatabase()
 				repo.On("CreateFlag", req.Name, req.IsActive, req.FeatureFlagIDDependencies).Return(nil, err)
 
-				result := service.CreateFeatureFlag(req)
+				created, result := service.CreateFeatureFlag(context.Background(), req)
+				Expect(created).To(BeNil())
 				Expect(result).To(Equal(api.InternalServerError("Internal Server Error", err.Error())))
 			})
 		})
 	})
+
+	Describe("Update Feature Flag", func() {
+		var (
+			repo    *mockFlags.MockRepository
+			log     *mockLogger.MockLogger
+			service *flags.Service
+			ctx     context.Context
+		)
+
+		BeforeEach(func() {
+			repo = &mockFlags.MockRepository{}
+			log = &mockLogger.MockLogger{}
+			service = &flags.Service{Repo: repo, Logger: log}
+			ctx = logger.WithActor(logger.WithRequestID(context.Background(), "req-1"), "alice")
+		})
+
+		AfterEach(func() {
+			repo.AssertExpectations(GinkgoT())
+			log.AssertExpectations(GinkgoT())
+		})
+
+		It("should log the state before and after the change", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithName("checkout"), mockFlags.WithIsActive(false))
+			dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{dependency}, nil)
+			repo.On("UpdateFlag", flag, true, "launch").Run(func(args mock.Arguments) {
+				args.Get(0).(*flags.FeatureFlag).IsActive = true
+			}).Return(nil, nil)
+
+			var entry *logger.LogEntry
+			log.On("Log", mock.AnythingOfType("*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entry = args.Get(0).(*logger.LogEntry)
+			}).Return(nil)
+			log.On("LogBatch", []*logger.LogEntry{}).Return(nil)
+
+			result := service.UpdateFeatureFlag(ctx, flag, &flags.UpdateFeatureFlagRequest{IsActive: true, Reason: "launch"})
+
+			Expect(result).To(BeNil())
+			Expect(entry.SchemaVersion).To(Equal(logger.SchemaVersion))
+			Expect(entry.Action).To(Equal(logger.ActionToggled))
+			Expect(entry.EntityType).To(Equal(logger.EntityTypeFlag))
+			Expect(entry.EntityID).To(Equal(uint(2)))
+			Expect(entry.Reason).To(Equal("launch"))
+			Expect(entry.RequestID).To(Equal("req-1"))
+			Expect(entry.Actor).To(Equal("alice"))
+			Expect(entry.Before).To(Equal(&logger.FlagState{Name: "checkout", IsActive: false, Dependencies: []uint{1}}))
+			Expect(entry.After).To(Equal(&logger.FlagState{Name: "checkout", IsActive: true, Dependencies: []uint{1}}))
+		})
+
+		It("should log every dependent the deactivation switched off", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			dependent := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithName("checkout"), mockFlags.WithIsActive(false))
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagDependencies", dependent).Return([]*flags.FeatureFlag{flag}, nil)
+			repo.On("UpdateFlag", flag, false, "incident").Return([]*flags.FeatureFlag{dependent}, nil)
+			log.On("Log", mock.AnythingOfType("*logger.LogEntry")).Return(nil)
+
+			var entries []*logger.LogEntry
+			log.On("LogBatch", mock.AnythingOfType("[]*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entries = args.Get(0).([]*logger.LogEntry)
+			}).Return(nil)
+
+			result := service.UpdateFeatureFlag(ctx, flag, &flags.UpdateFeatureFlagRequest{IsActive: false, Reason: "incident"})
+
+			Expect(result).To(BeNil())
+			Expect(entries).To(HaveLen(1))
+			Expect(entries[0].Action).To(Equal(logger.ActionAutoDisabled))
+			Expect(entries[0].EntityID).To(Equal(uint(2)))
+			Expect(entries[0].CausedBy).To(Equal(uint(1)))
+			Expect(entries[0].Reason).To(Equal("incident"))
+			Expect(entries[0].RequestID).To(Equal("req-1"))
+			Expect(entries[0].Actor).To(Equal("alice"))
+			Expect(entries[0].Before).To(Equal(&logger.FlagState{Name: "checkout", IsActive: true, Dependencies: []uint{1}}))
+			Expect(entries[0].After).To(Equal(&logger.FlagState{Name: "checkout", IsActive: false, Dependencies: []uint{1}}))
+		})
+
+		It("should not change the flag when its dependencies cannot be read", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			err := gofakeit.ErrorDatabase()
+			repo.On("GetFlagDependencies", flag).Return(nil, err)
+
+			result := service.UpdateFeatureFlag(ctx, flag, &flags.UpdateFeatureFlagRequest{IsActive: false, Reason: "incident"})
+
+			Expect(result).To(Equal(api.InternalServerError("Internal Server Error", err.Error())))
+		})
+	})
+
+	Describe("Validate Feature Flag Targets Request", func() {
+		When("target list is unknown", func() {
+			It("should return bad request error", func() {
+				repo := &mockFlags.MockRepository{}
+				logger := &mockLogger.MockLogger{}
+				service := &flags.Service{Repo: repo, Logger: logger}
+
+				req := flags.FeatureFlagTargetsRequest{TargetingKeys: []string{"user-1"}}
+				c, _ := testutils.CreateJSONRequest(http.MethodPost, "/api/v1/flags/1/targets/maybe", req)
+				c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "list", Value: "maybe"}}
+
+				flag, list, result, err := service.ValidateFeatureFlagTargetsRequest(c)
+
+				Expect(err).NotTo(BeNil())
+				Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(flag).To(BeNil())
+				Expect(list).To(BeEmpty())
+				Expect(result).To(BeNil())
+
+				repo.AssertExpectations(GinkgoT())
+				logger.AssertExpectations(GinkgoT())
+			})
+		})
+
+		When("targeting keys are duplicated", func() {
+			It("should return the request with unique keys", func() {
+				repo := &mockFlags.MockRepository{}
+				flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1))
+				repo.On("GetFlagById", uint(1)).Return(flag, nil)
+				logger := &mockLogger.MockLogger{}
+				service := &flags.Service{Repo: repo, Logger: logger}
+
+				req := flags.FeatureFlagTargetsRequest{TargetingKeys: []string{"user-1", "user-2", "user-1"}}
+				c, _ := testutils.CreateJSONRequest(http.MethodPost, "/api/v1/flags/1/targets/allow", req)
+				c.Params = gin.Params{{Key: "id", Value: "1"}, {Key: "list", Value: flags.TargetListAllow}}
+
+				resultFlag, list, result, err := service.ValidateFeatureFlagTargetsRequest(c)
+
+				Expect(err).To(BeNil())
+				Expect(resultFlag).To(Equal(flag))
+				Expect(list).To(Equal(flags.TargetListAllow))
+				Expect(result.TargetingKeys).To(Equal([]string{"user-1", "user-2"}))
+
+				repo.AssertExpectations(GinkgoT())
+				logger.AssertExpectations(GinkgoT())
+			})
+		})
+	})
+
+	Describe("Evaluate Feature Flag", func() {
+		var (
+			repo    *mockFlags.MockRepository
+			logger  *mockLogger.MockLogger
+			service *flags.Service
+		)
+
+		BeforeEach(func() {
+			repo = &mockFlags.MockRepository{}
+			logger = &mockLogger.MockLogger{}
+			service = &flags.Service{
+				Repo:   repo,
+				Logger: logger,
+			}
+		})
+
+		AfterEach(func() {
+			repo.AssertExpectations(GinkgoT())
+			logger.AssertExpectations(GinkgoT())
+			repo = nil
+			logger = nil
+			service = nil
+		})
+
+		When("targeting key is in the allow list", func() {
+			It("should return a target match", func() {
+				flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(true))
+				dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+				repo.On("GetAllTransitiveDependencies", flag).Return([]*flags.FeatureFlag{dependency}, nil)
+				repo.On("GetDependenciesByFlagIds", []uint{2, 1}).Return(
+					[]*flags.FlagDependency{{FlagID: 2, DependsOnFlagID: 1}},
+					nil,
+				)
+				repo.On("GetFlagTargetsByKey", []uint{2, 1}, "user-1").Return(
+					[]*flags.FlagTarget{{FlagID: 2, TargetingKey: "user-1", List: flags.TargetListAllow}},
+					nil,
+				)
+
+				result, err := service.EvaluateFeatureFlag(flag, &flags.EvaluateFeatureFlagRequest{
+					Context: evaluation.Context{TargetingKey: "user-1"},
+				})
+
+				Expect(err).To(BeNil())
+				Expect(result.ID).To(Equal(flag.ID))
+				Expect(result.Value).To(BeTrue())
+				Expect(result.Reason).To(Equal(evaluation.ReasonTargetMatch))
+			})
+		})
+
+		When("internal server error is happened", func() {
+			It("should return api error with status code 500", func() {
+				flag := mockFlags.CreateFeatureFlag()
+				err := gofakeit.ErrorDatabase()
+				repo.On("GetAllTransitiveDependencies", flag).Return(nil, err)
+
+				result, apiErr := service.EvaluateFeatureFlag(flag, &flags.EvaluateFeatureFlagRequest{})
+
+				Expect(result).To(BeNil())
+				Expect(apiErr).To(Equal(api.InternalServerError("Internal Server Error", err.Error())))
+			})
+		})
+	})
+
+	Describe("Evaluate All Feature Flags", func() {
+		It("should load the flag set once and key results by flag name", func() {
+			repo := &mockFlags.MockRepository{}
+			logger := &mockLogger.MockLogger{}
+			service := &flags.Service{Repo: repo, Logger: logger}
+
+			repo.On("GetAllFlags").Return([]*flags.FeatureFlag{
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"), mockFlags.WithIsActive(true)),
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithName("new-cart"), mockFlags.WithIsActive(true)),
+			}, nil)
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{{FlagID: 2, DependsOnFlagID: 1}}, nil)
+			repo.On("GetAllFlagTargetsByKey", "user-1").Return(
+				[]*flags.FlagTarget{{FlagID: 1, TargetingKey: "user-1", List: flags.TargetListDeny}},
+				nil,
+			)
+
+			result, err := service.EvaluateAllFeatureFlags(&flags.EvaluateAllFeatureFlagsRequest{
+				Context: evaluation.Context{TargetingKey: "user-1"},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Flags).To(HaveLen(2))
+			Expect(result.Flags["checkout"].Reason).To(Equal(evaluation.ReasonTargetMatch))
+			Expect(result.Flags["new-cart"].Reason).To(Equal(evaluation.ReasonPrerequisiteFailed))
+
+			repo.AssertExpectations(GinkgoT())
+			logger.AssertExpectations(GinkgoT())
+		})
+	})
+
+	Describe("Get Feature Flag Changes", func() {
+		var (
+			repo    *mockFlags.MockRepository
+			logger  *mockLogger.MockLogger
+			service *flags.Service
+		)
+
+		BeforeEach(func() {
+			repo = &mockFlags.MockRepository{}
+			logger = &mockLogger.MockLogger{}
+			service = &flags.Service{
+				Repo:   repo,
+				Logger: logger,
+			}
+		})
+
+		AfterEach(func() {
+			repo.AssertExpectations(GinkgoT())
+			logger.AssertExpectations(GinkgoT())
+		})
+
+		When("client is up to date", func() {
+			It("should return no changes", func() {
+				repo.On("GetLatestFlagEventRevision").Return(uint64(7), nil)
+
+				result, err := service.GetFeatureFlagChanges(7)
+
+				Expect(err).To(BeNil())
+				Expect(result.Revision).To(Equal(uint64(7)))
+				Expect(result.Flags).To(BeEmpty())
+				Expect(result.Deleted).To(BeEmpty())
+			})
+		})
+
+		When("flags changed after the revision", func() {
+			It("should return their configuration and tombstones for missing flags", func() {
+				repo.On("GetLatestFlagEventRevision").Return(uint64(9), nil)
+				repo.On("GetChangedFlagIds", uint64(4), uint64(9)).Return([]uint{1, 2}, nil)
+				repo.On("GetFlagByIds", []uint{1, 2}).Return(
+					mockFlags.CreateFeatureFlagByIds([]uint{1}, mockFlags.WithIsActive(true)),
+					nil,
+				)
+				repo.On("GetDependenciesByFlagIds", []uint{1}).Return([]*flags.FlagDependency{}, nil)
+				repo.On("GetFlagTargetsByFlagIds", []uint{1}).Return(
+					[]*flags.FlagTarget{{FlagID: 1, TargetingKey: "user-1", List: flags.TargetListAllow}},
+					nil,
+				)
+
+				result, err := service.GetFeatureFlagChanges(4)
+
+				Expect(err).To(BeNil())
+				Expect(result.Revision).To(Equal(uint64(9)))
+				Expect(result.Flags).To(HaveLen(1))
+				Expect(result.Flags[0].ID).To(Equal(uint(1)))
+				Expect(result.Flags[0].Allow).To(Equal([]string{"user-1"}))
+				Expect(result.Deleted).To(Equal([]uint{2}))
+			})
+		})
+	})
+
+	Describe("Get Feature Flags Snapshot", func() {
+		var (
+			repo    *mockFlags.MockRepository
+			logger  *mockLogger.MockLogger
+			service *flags.Service
+		)
+
+		BeforeEach(func() {
+			repo = &mockFlags.MockRepository{}
+			logger = &mockLogger.MockLogger{}
+			service = &flags.Service{
+				Repo:   repo,
+				Logger: logger,
+			}
+		})
+
+		AfterEach(func() {
+			repo.AssertExpectations(GinkgoT())
+			logger.AssertExpectations(GinkgoT())
+		})
+
+		When("signing key is configured", func() {
+			It("should return a snapshot that verifies with the public key", func() {
+				publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+				GinkgoT().Setenv("SNAPSHOT_SIGNING_KEY", base64.StdEncoding.EncodeToString(privateKey.Seed()))
+				repo.On("GetLatestFlagEventRevision").Return(uint64(12), nil)
+				repo.On("GetAllFlags").Return(mockFlags.CreateFeatureFlagByIds([]uint{1, 2}, mockFlags.WithIsActive(true)), nil)
+				repo.On("GetAllDependencies").Return([]*flags.FlagDependency{{FlagID: 2, DependsOnFlagID: 1}}, nil)
+				repo.On("GetAllFlagTargets").Return([]*flags.FlagTarget{}, nil)
+
+				file, err := service.GetFeatureFlagsSnapshot()
+				Expect(err).To(BeNil())
+
+				opened, verifyErr := snapshot.Verify(file, publicKey)
+				Expect(verifyErr).To(BeNil())
+				Expect(opened.Revision).To(Equal(uint64(12)))
+				Expect(opened.Config.Flags).To(HaveLen(2))
+				Expect(opened.Config.Flags[1].Dependencies).To(Equal([]uint{1}))
+
+				key, err := service.GetFeatureFlagsSnapshotPublicKey()
+				Expect(err).To(BeNil())
+				Expect(key.PublicKey).To(Equal(base64.StdEncoding.EncodeToString(publicKey)))
+			})
+		})
+
+		When("signing key is not configured", func() {
+			It("should return an error", func() {
+				GinkgoT().Setenv("SNAPSHOT_SIGNING_KEY", "")
+
+				file, err := service.GetFeatureFlagsSnapshot()
+
+				Expect(file).To(BeNil())
+				Expect(err).NotTo(BeNil())
+				Expect(err.StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("List Feature Flags", func() {
+		It("should return a page of flags with their dependencies and dependents", func() {
+			repo := &mockFlags.MockRepository{}
+			service := &flags.Service{Repo: repo, Logger: &mockLogger.MockLogger{}}
+			repo.On("GetFlags", 2, 2).Return(mockFlags.CreateFeatureFlagByIds([]uint{3, 4}), uint(5), nil)
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{
+				{FlagID: 3, DependsOnFlagID: 1},
+				{FlagID: 5, DependsOnFlagID: 3},
+			}, nil)
+
+			result, err := service.ListFeatureFlags(&flags.ListFeatureFlagsQueryParams{
+				PaginationQueryParam: api.PaginationQueryParam{Page: 2, Size: 2},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Total).To(Equal(uint(5)))
+			Expect(result.TotalPages).To(Equal(uint(3)))
+			Expect(result.Flags).To(HaveLen(2))
+			Expect(result.Flags[0].Dependencies).To(Equal([]uint{1}))
+			Expect(result.Flags[0].Dependents).To(Equal([]uint{5}))
+			Expect(result.Flags[1].Dependencies).To(BeEmpty())
+			repo.AssertExpectations(GinkgoT())
+		})
+	})
+
+	Describe("Validate Update Feature Flag Dependencies", func() {
+		var (
+			repo    *mockFlags.MockRepository
+			service *flags.Service
+			flag    *flags.FeatureFlag
+		)
+
+		BeforeEach(func() {
+			repo = &mockFlags.MockRepository{}
+			service = &flags.Service{Repo: repo, Logger: &mockLogger.MockLogger{}}
+			flag = mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			repo.On("GetFlagById", uint(1)).Return(flag, nil)
+		})
+
+		AfterEach(func() {
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should accept existing active dependencies", func() {
+			dependencies := mockFlags.CreateFeatureFlagByIds([]uint{2, 3}, mockFlags.WithIsActive(true))
+			repo.On("GetFlagByIds", []uint{2, 3}).Return(dependencies, nil)
+			repo.On("GetAllTransitiveDependencies", dependencies[0]).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetAllTransitiveDependencies", dependencies[1]).Return([]*flags.FeatureFlag{}, nil)
+
+			result, err := service.ValidateUpdateFeatureFlagDependencies(1, &flags.UpdateFeatureFlagDependenciesRequest{
+				Dependencies: []uint{2, 3, 2},
+				Reason:       "rewire",
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(flag))
+		})
+
+		It("should allow removing every dependency", func() {
+			req := &flags.UpdateFeatureFlagDependenciesRequest{Reason: "standalone"}
+
+			_, err := service.ValidateUpdateFeatureFlagDependencies(1, req)
+
+			Expect(err).To(BeNil())
+			Expect(req.Dependencies).To(Equal([]uint{}))
+		})
+
+		DescribeTable("should reject invalid dependencies",
+			func(setup func(), dependencies []uint, statusCode int, message string) {
+				setup()
+
+				_, err := service.ValidateUpdateFeatureFlagDependencies(1, &flags.UpdateFeatureFlagDependenciesRequest{
+					Dependencies: dependencies,
+					Reason:       "rewire",
+				})
+
+				Expect(err).NotTo(BeNil())
+				Expect(err.StatusCode).To(Equal(statusCode))
+				Expect(err.Message).To(ContainSubstring(message))
+			},
+			Entry("unknown flag", func() {
+				repo.On("GetFlagByIds", []uint{2}).Return([]*flags.FeatureFlag{}, nil)
+			}, []uint{2}, http.StatusNotFound, ""),
+			Entry("self dependency", func() {
+				repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{flag}, nil)
+			}, []uint{1}, http.StatusBadRequest, "cannot depend on itself"),
+			Entry("cycle", func() {
+				dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(true))
+				repo.On("GetFlagByIds", []uint{2}).Return([]*flags.FeatureFlag{dependency}, nil)
+				repo.On("GetAllTransitiveDependencies", dependency).Return(
+					mockFlags.CreateFeatureFlagByIds([]uint{3, 1}),
+					nil,
+				)
+			}, []uint{2}, http.StatusBadRequest, "Circular dependency"),
+			Entry("inactive dependency of an active flag", func() {
+				dependency := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(false))
+				repo.On("GetFlagByIds", []uint{2}).Return([]*flags.FeatureFlag{dependency}, nil)
+				repo.On("GetAllTransitiveDependencies", dependency).Return([]*flags.FeatureFlag{}, nil)
+			}, []uint{2}, http.StatusBadRequest, "Inactive dependency IDs: [2]"),
+		)
+	})
 })
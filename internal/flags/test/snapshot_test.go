@@ -0,0 +1,227 @@
+package flags_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	mockFlags "github.com/ArshiAbolghasemi/dom-cobb/internal/flags/test/mock"
+	mockLogger "github.com/ArshiAbolghasemi/dom-cobb/internal/logger/test/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CachedRepository", func() {
+	var (
+		repo   *mockFlags.MockRepository
+		cached *flags.CachedRepository
+	)
+
+	BeforeEach(func() {
+		repo = &mockFlags.MockRepository{}
+		repo.On("GetLatestFlagEventRevision").Return(uint64(5), nil).Once()
+		repo.On("GetAllFlags").Return([]*flags.FeatureFlag{
+			mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"), mockFlags.WithIsActive(true)),
+			mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithName("payments"), mockFlags.WithIsActive(true)),
+			mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithName("wallet"), mockFlags.WithIsActive(false)),
+		}, nil).Once()
+		repo.On("GetAllDependencies").Return([]*flags.FlagDependency{
+			{FlagID: 1, DependsOnFlagID: 2},
+			{FlagID: 2, DependsOnFlagID: 3},
+		}, nil).Once()
+		repo.On("GetAllFlagTargets").Return([]*flags.FlagTarget{
+			{FlagID: 1, TargetingKey: "user-1", List: flags.TargetListAllow},
+			{FlagID: 2, TargetingKey: "user-1", List: flags.TargetListDeny},
+		}, nil).Once()
+
+		var err error
+		cached, err = flags.NewCachedRepository(repo, time.Hour)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		repo.AssertExpectations(GinkgoT())
+	})
+
+	Describe("Reads", func() {
+		It("should serve flags and the dependency graph from the snapshot", func() {
+			flag, err := cached.GetFlagById(1)
+			Expect(err).To(BeNil())
+			Expect(flag.Name).To(Equal("checkout"))
+
+			dependencies, err := cached.GetFlagDependencies(flag)
+			Expect(err).To(BeNil())
+			Expect(dependencies).To(HaveLen(1))
+			Expect(dependencies[0].ID).To(Equal(uint(2)))
+
+			dependents, err := cached.GetFlagDependents(dependencies[0])
+			Expect(err).To(BeNil())
+			Expect(dependents).To(HaveLen(1))
+			Expect(dependents[0].ID).To(Equal(uint(1)))
+
+			transitive, err := cached.GetAllTransitiveDependencies(flag)
+			Expect(err).To(BeNil())
+			Expect(transitive).To(HaveLen(2))
+			Expect(transitive[0].ID).To(Equal(uint(2)))
+			Expect(transitive[1].ID).To(Equal(uint(3)))
+
+			targets, err := cached.GetFlagTargetsByKey([]uint{1}, "user-1")
+			Expect(err).To(BeNil())
+			Expect(targets).To(HaveLen(1))
+			Expect(targets[0].List).To(Equal(flags.TargetListAllow))
+		})
+
+		It("should report missing flags like the database repository", func() {
+			flag, err := cached.GetFlagByName("unknown")
+			Expect(err).To(BeNil())
+			Expect(flag).To(BeNil())
+
+			_, err = cached.GetFlagById(42)
+			Expect(err).To(MatchError("flag with id 42 not found"))
+		})
+
+		It("should report the snapshot revision", func() {
+			revision, err := cached.GetLatestFlagEventRevision()
+			Expect(err).To(BeNil())
+			Expect(revision).To(Equal(uint64(5)))
+		})
+
+		It("should not let callers modify the snapshot", func() {
+			flag, _ := cached.GetFlagById(1)
+			flag.IsActive = false
+
+			flag, _ = cached.GetFlagById(1)
+			Expect(flag.IsActive).To(BeTrue())
+		})
+	})
+
+	Describe("Sync", func() {
+		It("should only mark the snapshot verified when the revision is unchanged", func() {
+			repo.On("GetLatestFlagEventRevision").Return(uint64(5), nil).Once()
+
+			Expect(cached.Sync()).To(Succeed())
+			Expect(cached.Snapshot().Revision).To(Equal(uint64(5)))
+		})
+
+		It("should reload the snapshot when the revision moved", func() {
+			repo.On("GetLatestFlagEventRevision").Return(uint64(6), nil).Twice()
+			repo.On("GetAllFlags").Return([]*flags.FeatureFlag{mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"), mockFlags.WithIsActive(false))}, nil).Once()
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{}, nil).Once()
+			repo.On("GetAllFlagTargets").Return([]*flags.FlagTarget{}, nil).Once()
+
+			Expect(cached.Sync()).To(Succeed())
+
+			Expect(cached.Snapshot().Revision).To(Equal(uint64(6)))
+			flag, err := cached.GetFlagById(1)
+			Expect(err).To(BeNil())
+			Expect(flag.IsActive).To(BeFalse())
+		})
+
+		It("should keep serving the last snapshot when postgres is unavailable", func() {
+			repo.On("GetLatestFlagEventRevision").Return(uint64(0), errors.New("connection refused")).Once()
+
+			Expect(cached.Sync()).NotTo(Succeed())
+			Expect(cached.SyncFailures()).To(Equal(uint64(1)))
+
+			flag, err := cached.GetFlagById(2)
+			Expect(err).To(BeNil())
+			Expect(flag.Name).To(Equal("payments"))
+			Expect(cached.Snapshot().Revision).To(Equal(uint64(5)))
+		})
+	})
+
+	Describe("Writes", func() {
+		It("should validate against postgres rather than the snapshot", func() {
+			service := &flags.Service{Repo: cached, Logger: &mockLogger.MockLogger{}}
+			wallet := mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithIsActive(false))
+			payments := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(false))
+			repo.On("GetFlagById", uint(2)).Return(payments, nil)
+			repo.On("GetFlagDependencies", payments).Return([]*flags.FeatureFlag{wallet}, nil)
+
+			_, err := service.ValidateUpdateFeatureFlag(2, &flags.UpdateFeatureFlagRequest{IsActive: true, Reason: "enable"})
+
+			Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(err.Message).To(ContainSubstring("[3]"))
+		})
+
+		It("should count a failed sync after a committed write", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(3))
+			repo.On("UpdateFlag", flag, true, "enable").Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetLatestFlagEventRevision").Return(uint64(0), errors.New("connection refused")).Once()
+
+			_, err := cached.UpdateFlag(context.Background(), flag, true, "enable")
+
+			Expect(err).To(BeNil())
+			Expect(cached.SyncFailures()).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("Changes", func() {
+		It("should read changes from postgres when the snapshot lags the notified revision", func() {
+			service := &flags.Service{Repo: cached, Logger: &mockLogger.MockLogger{}}
+			repo.On("GetLatestFlagEventRevision").Return(uint64(6), nil).Once()
+			repo.On("GetChangedFlagIds", uint64(5), uint64(6)).Return([]uint{3}, nil).Once()
+			repo.On("GetFlagByIds", []uint{3}).Return([]*flags.FeatureFlag{
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithName("wallet"), mockFlags.WithIsActive(true)),
+			}, nil).Once()
+			repo.On("GetDependenciesByFlagIds", []uint{3}).Return([]*flags.FlagDependency{}, nil).Once()
+			repo.On("GetFlagTargetsByFlagIds", []uint{3}).Return([]*flags.FlagTarget{}, nil).Once()
+
+			data, err := service.GetFeatureFlagChangesUntil(5, 6)
+
+			Expect(err).To(BeNil())
+			Expect(data.Revision).To(Equal(uint64(6)))
+			Expect(data.Flags).To(HaveLen(1))
+			Expect(data.Flags[0].Active).To(BeTrue())
+		})
+
+		It("should serve changes the snapshot already has from it", func() {
+			service := &flags.Service{Repo: cached, Logger: &mockLogger.MockLogger{}}
+
+			data, err := service.GetFeatureFlagChangesUntil(5, 5)
+
+			Expect(err).To(BeNil())
+			Expect(data.Revision).To(Equal(uint64(5)))
+			Expect(data.Flags).To(BeEmpty())
+		})
+	})
+
+	Describe("Run", func() {
+		It("should reload the snapshot when a newer revision is notified", func() {
+			repo.On("GetLatestFlagEventRevision").Return(uint64(7), nil).Twice()
+			repo.On("GetAllFlags").Return([]*flags.FeatureFlag{}, nil).Once()
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{}, nil).Once()
+			repo.On("GetAllFlagTargets").Return([]*flags.FlagTarget{}, nil).Once()
+
+			stop := make(chan struct{})
+			defer close(stop)
+			notifications := make(chan postgres.Notification, 1)
+			go cached.Run(stop, notifications)
+
+			notifications <- postgres.Notification{Payload: "7"}
+
+			Eventually(func() uint64 { return cached.Snapshot().Revision }).Should(Equal(uint64(7)))
+		})
+	})
+
+	Describe("Writes", func() {
+		It("should sync the snapshot after a successful write", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithName("wallet"), mockFlags.WithIsActive(false))
+			repo.On("UpdateFlag", flag, true, "launch").Return(nil, nil).Once()
+			repo.On("GetLatestFlagEventRevision").Return(uint64(6), nil).Twice()
+			repo.On("GetAllFlags").Return([]*flags.FeatureFlag{mockFlags.CreateFeatureFlag(mockFlags.WithId(3), mockFlags.WithName("wallet"), mockFlags.WithIsActive(true))}, nil).Once()
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{}, nil).Once()
+			repo.On("GetAllFlagTargets").Return([]*flags.FlagTarget{}, nil).Once()
+
+			_, err := cached.UpdateFlag(context.Background(), flag, true, "launch")
+			Expect(err).NotTo(HaveOccurred())
+
+			flag, err = cached.GetFlagById(3)
+			Expect(err).To(BeNil())
+			Expect(flag.IsActive).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,227 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	FlagTimestampIndexName   = "metadata.flag_id_1_timestamp_-1__id_-1"
+	EntityTimestampIndexName = "entity_type_1_entity_id_1_timestamp_-1__id_-1"
+	TimestampIndexName       = "timestamp_-1__id_-1"
+	SequenceIndexName        = "sequence_1"
+
+	errorCodeNamespaceNotFound     = 26
+	errorCodeIndexNotFound         = 27
+	errorCodeDuplicateKey          = 11000
+	errorCodeIndexOptionsConflict  = 85
+	errorCodeIndexKeySpecsConflict = 86
+)
+
+// legacyIndexNames are the indexes of earlier versions that the ones in
+// GetIndexModels replace.
+var legacyIndexNames = []string{
+	"metadata.flag_id_1_timestamp_-1",
+	"entity_type_1_entity_id_1_timestamp_-1",
+	"timestamp_-1",
+}
+
+// GetValidator returns the $jsonSchema validator of the log collection.
+func GetValidator() bson.M {
+	return bson.M{
+		"$jsonSchema": bson.M{
+			"bsonType": "object",
+			"required": bson.A{"message", "timestamp"},
+			"properties": bson.M{
+				"sequence":       bson.M{"bsonType": "long"},
+				"prev_hash":      bson.M{"bsonType": "string"},
+				"hash":           bson.M{"bsonType": "string"},
+				"schema_version": bson.M{"bsonType": bson.A{"int", "long"}},
+				"action":         bson.M{"enum": actions()},
+				"entity_type":    bson.M{"enum": bson.A{EntityTypeFlag}},
+				"entity_id":      bson.M{"bsonType": bson.A{"int", "long"}},
+				"actor":          bson.M{"bsonType": "string"},
+				"reason":         bson.M{"bsonType": "string"},
+				"request_id":     bson.M{"bsonType": "string"},
+				"before":         bson.M{"bsonType": "object"},
+				"after":          bson.M{"bsonType": "object"},
+				"message":        bson.M{"bsonType": "string"},
+				"timestamp":      bson.M{"bsonType": "date"},
+				"metadata":       bson.M{"bsonType": "object"},
+			},
+		},
+	}
+}
+
+// GetIndexModels returns the indexes behind the log queries. Flag logs
+// filter on the entity, or on metadata.flag_id for legacy entries, and
+// time range scans on the timestamp alone. Pages sort by timestamp and
+// then _id, so each of these indexes ends in both. The unique sequence
+// index keeps concurrent writers from chaining two entries after the same
+// one.
+func GetIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "metadata.flag_id", Value: 1},
+				{Key: "timestamp", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName(FlagTimestampIndexName),
+		},
+		{
+			Keys: bson.D{
+				{Key: "entity_type", Value: 1},
+				{Key: "entity_id", Value: 1},
+				{Key: "timestamp", Value: -1},
+				{Key: "_id", Value: -1},
+			},
+			Options: options.Index().SetName(EntityTimestampIndexName),
+		},
+		{
+			Keys:    bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName(TimestampIndexName),
+		},
+		{
+			Keys: bson.D{{Key: "sequence", Value: 1}},
+			Options: options.Index().
+				SetName(SequenceIndexName).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"sequence": bson.M{"$exists": true}}),
+		},
+	}
+}
+
+// Bootstrap installs the schema validator and indexes of the log
+// collection, creating it when missing. It is idempotent; an index whose
+// options changed is dropped and recreated, and the indexes of earlier
+// versions, including their TTL index, are dropped.
+func Bootstrap(ctx context.Context, db *mongo.Database, collection string, retention time.Duration, mode string) error {
+	if err := ensureValidator(ctx, db, collection); err != nil {
+		return err
+	}
+	if err := ensureIndexes(ctx, db.Collection(collection), GetIndexModels()); err != nil {
+		return err
+	}
+	if retention > 0 {
+		if err := ensureChainAnchor(ctx, db.Collection(collection)); err != nil {
+			return err
+		}
+	}
+	if mode != RetentionModeArchive {
+		return nil
+	}
+
+	archiveCollection, err := GetArchiveCollection()
+	if err != nil {
+		return err
+	}
+	if err := ensureValidator(ctx, db, archiveCollection); err != nil {
+		return err
+	}
+	return ensureIndexes(ctx, db.Collection(archiveCollection), GetIndexModels())
+}
+
+// EnsureCollection bootstraps the configured log collection.
+func EnsureCollection(ctx context.Context) error {
+	collection, err := GetCollection()
+	if err != nil {
+		return err
+	}
+	retention, err := GetRetention()
+	if err != nil {
+		return err
+	}
+	mode, err := GetRetentionMode()
+	if err != nil {
+		return err
+	}
+	return Bootstrap(ctx, mongodb.GetDB(), collection, retention, mode)
+}
+
+// ensureChainAnchor anchors the chain at its oldest entry when entries
+// before it were removed, by timestamp, before retention recorded anchors.
+func ensureChainAnchor(ctx context.Context, collection *mongo.Collection) error {
+	anchor, err := GetChainAnchor(ctx, collection)
+	if err != nil || anchor != nil {
+		return err
+	}
+
+	findOptions := options.FindOne().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetProjection(bson.M{"sequence": 1, "prev_hash": 1})
+
+	var oldest LogEntry
+	err = collection.FindOne(ctx, bson.M{"sequence": bson.M{"$exists": true}}, findOptions).Decode(&oldest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if oldest.Sequence <= 1 {
+		return nil
+	}
+	return setChainAnchor(ctx, collection, &LogEntry{Sequence: oldest.Sequence - 1, Hash: oldest.PrevHash})
+}
+
+func ensureValidator(ctx context.Context, db *mongo.Database, collection string) error {
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection},
+		{Key: "validator", Value: GetValidator()},
+		{Key: "validationLevel", Value: "moderate"},
+	}).Err()
+	if !hasErrorCode(err, errorCodeNamespaceNotFound) {
+		return err
+	}
+
+	return db.CreateCollection(ctx, collection, options.CreateCollection().
+		SetValidator(GetValidator()).
+		SetValidationLevel("moderate"))
+}
+
+func ensureIndexes(ctx context.Context, collection *mongo.Collection, models []mongo.IndexModel) error {
+	for _, name := range legacyIndexNames {
+		_, err := collection.Indexes().DropOne(ctx, name)
+		if err != nil && !hasErrorCode(err, errorCodeIndexNotFound, errorCodeNamespaceNotFound) {
+			return err
+		}
+	}
+
+	for _, model := range models {
+		_, err := collection.Indexes().CreateOne(ctx, model)
+		if !hasErrorCode(err, errorCodeIndexOptionsConflict, errorCodeIndexKeySpecsConflict) {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := collection.Indexes().DropOne(ctx, *model.Options.Name); err != nil {
+			return err
+		}
+		if _, err := collection.Indexes().CreateOne(ctx, model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasErrorCode(err error, codes ...int) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	for _, code := range codes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// chainContent is the part of an entry its hash covers. Fields are listed
+// explicitly so that adding a field to LogEntry does not silently change
+// the hash of existing entries.
+type chainContent struct {
+	Sequence      uint64         `json:"sequence"`
+	PrevHash      string         `json:"prev_hash"`
+	SchemaVersion int            `json:"schema_version"`
+	Action        Action         `json:"action"`
+	EntityType    EntityType     `json:"entity_type"`
+	EntityID      uint           `json:"entity_id"`
+	Actor         string         `json:"actor"`
+	Reason        string         `json:"reason"`
+	RequestID     string         `json:"request_id"`
+	CausedBy      uint           `json:"caused_by"`
+	Before        *FlagState     `json:"before"`
+	After         *FlagState     `json:"after"`
+	Message       string         `json:"message"`
+	Timestamp     string         `json:"timestamp"`
+	Metadata      map[string]any `json:"metadata"`
+}
+
+// ComputeHash returns the hex SHA-256 of the content of entry, including
+// its sequence and the hash of the entry before it.
+func ComputeHash(entry *LogEntry) (string, error) {
+	content, err := json.Marshal(chainContent{
+		Sequence:      entry.Sequence,
+		PrevHash:      entry.PrevHash,
+		SchemaVersion: entry.SchemaVersion,
+		Action:        entry.Action,
+		EntityType:    entry.EntityType,
+		EntityID:      entry.EntityID,
+		Actor:         entry.Actor,
+		Reason:        entry.Reason,
+		RequestID:     entry.RequestID,
+		CausedBy:      entry.CausedBy,
+		Before:        entry.Before,
+		After:         entry.After,
+		Message:       entry.Message,
+		Timestamp:     entry.Timestamp.UTC().Format(time.RFC3339Nano),
+		Metadata:      entry.Metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Chain links entries after the entry with sequence and hash, numbering
+// them consecutively. Timestamps are cut to the millisecond precision Mongo
+// stores, so the hash can be recomputed from the stored entry.
+func Chain(entries []*LogEntry, sequence uint64, hash string) error {
+	for _, entry := range entries {
+		sequence++
+		entry.Sequence = sequence
+		entry.PrevHash = hash
+		entry.Timestamp = entry.Timestamp.UTC().Truncate(time.Millisecond)
+
+		var err error
+		entry.Hash, err = ComputeHash(entry)
+		if err != nil {
+			return err
+		}
+		hash = entry.Hash
+	}
+	return nil
+}
+
+// chainHead returns the sequence and hash of the newest chained entry, or
+// zero values when the chain is empty.
+func chainHead(ctx context.Context, collection *mongo.Collection) (uint64, string, error) {
+	findOptions := options.FindOne().
+		SetSort(bson.D{{Key: "sequence", Value: -1}}).
+		SetProjection(bson.M{"sequence": 1, "hash": 1})
+
+	var head LogEntry
+	err := collection.FindOne(ctx, bson.M{"sequence": bson.M{"$exists": true}}, findOptions).Decode(&head)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	return head.Sequence, head.Hash, nil
+}
+
+// appendToChain inserts entries at the head of the chain. Writers in other
+// processes race for the same sequences; the unique sequence index lets
+// exactly one of them win each, and the losers relink what they have not
+// inserted yet after the new head and try again.
+func appendToChain(ctx context.Context, collection *mongo.Collection, entries []*LogEntry) error {
+	for len(entries) > 0 {
+		sequence, hash, err := chainHead(ctx, collection)
+		if err != nil {
+			return err
+		}
+		if err := Chain(entries, sequence, hash); err != nil {
+			return err
+		}
+
+		_, err = collection.InsertMany(ctx, utils.ToAnySlice(entries), options.InsertMany().SetOrdered(true))
+		if err == nil {
+			return nil
+		}
+
+		inserted, conflict := insertedBeforeConflict(err)
+		if !conflict {
+			return err
+		}
+		entries = entries[inserted:]
+	}
+	return nil
+}
+
+// insertedBeforeConflict reports whether an ordered insert stopped on a
+// duplicate sequence, and how many documents went in before it.
+func insertedBeforeConflict(err error) (int, bool) {
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || len(bulkErr.WriteErrors) == 0 {
+		return 0, false
+	}
+	writeErr := bulkErr.WriteErrors[0]
+	if writeErr.Code != errorCodeDuplicateKey {
+		return 0, false
+	}
+	return writeErr.Index, true
+}
+
+// ChainBreak is the first place where the stored chain does not verify.
+type ChainBreak struct {
+	Sequence uint64 `json:"sequence"`
+	Reason   string `json:"reason"`
+}
+
+// ChainAnchor is the last entry retention removed from the front of the
+// chain, kept in the chain collection next to the log collection. The
+// oldest retained entry must follow it, which tells entries removed by
+// retention apart from entries deleted otherwise.
+type ChainAnchor struct {
+	Collection string    `bson:"_id" json:"-"`
+	Sequence   uint64    `bson:"sequence" json:"sequence"`
+	Hash       string    `bson:"hash" json:"hash"`
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// chainCollection returns the collection holding the anchor of the chain
+// in collection.
+func chainCollection(collection *mongo.Collection) *mongo.Collection {
+	return collection.Database().Collection(collection.Name() + "_chain")
+}
+
+// GetChainAnchor returns the anchor of the chain in collection, or nil
+// when retention has not removed any chained entry.
+func GetChainAnchor(ctx context.Context, collection *mongo.Collection) (*ChainAnchor, error) {
+	var anchor ChainAnchor
+	err := chainCollection(collection).FindOne(ctx, bson.M{"_id": collection.Name()}).Decode(&anchor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &anchor, nil
+}
+
+// setChainAnchor records entry as the last entry removed from the front of
+// the chain in collection.
+func setChainAnchor(ctx context.Context, collection *mongo.Collection, entry *LogEntry) error {
+	_, err := chainCollection(collection).UpdateOne(ctx,
+		bson.M{"_id": collection.Name()},
+		bson.M{"$set": bson.M{"sequence": entry.Sequence, "hash": entry.Hash, "updated_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// ChainVerification is the outcome of walking the chain. The chain starts
+// after the anchor, so FirstSequence is above 1 once retention removed
+// older entries. LastHash can be recorded elsewhere to later prove that no
+// entry up to LastSequence was removed from the end.
+type ChainVerification struct {
+	Valid         bool         `json:"valid"`
+	Entries       uint64       `json:"entries"`
+	Anchor        *ChainAnchor `json:"anchor,omitempty"`
+	FirstSequence uint64       `json:"first_sequence,omitempty"`
+	LastSequence  uint64       `json:"last_sequence,omitempty"`
+	LastHash      string       `json:"last_hash,omitempty"`
+	Break         *ChainBreak  `json:"break,omitempty"`
+}
+
+// ChainVerifier checks entries handed to it in sequence order.
+type ChainVerifier struct {
+	result       ChainVerification
+	prevSequence uint64
+	prevHash     string
+}
+
+// NewChainVerifier returns a verifier of a chain whose first entries were
+// removed by retention up to anchor, or of a whole chain for a nil anchor.
+func NewChainVerifier(anchor *ChainAnchor) *ChainVerifier {
+	verifier := &ChainVerifier{result: ChainVerification{Valid: true, Anchor: anchor}}
+	if anchor != nil {
+		verifier.prevSequence = anchor.Sequence
+		verifier.prevHash = anchor.Hash
+	}
+	return verifier
+}
+
+// Add checks the next entry and returns false once the chain is broken.
+func (v *ChainVerifier) Add(entry *LogEntry) bool {
+	if v.result.Break != nil {
+		return false
+	}
+
+	switch {
+	case entry.Sequence != v.prevSequence+1:
+		v.breakAt(v.prevSequence+1, fmt.Sprintf("entry is missing, next entry is %d", entry.Sequence))
+	case entry.PrevHash != v.prevHash:
+		v.breakAt(entry.Sequence, "previous hash does not match the previous entry")
+	default:
+		hash, err := ComputeHash(entry)
+		if err != nil {
+			v.breakAt(entry.Sequence, "entry cannot be hashed: "+err.Error())
+		} else if hash != entry.Hash {
+			v.breakAt(entry.Sequence, "content does not match its hash")
+		}
+	}
+	if v.result.Break != nil {
+		return false
+	}
+
+	if v.result.Entries == 0 {
+		v.result.FirstSequence = entry.Sequence
+	}
+	v.result.Entries++
+	v.result.LastSequence = entry.Sequence
+	v.result.LastHash = entry.Hash
+	v.prevSequence = entry.Sequence
+	v.prevHash = entry.Hash
+	return true
+}
+
+func (v *ChainVerifier) breakAt(sequence uint64, reason string) {
+	v.result.Valid = false
+	v.result.Break = &ChainBreak{Sequence: sequence, Reason: reason}
+}
+
+func (v *ChainVerifier) Result() *ChainVerification {
+	result := v.result
+	return &result
+}
+
+// VerifyChain walks the chained entries of collection after its anchor in
+// sequence order and stops at the first break.
+func VerifyChain(ctx context.Context, collection *mongo.Collection) (*ChainVerification, error) {
+	anchor, err := GetChainAnchor(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	var after uint64
+	if anchor != nil {
+		after = anchor.Sequence
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetBatchSize(logCursorBatchSize)
+
+	cursor, err := collection.Find(ctx, bson.M{"sequence": bson.M{"$gt": after}}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	verifier := NewChainVerifier(anchor)
+	for cursor.Next(ctx) {
+		var entry LogEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, err
+		}
+		if !verifier.Add(&entry) {
+			break
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return verifier.Result(), nil
+}
@@ -26,3 +26,51 @@ func GetWriteTimeOut() (time.Duration, error) {
 	}
 	return time.Duration(timeout), nil
 }
+
+const (
+	RetentionModeTTL     = "ttl"
+	RetentionModeArchive = "archive"
+)
+
+// GetRetention returns how long log entries stay in the log collection.
+// Zero, the default, keeps them forever.
+func GetRetention() (time.Duration, error) {
+	daysStr, exists := os.LookupEnv("MONGO_LOG_RETENTION_DAYS")
+	if !exists {
+		return 0, nil
+	}
+	days, err := strconv.Atoi(daysStr)
+	if err != nil {
+		return -1, err
+	}
+	if days < 0 {
+		return -1, fmt.Errorf("Log retention days must not be negative")
+	}
+	return time.Duration(days) * 24 * time.Hour, nil
+}
+
+// GetRetentionMode returns what the retainer does with expired entries:
+// ttl deletes them and archive moves them to the archive collection.
+// Either way it only removes a verified prefix of the chain.
+func GetRetentionMode() (string, error) {
+	mode, exists := os.LookupEnv("MONGO_LOG_RETENTION_MODE")
+	if !exists {
+		return RetentionModeTTL, nil
+	}
+	if mode != RetentionModeTTL && mode != RetentionModeArchive {
+		return "", fmt.Errorf("Log retention mode must be %s or %s", RetentionModeTTL, RetentionModeArchive)
+	}
+	return mode, nil
+}
+
+func GetArchiveCollection() (string, error) {
+	collection, exists := os.LookupEnv("MONGO_LOG_ARCHIVE_COLLECTION")
+	if exists {
+		return collection, nil
+	}
+	collection, err := GetCollection()
+	if err != nil {
+		return "", err
+	}
+	return collection + "_archive", nil
+}
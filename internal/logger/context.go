@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	// SystemActor is recorded for changes made outside of a request, such
+	// as by background jobs.
+	SystemActor = "system"
+	// AnonymousActor is recorded for requests without an authenticated
+	// principal or trusted actor header.
+	AnonymousActor = "anonymous"
+	// MaxActorLength matches the length the actor filter accepts.
+	MaxActorLength = 255
+)
+
+type requestIDKey struct{}
+
+type actorKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the id of the request that
+// caused the changes logged under it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithActor returns a copy of ctx carrying who makes the changes logged
+// under it.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// GetActor returns the actor of ctx, SystemActor when there is none.
+func GetActor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	if actor == "" {
+		return SystemActor
+	}
+	return actor
+}
+
+func NewRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
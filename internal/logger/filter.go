@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const logCursorBatchSize = 1000
+
+type Action string
+
+const (
+	ActionCreated             Action = "created"
+	ActionToggled             Action = "toggled"
+	ActionDependenciesUpdated Action = "dependencies_updated"
+	ActionTargetsAdded        Action = "targets_added"
+	ActionTargetsRemoved      Action = "targets_removed"
+	ActionAutoDisabled        Action = "auto_disabled"
+	ActionRolledBack          Action = "rolled_back"
+	ActionUndone              Action = "undone"
+)
+
+func actions() bson.A {
+	return bson.A{
+		ActionCreated,
+		ActionToggled,
+		ActionDependenciesUpdated,
+		ActionTargetsAdded,
+		ActionTargetsRemoved,
+		ActionAutoDisabled,
+		ActionRolledBack,
+		ActionUndone,
+	}
+}
+
+// legacyActionMessages maps actions to the fixed messages of entries
+// written before actions were recorded, so filters still match them.
+var legacyActionMessages = map[Action]string{
+	ActionCreated:             "Feature Flag is created successfully",
+	ActionToggled:             "Feature Flag is toggled successfully",
+	ActionDependenciesUpdated: "Feature Flag dependencies are updated successfully",
+	ActionTargetsAdded:        "Feature Flag targets are added successfully",
+	ActionTargetsRemoved:      "Feature Flag targets are removed successfully",
+	ActionAutoDisabled:        "Flag is auto disabled",
+}
+
+// Filter narrows down log entries. Zero fields match everything.
+type Filter struct {
+	From    time.Time
+	To      time.Time
+	FlagIds []uint
+	Action  Action
+	Actor   string
+	// Reason matches entries whose reason contains it, case-insensitively.
+	Reason string
+	// Cascade selects only cascaded entries when true and excludes them
+	// when false.
+	Cascade   *bool
+	Ascending bool
+}
+
+func (f *Filter) BSON() bson.M {
+	conditions := bson.A{}
+
+	timestamp := bson.M{}
+	if !f.From.IsZero() {
+		timestamp["$gte"] = f.From
+	}
+	if !f.To.IsZero() {
+		timestamp["$lt"] = f.To
+	}
+	if len(timestamp) > 0 {
+		conditions = append(conditions, bson.M{"timestamp": timestamp})
+	}
+
+	if len(f.FlagIds) > 0 {
+		conditions = append(conditions, orLegacy(
+			bson.M{"entity_type": EntityTypeFlag, "entity_id": bson.M{"$in": f.FlagIds}},
+			bson.M{"metadata.flag_id": bson.M{"$in": f.FlagIds}},
+		))
+	}
+	if f.Action != "" {
+		conditions = append(conditions, actionCondition(f.Action))
+	}
+	if f.Actor != "" {
+		conditions = append(conditions, bson.M{"actor": f.Actor})
+	}
+	if f.Reason != "" {
+		reason := bson.M{"$regex": regexp.QuoteMeta(f.Reason), "$options": "i"}
+		conditions = append(conditions, orLegacy(
+			bson.M{"reason": reason},
+			bson.M{"metadata.reason": reason},
+		))
+	}
+	if f.Cascade != nil {
+		cascade := orLegacy(
+			bson.M{"caused_by": bson.M{"$exists": true}},
+			bson.M{"$or": bson.A{
+				bson.M{"metadata.cascade": true},
+				bson.M{"message": legacyActionMessages[ActionAutoDisabled]},
+			}},
+		)
+		if *f.Cascade {
+			conditions = append(conditions, cascade)
+		} else {
+			conditions = append(conditions, bson.M{"$nor": bson.A{cascade}})
+		}
+	}
+
+	if len(conditions) == 0 {
+		return bson.M{}
+	}
+	return bson.M{"$and": conditions}
+}
+
+// Sort orders entries by timestamp, newest first unless Ascending, with
+// _id breaking ties so the order is stable.
+func (f *Filter) Sort() bson.D {
+	order := -1
+	if f.Ascending {
+		order = 1
+	}
+	return bson.D{{Key: "timestamp", Value: order}, {Key: "_id", Value: order}}
+}
+
+func actionCondition(action Action) bson.M {
+	legacy := bson.A{bson.M{"metadata.action": action}}
+	if legacyMessage, exists := legacyActionMessages[action]; exists {
+		legacy = append(legacy, bson.M{"metadata.action": bson.M{"$exists": false}, "message": legacyMessage})
+	}
+	return orLegacy(bson.M{"action": action}, bson.M{"$or": legacy})
+}
+
+// orLegacy matches condition on typed entries, or legacy on entries
+// written before SchemaVersion.
+func orLegacy(condition, legacy bson.M) bson.M {
+	return bson.M{"$or": bson.A{
+		condition,
+		bson.M{"$and": bson.A{bson.M{"schema_version": bson.M{"$exists": false}}, legacy}},
+	}}
+}
+
+// FindLogs returns a page of the entries in collection matching filter,
+// newest first unless the filter asks for ascending order.
+func FindLogs(ctx context.Context, collection *mongo.Collection, filter *Filter, pager *mongodb.Pager) ([]*LogEntry, error) {
+	query := filter.BSON()
+
+	total, err := collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	pager.SetTotal(uint(total))
+
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(pager.GetLimit()))
+	findOptions.SetSkip(int64(pager.GetOffset()))
+	findOptions.SetSort(filter.Sort())
+
+	cursor, err := collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	logs := make([]*LogEntry, 0)
+	if err = cursor.All(ctx, &logs); err != nil {
+		return nil, err
+	}
+	for _, entry := range logs {
+		entry.UpgradeLegacy()
+	}
+
+	return logs, nil
+}
+
+// LogCursor iterates over the entries matching a filter one at a time, so
+// large results are never held in memory at once.
+type LogCursor struct {
+	cursor *mongo.Cursor
+	entry  *LogEntry
+	err    error
+}
+
+// OpenLogCursor starts iterating over the entries in collection matching
+// filter, in the order of filter.Sort.
+func OpenLogCursor(ctx context.Context, collection *mongo.Collection, filter *Filter) (*LogCursor, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(filter.Sort())
+	findOptions.SetBatchSize(logCursorBatchSize)
+
+	cursor, err := collection.Find(ctx, filter.BSON(), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	return &LogCursor{cursor: cursor}, nil
+}
+
+// Next advances to the next entry, returning false when the cursor is
+// exhausted or failed; Err tells the two apart.
+func (c *LogCursor) Next(ctx context.Context) bool {
+	if c.err != nil || !c.cursor.Next(ctx) {
+		return false
+	}
+
+	var entry LogEntry
+	if err := c.cursor.Decode(&entry); err != nil {
+		c.err = err
+		return false
+	}
+	entry.UpgradeLegacy()
+	c.entry = &entry
+	return true
+}
+
+func (c *LogCursor) Entry() *LogEntry {
+	return c.entry
+}
+
+func (c *LogCursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.cursor.Err()
+}
+
+func (c *LogCursor) Close(ctx context.Context) error {
+	return c.cursor.Close(ctx)
+}
@@ -1,9 +1,98 @@
 package logger
 
-import "time"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SchemaVersion is the version of the audit event schema written by this
+// release. Entries without one predate typed events and are upgraded on
+// read, see LogEntry.UpgradeLegacy.
+const SchemaVersion = 1
+
+type EntityType string
+
+const (
+	EntityTypeFlag EntityType = "flag"
+)
+
+// FlagState is the state of a flag recorded before and after a change.
+type FlagState struct {
+	Name         string `bson:"name" json:"name"`
+	IsActive     bool   `bson:"is_active" json:"is_active"`
+	Dependencies []uint `bson:"dependencies" json:"dependencies"`
+}
 
 type LogEntry struct {
-	Message   string         `bson:"message" json:"message"`
-	Timestamp time.Time      `bson:"timestamp" json:"timestamp"`
-	Metadata  map[string]any `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	// ID is only read to page by cursor, Mongo assigns it on insert.
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	// Sequence, PrevHash and Hash chain entries together, see Chain.
+	Sequence      uint64     `bson:"sequence,omitempty" json:"sequence,omitempty"`
+	PrevHash      string     `bson:"prev_hash,omitempty" json:"prev_hash,omitempty"`
+	Hash          string     `bson:"hash,omitempty" json:"hash,omitempty"`
+	SchemaVersion int        `bson:"schema_version,omitempty" json:"schema_version"`
+	Action        Action     `bson:"action,omitempty" json:"action,omitempty"`
+	EntityType    EntityType `bson:"entity_type,omitempty" json:"entity_type,omitempty"`
+	EntityID      uint       `bson:"entity_id,omitempty" json:"entity_id,omitempty"`
+	Actor         string     `bson:"actor,omitempty" json:"actor,omitempty"`
+	Reason        string     `bson:"reason,omitempty" json:"reason,omitempty"`
+	RequestID     string     `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	// CausedBy is the flag whose deactivation auto-disabled the entity.
+	CausedBy  uint       `bson:"caused_by,omitempty" json:"caused_by,omitempty"`
+	Before    *FlagState `bson:"before,omitempty" json:"before,omitempty"`
+	After     *FlagState `bson:"after,omitempty" json:"after,omitempty"`
+	Message   string     `bson:"message" json:"message"`
+	Timestamp time.Time  `bson:"timestamp" json:"timestamp"`
+	// Metadata holds action specific details such as the target list of
+	// a targets change. Legacy entries keep all their fields here.
+	Metadata map[string]any `bson:"metadata,omitempty" json:"metadata,omitempty"`
+}
+
+// UpgradeLegacy fills the typed fields of an entry written before
+// SchemaVersion from its message and metadata keys.
+func (e *LogEntry) UpgradeLegacy() {
+	if e.SchemaVersion != 0 {
+		return
+	}
+
+	if action, ok := e.Metadata["action"].(string); ok {
+		e.Action = Action(action)
+	} else {
+		for action, message := range legacyActionMessages {
+			if message == e.Message {
+				e.Action = action
+				break
+			}
+		}
+	}
+
+	if flagId, ok := toUint(e.Metadata["flag_id"]); ok {
+		e.EntityType = EntityTypeFlag
+		e.EntityID = flagId
+	}
+	if reason, ok := e.Metadata["reason"].(string); ok {
+		e.Reason = reason
+	}
+	if causedBy, ok := toUint(e.Metadata["dependecy_flag_id"]); ok {
+		e.CausedBy = causedBy
+	}
+}
+
+// toUint converts the integer types Mongo decodes numbers into.
+func toUint(value any) (uint, bool) {
+	switch v := value.(type) {
+	case int32:
+		return uint(v), v >= 0
+	case int64:
+		return uint(v), v >= 0
+	case int:
+		return uint(v), v >= 0
+	case uint:
+		return v, true
+	case float64:
+		return uint(v), v >= 0
+	default:
+		return 0, false
+	}
 }
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInvalidPageCursor = errors.New("invalid page cursor")
+
+// PageCursor is a position between two entries in the order of Filter.Sort,
+// at the entry with Timestamp and ID. Unlike an offset it stays put when
+// entries are written while a client pages.
+type PageCursor struct {
+	Timestamp time.Time
+	ID        primitive.ObjectID
+	// Ascending is the order the cursor was made for.
+	Ascending bool
+	// Before selects the page ending before the entry rather than the page
+	// starting after it.
+	Before bool
+}
+
+type pageCursorContent struct {
+	Timestamp string `json:"t"`
+	ID        string `json:"id"`
+	Ascending bool   `json:"asc,omitempty"`
+	Before    bool   `json:"before,omitempty"`
+}
+
+func newPageCursor(entry *LogEntry, filter *Filter, before bool) *PageCursor {
+	return &PageCursor{
+		Timestamp: entry.Timestamp,
+		ID:        entry.ID,
+		Ascending: filter.Ascending,
+		Before:    before,
+	}
+}
+
+// Encode returns the cursor as an opaque URL safe string.
+func (c *PageCursor) Encode() string {
+	content, _ := json.Marshal(pageCursorContent{
+		Timestamp: c.Timestamp.UTC().Format(time.RFC3339Nano),
+		ID:        c.ID.Hex(),
+		Ascending: c.Ascending,
+		Before:    c.Before,
+	})
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func DecodePageCursor(value string) (*PageCursor, error) {
+	content, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+
+	var cursorContent pageCursorContent
+	if err := json.Unmarshal(content, &cursorContent); err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, cursorContent.Timestamp)
+	if err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+	id, err := primitive.ObjectIDFromHex(cursorContent.ID)
+	if err != nil {
+		return nil, ErrInvalidPageCursor
+	}
+
+	return &PageCursor{
+		Timestamp: timestamp,
+		ID:        id,
+		Ascending: cursorContent.Ascending,
+		Before:    cursorContent.Before,
+	}, nil
+}
+
+// LogPage is a page of entries with the cursors of the pages around it,
+// nil when there is no entry in that direction.
+type LogPage struct {
+	Logs []*LogEntry
+	Next *PageCursor
+	Prev *PageCursor
+}
+
+// FindLogsPage returns up to limit entries in collection matching filter,
+// starting after cursor or ending before it. A nil cursor starts at the
+// first entry. One entry more than limit is read to tell whether there is
+// another page in the direction of the read.
+func FindLogsPage(
+	ctx context.Context,
+	collection *mongo.Collection,
+	filter *Filter,
+	cursor *PageCursor,
+	limit uint,
+) (*LogPage, error) {
+	backward := cursor != nil && cursor.Before
+
+	query := filter.BSON()
+	if cursor != nil {
+		query = bson.M{"$and": bson.A{query, cursor.BSON()}}
+	}
+
+	sort := filter.Sort()
+	if backward {
+		sort = (&Filter{Ascending: !filter.Ascending}).Sort()
+	}
+	findOptions := options.Find()
+	findOptions.SetLimit(int64(limit) + 1)
+	findOptions.SetSort(sort)
+
+	result, err := collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close(ctx)
+
+	logs := make([]*LogEntry, 0)
+	if err = result.All(ctx, &logs); err != nil {
+		return nil, err
+	}
+	more := uint(len(logs)) > limit
+	if more {
+		logs = logs[:limit]
+	}
+	if backward {
+		slices.Reverse(logs)
+	}
+	for _, entry := range logs {
+		entry.UpgradeLegacy()
+	}
+
+	page := &LogPage{Logs: logs}
+	if len(logs) == 0 {
+		// Nothing is left in the direction of the read; the way back
+		// starts from the cursor itself.
+		if cursor != nil {
+			back := *cursor
+			back.Before = !cursor.Before
+			if backward {
+				page.Next = &back
+			} else {
+				page.Prev = &back
+			}
+		}
+		return page, nil
+	}
+
+	first, last := logs[0], logs[len(logs)-1]
+	if backward {
+		page.Next = newPageCursor(last, filter, false)
+		if more {
+			page.Prev = newPageCursor(first, filter, true)
+		}
+	} else {
+		if more {
+			page.Next = newPageCursor(last, filter, false)
+		}
+		if cursor != nil {
+			page.Prev = newPageCursor(first, filter, true)
+		}
+	}
+	return page, nil
+}
+
+// BSON matches the entries after the cursor in its order, or before it
+// for a cursor to the previous page.
+func (c *PageCursor) BSON() bson.M {
+	operator := "$lt"
+	if c.Ascending != c.Before {
+		operator = "$gt"
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{"timestamp": bson.M{operator: c.Timestamp}},
+		bson.M{"timestamp": c.Timestamp, "_id": bson.M{operator: c.ID}},
+	}}
+}
+
+// CountLogs returns the number of entries in collection matching filter.
+func CountLogs(ctx context.Context, collection *mongo.Collection, filter *Filter) (uint, error) {
+	total, err := collection.CountDocuments(ctx, filter.BSON())
+	if err != nil {
+		return 0, err
+	}
+	return uint(total), nil
+}
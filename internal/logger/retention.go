@@ -0,0 +1,263 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/mongodb"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	retentionInterval  = time.Hour
+	retentionBatchSize = 1000
+)
+
+// Retainer removes log entries older than the retention period from the
+// log collection, moving them into the archive collection first with
+// archive retention. Chained entries are only ever removed as a prefix of
+// the chain, and the last removed entry is kept as the chain anchor, so
+// VerifyChain can tell retention apart from deletion.
+type Retainer struct {
+	collection *mongo.Collection
+	archive    *mongo.Collection
+	retention  time.Duration
+	failures   atomic.Uint64
+}
+
+// GetRetainer returns the retainer of the configured log collection, or
+// nil unless retention is configured.
+func GetRetainer() (*Retainer, error) {
+	retention, err := GetRetention()
+	if err != nil {
+		return nil, err
+	}
+	if retention == 0 {
+		return nil, nil
+	}
+	mode, err := GetRetentionMode()
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := GetCollection()
+	if err != nil {
+		return nil, err
+	}
+	var archive *mongo.Collection
+	if mode == RetentionModeArchive {
+		archiveCollection, err := GetArchiveCollection()
+		if err != nil {
+			return nil, err
+		}
+		archive = mongodb.GetCollection(archiveCollection)
+	}
+	return NewRetainer(mongodb.GetCollection(collection), archive, retention), nil
+}
+
+// NewRetainer returns a retainer that deletes expired entries, or moves
+// them to archive when it is not nil.
+func NewRetainer(collection, archive *mongo.Collection, retention time.Duration) *Retainer {
+	return &Retainer{
+		collection: collection,
+		archive:    archive,
+		retention:  retention,
+	}
+}
+
+func (r *Retainer) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		// Failures are counted and retried on the next tick.
+		if _, err := r.Expire(context.Background(), time.Now().Add(-r.retention)); err != nil {
+			r.failures.Add(1)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Failures is the number of retention runs that failed.
+func (r *Retainer) Failures() uint64 {
+	return r.failures.Load()
+}
+
+// Expire removes entries with a timestamp before cutoff and returns how
+// many it removed. Chained entries are removed in sequence order up to the
+// oldest entry that is still retained, and the head of the chain is always
+// kept, so an entry that is written late with an older timestamp holds
+// back the entries after it rather than leaving a gap.
+func (r *Retainer) Expire(ctx context.Context, cutoff time.Time) (int, error) {
+	removed, err := r.expireUnchained(ctx, cutoff)
+	if err != nil {
+		return removed, err
+	}
+	chained, err := r.expireChained(ctx, cutoff)
+	return removed + chained, err
+}
+
+// expireUnchained removes expired entries written before chaining.
+func (r *Retainer) expireUnchained(ctx context.Context, cutoff time.Time) (int, error) {
+	filter := bson.M{"sequence": bson.M{"$exists": false}, "timestamp": bson.M{"$lt": cutoff}}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}}).
+		SetLimit(retentionBatchSize)
+
+	removed := 0
+	for {
+		cursor, err := r.collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return removed, err
+		}
+		var documents []bson.Raw
+		if err := cursor.All(ctx, &documents); err != nil {
+			return removed, err
+		}
+		if len(documents) == 0 {
+			return removed, nil
+		}
+
+		if err := r.archiveDocuments(ctx, documents); err != nil {
+			return removed, err
+		}
+		if err := r.deleteDocuments(ctx, documents); err != nil {
+			return removed, err
+		}
+
+		removed += len(documents)
+		if len(documents) < retentionBatchSize {
+			return removed, nil
+		}
+	}
+}
+
+// expireChained removes the expired prefix of the chain in batches. Each
+// batch is verified against the anchor before it goes, archived, recorded
+// as the new anchor and only then deleted, so a failure in between leaves
+// entries behind the anchor rather than a gap in front of it; those are
+// deleted on the next run.
+func (r *Retainer) expireChained(ctx context.Context, cutoff time.Time) (int, error) {
+	anchor, err := GetChainAnchor(ctx, r.collection)
+	if err != nil {
+		return 0, err
+	}
+	if anchor != nil {
+		if _, err := r.collection.DeleteMany(ctx, bson.M{"sequence": bson.M{"$lte": anchor.Sequence}}); err != nil {
+			return 0, err
+		}
+	}
+
+	end, err := r.retainedSequence(ctx, cutoff)
+	if err != nil || end == 0 {
+		return 0, err
+	}
+
+	verifier := NewChainVerifier(anchor)
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetLimit(retentionBatchSize)
+
+	removed := 0
+	for {
+		var after uint64
+		if anchor != nil {
+			after = anchor.Sequence
+		}
+		filter := bson.M{"sequence": bson.M{"$gt": after, "$lt": end}}
+		cursor, err := r.collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return removed, err
+		}
+		var documents []bson.Raw
+		if err := cursor.All(ctx, &documents); err != nil {
+			return removed, err
+		}
+		if len(documents) == 0 {
+			return removed, nil
+		}
+
+		var last LogEntry
+		for _, document := range documents {
+			if err := bson.Unmarshal(document, &last); err != nil {
+				return removed, err
+			}
+			if !verifier.Add(&last) {
+				chainBreak := verifier.Result().Break
+				return removed, fmt.Errorf("log chain is broken at sequence %d: %s", chainBreak.Sequence, chainBreak.Reason)
+			}
+		}
+
+		if err := r.archiveDocuments(ctx, documents); err != nil {
+			return removed, err
+		}
+		if err := setChainAnchor(ctx, r.collection, &last); err != nil {
+			return removed, err
+		}
+		if err := r.deleteDocuments(ctx, documents); err != nil {
+			return removed, err
+		}
+
+		anchor = &ChainAnchor{Sequence: last.Sequence, Hash: last.Hash}
+		removed += len(documents)
+		if len(documents) < retentionBatchSize {
+			return removed, nil
+		}
+	}
+}
+
+// retainedSequence returns the sequence of the oldest chained entry to
+// keep: the first one written at or after cutoff, or else the head.
+func (r *Retainer) retainedSequence(ctx context.Context, cutoff time.Time) (uint64, error) {
+	findOptions := options.FindOne().
+		SetSort(bson.D{{Key: "sequence", Value: 1}}).
+		SetProjection(bson.M{"sequence": 1})
+
+	var entry LogEntry
+	err := r.collection.FindOne(ctx, bson.M{
+		"sequence":  bson.M{"$exists": true},
+		"timestamp": bson.M{"$gte": cutoff},
+	}, findOptions).Decode(&entry)
+	if err == nil {
+		return entry.Sequence, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, err
+	}
+
+	sequence, _, err := chainHead(ctx, r.collection)
+	return sequence, err
+}
+
+// archiveDocuments inserts documents into the archive. Retried inserts
+// skip documents already archived.
+func (r *Retainer) archiveDocuments(ctx context.Context, documents []bson.Raw) error {
+	if r.archive == nil {
+		return nil
+	}
+	_, err := r.archive.InsertMany(ctx, utils.ToAnySlice(documents), options.InsertMany().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *Retainer) deleteDocuments(ctx context.Context, documents []bson.Raw) error {
+	ids := make(bson.A, 0, len(documents))
+	for _, document := range documents {
+		ids = append(ids, document.Lookup("_id"))
+	}
+	_, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
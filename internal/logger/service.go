@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/mongodb"
-	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -35,20 +34,16 @@ func NewService() IService {
 
 type Service struct {
 	collection *mongo.Collection
+	// mu saves writers of this process from racing each other for the
+	// head of the chain; other processes are handled by appendToChain.
+	mu sync.Mutex
 }
 
 func (s *Service) Log(entry *LogEntry) error {
-	writeTimeout, err := GetWriteTimeOut()
-	if err != nil {
-		panic("Faile to get mongo write time out: " + err.Error())
-	}
-	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout*time.Second)
-	defer cancel()
-
-	_, err = s.collection.InsertOne(ctx, entry)
-	return err
+	return s.LogBatch([]*LogEntry{entry})
 }
 
+// LogBatch appends entries to the hash chain in order.
 func (s *Service) LogBatch(entries []*LogEntry) error {
 	if len(entries) == 0 {
 		return nil
@@ -62,6 +57,7 @@ func (s *Service) LogBatch(entries []*LogEntry) error {
 	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout*time.Second)
 	defer cancel()
 
-	_, err = s.collection.InsertMany(ctx, utils.ToAnySlice(entries))
-	return err
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return appendToChain(ctx, s.collection, entries)
 }
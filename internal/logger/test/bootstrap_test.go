@@ -0,0 +1,105 @@
+package logger_test
+
+import (
+	"os"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func unsetEnv(key string) {
+	if value, exists := os.LookupEnv(key); exists {
+		Expect(os.Unsetenv(key)).To(Succeed())
+		DeferCleanup(os.Setenv, key, value)
+	}
+}
+
+var _ = Describe("Bootstrap", func() {
+	Describe("Get Index Models", func() {
+		It("should index flag logs by flag id, timestamp and id", func() {
+			models := logger.GetIndexModels()
+
+			Expect(models).To(HaveLen(4))
+			Expect(models[0].Keys).To(Equal(bson.D{
+				{Key: "metadata.flag_id", Value: 1},
+				{Key: "timestamp", Value: -1},
+				{Key: "_id", Value: -1},
+			}))
+			Expect(*models[0].Options.Name).To(Equal(logger.FlagTimestampIndexName))
+			Expect(models[1].Keys).To(Equal(bson.D{
+				{Key: "entity_type", Value: 1},
+				{Key: "entity_id", Value: 1},
+				{Key: "timestamp", Value: -1},
+				{Key: "_id", Value: -1},
+			}))
+			Expect(*models[1].Options.Name).To(Equal(logger.EntityTimestampIndexName))
+			Expect(models[2].Keys).To(Equal(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}))
+			Expect(*models[2].Options.Name).To(Equal(logger.TimestampIndexName))
+			Expect(models[3].Keys).To(Equal(bson.D{{Key: "sequence", Value: 1}}))
+			Expect(*models[3].Options.Name).To(Equal(logger.SequenceIndexName))
+			Expect(*models[3].Options.Unique).To(BeTrue())
+			Expect(models[3].Options.PartialFilterExpression).To(Equal(bson.M{"sequence": bson.M{"$exists": true}}))
+		})
+
+		It("should not expire entries by timestamp", func() {
+			for _, model := range logger.GetIndexModels() {
+				Expect(model.Options.ExpireAfterSeconds).To(BeNil())
+			}
+		})
+	})
+
+	Describe("Get Validator", func() {
+		It("should require a message and a timestamp", func() {
+			schema := logger.GetValidator()["$jsonSchema"].(bson.M)
+
+			Expect(schema["required"]).To(Equal(bson.A{"message", "timestamp"}))
+			Expect(schema["properties"].(bson.M)["timestamp"]).To(Equal(bson.M{"bsonType": "date"}))
+		})
+	})
+
+	Describe("Retention config", func() {
+		It("should keep logs forever by default", func() {
+			unsetEnv("MONGO_LOG_RETENTION_DAYS")
+			unsetEnv("MONGO_LOG_RETENTION_MODE")
+
+			retention, err := logger.GetRetention()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retention).To(BeZero())
+
+			mode, err := logger.GetRetentionMode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mode).To(Equal(logger.RetentionModeTTL))
+		})
+
+		It("should parse retention days and mode", func() {
+			GinkgoT().Setenv("MONGO_LOG_RETENTION_DAYS", "7")
+			GinkgoT().Setenv("MONGO_LOG_RETENTION_MODE", "archive")
+			GinkgoT().Setenv("MONGO_LOG_COLLECTION", "logs")
+
+			retention, err := logger.GetRetention()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(retention).To(Equal(7 * 24 * time.Hour))
+
+			mode, err := logger.GetRetentionMode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(mode).To(Equal(logger.RetentionModeArchive))
+
+			archive, err := logger.GetArchiveCollection()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(archive).To(Equal("logs_archive"))
+		})
+
+		It("should reject invalid values", func() {
+			GinkgoT().Setenv("MONGO_LOG_RETENTION_DAYS", "-1")
+			GinkgoT().Setenv("MONGO_LOG_RETENTION_MODE", "delete")
+
+			_, err := logger.GetRetention()
+			Expect(err).To(HaveOccurred())
+			_, err = logger.GetRetentionMode()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
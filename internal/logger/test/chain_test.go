@@ -0,0 +1,203 @@
+package logger_test
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chain", func() {
+	newEntries := func() []*logger.LogEntry {
+		return []*logger.LogEntry{
+			{
+				SchemaVersion: logger.SchemaVersion,
+				Action:        logger.ActionToggled,
+				EntityType:    logger.EntityTypeFlag,
+				EntityID:      1,
+				Actor:         "alice",
+				Reason:        "incident",
+				Before:        &logger.FlagState{Name: "checkout", IsActive: true, Dependencies: []uint{}},
+				After:         &logger.FlagState{Name: "checkout", IsActive: false, Dependencies: []uint{}},
+				Message:       "Feature Flag is toggled successfully",
+				Timestamp:     time.Date(2026, 1, 1, 10, 0, 0, 123456789, time.UTC),
+			},
+			{
+				SchemaVersion: logger.SchemaVersion,
+				Action:        logger.ActionAutoDisabled,
+				EntityType:    logger.EntityTypeFlag,
+				EntityID:      2,
+				Actor:         "alice",
+				CausedBy:      1,
+				Message:       "Flag is auto disabled",
+				Timestamp:     time.Date(2026, 1, 1, 10, 0, 1, 0, time.UTC),
+			},
+			{
+				SchemaVersion: logger.SchemaVersion,
+				Action:        logger.ActionTargetsAdded,
+				EntityType:    logger.EntityTypeFlag,
+				EntityID:      1,
+				Message:       "Feature Flag targets are added successfully",
+				Timestamp:     time.Date(2026, 1, 1, 10, 0, 2, 0, time.UTC),
+				Metadata:      map[string]any{"targets": []any{"a", "b"}},
+			},
+		}
+	}
+
+	verifyAfter := func(anchor *logger.ChainAnchor, entries []*logger.LogEntry) *logger.ChainVerification {
+		verifier := logger.NewChainVerifier(anchor)
+		for _, entry := range entries {
+			if !verifier.Add(entry) {
+				break
+			}
+		}
+		return verifier.Result()
+	}
+
+	verify := func(entries []*logger.LogEntry) *logger.ChainVerification {
+		return verifyAfter(nil, entries)
+	}
+
+	Describe("Chain", func() {
+		It("should number entries after the head and link them", func() {
+			entries := newEntries()
+
+			Expect(logger.Chain(entries, 41, "head")).To(Succeed())
+
+			Expect(entries[0].Sequence).To(Equal(uint64(42)))
+			Expect(entries[0].PrevHash).To(Equal("head"))
+			Expect(entries[1].Sequence).To(Equal(uint64(43)))
+			Expect(entries[1].PrevHash).To(Equal(entries[0].Hash))
+			Expect(entries[2].Sequence).To(Equal(uint64(44)))
+			Expect(entries[2].PrevHash).To(Equal(entries[1].Hash))
+			Expect(entries[0].Hash).To(HaveLen(64))
+		})
+
+		It("should cut timestamps to milliseconds before hashing", func() {
+			entries := newEntries()
+
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+
+			Expect(entries[0].Timestamp).To(Equal(time.Date(2026, 1, 1, 10, 0, 0, 123000000, time.UTC)))
+			hash, err := logger.ComputeHash(entries[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hash).To(Equal(entries[0].Hash))
+		})
+	})
+
+	Describe("Verifier", func() {
+		It("should accept an intact chain", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+
+			result := verify(entries)
+
+			Expect(result.Valid).To(BeTrue())
+			Expect(result.Break).To(BeNil())
+			Expect(result.Entries).To(Equal(uint64(3)))
+			Expect(result.FirstSequence).To(Equal(uint64(1)))
+			Expect(result.LastSequence).To(Equal(uint64(3)))
+			Expect(result.LastHash).To(Equal(entries[2].Hash))
+		})
+
+		It("should accept a chain whose oldest entries were removed by retention", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+			anchor := &logger.ChainAnchor{Sequence: 1, Hash: entries[0].Hash}
+
+			result := verifyAfter(anchor, entries[1:])
+
+			Expect(result.Valid).To(BeTrue())
+			Expect(result.Anchor).To(Equal(anchor))
+			Expect(result.FirstSequence).To(Equal(uint64(2)))
+		})
+
+		It("should detect oldest entries removed without an anchor", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+
+			result := verify(entries[1:])
+
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Break).To(Equal(&logger.ChainBreak{Sequence: 1, Reason: "entry is missing, next entry is 2"}))
+		})
+
+		It("should detect oldest entries removed past the anchor", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+
+			result := verifyAfter(&logger.ChainAnchor{Sequence: 1, Hash: entries[0].Hash}, entries[2:])
+
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Break).To(Equal(&logger.ChainBreak{Sequence: 2, Reason: "entry is missing, next entry is 3"}))
+		})
+
+		It("should detect an anchor that does not match the entry after it", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+
+			result := verifyAfter(&logger.ChainAnchor{Sequence: 1, Hash: "forged"}, entries[1:])
+
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Break).To(Equal(&logger.ChainBreak{
+				Sequence: 2,
+				Reason:   "previous hash does not match the previous entry",
+			}))
+		})
+
+		It("should accept an empty chain", func() {
+			result := verify(nil)
+
+			Expect(result.Valid).To(BeTrue())
+			Expect(result.Entries).To(BeZero())
+		})
+
+		It("should detect edited content", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+			entries[1].Actor = "mallory"
+
+			result := verify(entries)
+
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Break).To(Equal(&logger.ChainBreak{Sequence: 2, Reason: "content does not match its hash"}))
+			Expect(result.LastSequence).To(Equal(uint64(1)))
+		})
+
+		It("should detect edited metadata", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+			entries[2].Metadata["targets"] = []any{"a"}
+
+			result := verify(entries)
+
+			Expect(result.Break.Sequence).To(Equal(uint64(3)))
+		})
+
+		It("should detect a removed entry", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+
+			result := verify([]*logger.LogEntry{entries[0], entries[2]})
+
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Break).To(Equal(&logger.ChainBreak{Sequence: 2, Reason: "entry is missing, next entry is 3"}))
+		})
+
+		It("should detect an entry rehashed after an edit", func() {
+			entries := newEntries()
+			Expect(logger.Chain(entries, 0, "")).To(Succeed())
+			entries[1].Actor = "mallory"
+			Expect(logger.Chain(entries[1:2], 1, entries[0].Hash)).To(Succeed())
+
+			result := verify(entries)
+
+			Expect(result.Valid).To(BeFalse())
+			Expect(result.Break).To(Equal(&logger.ChainBreak{
+				Sequence: 3,
+				Reason:   "previous hash does not match the previous entry",
+			}))
+		})
+	})
+})
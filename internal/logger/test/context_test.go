@@ -0,0 +1,23 @@
+package logger_test
+
+import (
+	"context"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Context", func() {
+	It("should attribute changes outside of a request to the system", func() {
+		Expect(logger.GetActor(context.Background())).To(Equal(logger.SystemActor))
+		Expect(logger.GetRequestID(context.Background())).To(BeEmpty())
+	})
+
+	It("should return the actor and request id stored in the context", func() {
+		ctx := logger.WithActor(logger.WithRequestID(context.Background(), "req-1"), "alice")
+
+		Expect(logger.GetActor(ctx)).To(Equal("alice"))
+		Expect(logger.GetRequestID(ctx)).To(Equal("req-1"))
+	})
+})
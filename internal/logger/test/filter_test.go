@@ -0,0 +1,80 @@
+package logger_test
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// orLegacy mirrors the fallback the filter uses for untyped entries.
+func orLegacy(condition, legacy bson.M) bson.M {
+	return bson.M{"$or": bson.A{
+		condition,
+		bson.M{"$and": bson.A{bson.M{"schema_version": bson.M{"$exists": false}}, legacy}},
+	}}
+}
+
+var _ = Describe("Filter", func() {
+	It("should match everything when empty", func() {
+		filter := &logger.Filter{}
+
+		Expect(filter.BSON()).To(Equal(bson.M{}))
+	})
+
+	It("should combine every condition", func() {
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		filter := &logger.Filter{
+			From:    from,
+			To:      to,
+			FlagIds: []uint{1, 2},
+			Actor:   "alice",
+			Reason:  "v1.2 rollout",
+		}
+		reason := bson.M{"$regex": `v1\.2 rollout`, "$options": "i"}
+
+		Expect(filter.BSON()).To(Equal(bson.M{"$and": bson.A{
+			bson.M{"timestamp": bson.M{"$gte": from, "$lt": to}},
+			orLegacy(
+				bson.M{"entity_type": logger.EntityTypeFlag, "entity_id": bson.M{"$in": []uint{1, 2}}},
+				bson.M{"metadata.flag_id": bson.M{"$in": []uint{1, 2}}},
+			),
+			bson.M{"actor": "alice"},
+			orLegacy(bson.M{"reason": reason}, bson.M{"metadata.reason": reason}),
+		}}))
+	})
+
+	It("should match legacy entries by message when filtering by action", func() {
+		filter := &logger.Filter{Action: logger.ActionToggled}
+
+		Expect(filter.BSON()).To(Equal(bson.M{"$and": bson.A{
+			orLegacy(
+				bson.M{"action": logger.ActionToggled},
+				bson.M{"$or": bson.A{
+					bson.M{"metadata.action": logger.ActionToggled},
+					bson.M{
+						"metadata.action": bson.M{"$exists": false},
+						"message":         "Feature Flag is toggled successfully",
+					},
+				}},
+			),
+		}}))
+	})
+
+	It("should include or exclude cascaded entries", func() {
+		cascaded := orLegacy(
+			bson.M{"caused_by": bson.M{"$exists": true}},
+			bson.M{"$or": bson.A{
+				bson.M{"metadata.cascade": true},
+				bson.M{"message": "Flag is auto disabled"},
+			}},
+		)
+		include, exclude := true, false
+
+		Expect((&logger.Filter{Cascade: &include}).BSON()).To(Equal(bson.M{"$and": bson.A{cascaded}}))
+		Expect((&logger.Filter{Cascade: &exclude}).BSON()).To(Equal(bson.M{"$and": bson.A{bson.M{"$nor": bson.A{cascaded}}}}))
+	})
+})
@@ -0,0 +1,67 @@
+package logger_test
+
+import (
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Log Entry", func() {
+	Describe("Upgrade Legacy", func() {
+		It("should derive the action from the message", func() {
+			entry := &logger.LogEntry{
+				Message:  "Feature Flag is toggled successfully",
+				Metadata: map[string]any{"flag_id": int64(4), "active": false, "reason": "incident"},
+			}
+
+			entry.UpgradeLegacy()
+
+			Expect(entry.Action).To(Equal(logger.ActionToggled))
+			Expect(entry.EntityType).To(Equal(logger.EntityTypeFlag))
+			Expect(entry.EntityID).To(Equal(uint(4)))
+			Expect(entry.Reason).To(Equal("incident"))
+			Expect(entry.CausedBy).To(BeZero())
+			Expect(entry.Metadata).To(HaveKeyWithValue("active", false))
+		})
+
+		It("should read the cascade source of auto disabled entries", func() {
+			entry := &logger.LogEntry{
+				Message:  "Flag is auto disabled",
+				Metadata: map[string]any{"flag_id": int32(5), "dependecy_flag_id": int32(4)},
+			}
+
+			entry.UpgradeLegacy()
+
+			Expect(entry.Action).To(Equal(logger.ActionAutoDisabled))
+			Expect(entry.EntityID).To(Equal(uint(5)))
+			Expect(entry.CausedBy).To(Equal(uint(4)))
+		})
+
+		It("should prefer a recorded action over the message", func() {
+			entry := &logger.LogEntry{
+				Message:  "Feature Flag targets are added successfully",
+				Metadata: map[string]any{"action": "targets_added", "flag_id": int64(2)},
+			}
+
+			entry.UpgradeLegacy()
+
+			Expect(entry.Action).To(Equal(logger.ActionTargetsAdded))
+		})
+
+		It("should leave typed entries untouched", func() {
+			entry := &logger.LogEntry{
+				SchemaVersion: logger.SchemaVersion,
+				Action:        logger.ActionCreated,
+				EntityType:    logger.EntityTypeFlag,
+				EntityID:      1,
+				Message:       "Feature Flag is toggled successfully",
+				Metadata:      map[string]any{"flag_id": int64(9)},
+			}
+
+			entry.UpgradeLegacy()
+
+			Expect(entry.Action).To(Equal(logger.ActionCreated))
+			Expect(entry.EntityID).To(Equal(uint(1)))
+		})
+	})
+})
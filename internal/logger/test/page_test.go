@@ -0,0 +1,60 @@
+package logger_test
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ = Describe("Page Cursor", func() {
+	var cursor *logger.PageCursor
+
+	BeforeEach(func() {
+		cursor = &logger.PageCursor{
+			Timestamp: time.Date(2026, 3, 1, 10, 0, 0, 123000000, time.UTC),
+			ID:        primitive.NewObjectID(),
+		}
+	})
+
+	It("should decode what it encodes", func() {
+		cursor.Ascending = true
+		cursor.Before = true
+
+		decoded, err := logger.DecodePageCursor(cursor.Encode())
+
+		Expect(err).To(BeNil())
+		Expect(decoded).To(Equal(cursor))
+	})
+
+	DescribeTable("should reject invalid cursors",
+		func(value string) {
+			decoded, err := logger.DecodePageCursor(value)
+
+			Expect(decoded).To(BeNil())
+			Expect(err).To(MatchError(logger.ErrInvalidPageCursor))
+		},
+		Entry("not base64", "not a cursor!"),
+		Entry("not json", "bm90IGpzb24"),
+		Entry("bad id", "eyJ0IjoiMjAyNi0wMy0wMVQxMDowMDowMFoiLCJpZCI6Inh5eiJ9"),
+	)
+
+	DescribeTable("should match the entries past the cursor with the id breaking timestamp ties",
+		func(ascending, before bool, operator string) {
+			cursor.Ascending = ascending
+			cursor.Before = before
+
+			Expect(cursor.BSON()).To(Equal(bson.M{"$or": bson.A{
+				bson.M{"timestamp": bson.M{operator: cursor.Timestamp}},
+				bson.M{"timestamp": cursor.Timestamp, "_id": bson.M{operator: cursor.ID}},
+			}}))
+		},
+		Entry("next page newest first", false, false, "$lt"),
+		Entry("previous page newest first", false, true, "$gt"),
+		Entry("next page oldest first", true, false, "$gt"),
+		Entry("previous page oldest first", true, true, "$lt"),
+	)
+})
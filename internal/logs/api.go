@@ -0,0 +1,176 @@
+package logs
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func newLogsService() *Service {
+	return GetService(GetRepository())
+}
+
+// @Description Query parameters for the paginated audit log across every flag
+type GetLogsQueryParams struct {
+	PageQueryParams
+	FilterQueryParams
+	FlagIds []uint `form:"flag_id"`
+}
+
+// @Description Paginated audit log entries
+type GetLogsData struct {
+	Logs []*logger.LogEntry `json:"logs"`
+	api.PaginationResponse
+	CursorPaginationResponse
+}
+
+// @Summary Get audit logs
+// @Description Retrieve audit log entries of every feature flag, filtered by time range, flags, action, actor,
+// @Description reason and cascade, newest first unless sort=timestamp. Leaving out page pages by cursor instead: follow
+// @Description the next and prev cursors of the response, which stay stable while entries are written.
+// @Tags logs
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number, leave out to page by cursor" minimum(1)
+// @Param size query int true "Number of items per page" minimum(1) maximum(20)
+// @Param cursor query string false "Cursor of the page to fetch, from next or prev of a previous response"
+// @Param total query bool false "Count the matching entries when paging by cursor"
+// @Param from query string false "Only entries at or after this RFC 3339 time"
+// @Param to query string false "Only entries before this RFC 3339 time"
+// @Param flag_id query []int false "Only entries of these flags" collectionFormat(multi)
+// @Param action query string false "Only entries of this action" Enums(created, toggled, dependencies_updated, targets_added, targets_removed, auto_disabled, rolled_back, undone)
+// @Param actor query string false "Only entries made by this actor"
+// @Param reason query string false "Only entries whose reason contains this text, case-insensitively"
+// @Param cascade query bool false "Only cascaded entries when true, none when false"
+// @Param sort query string false "timestamp for oldest first, -timestamp for newest first (default)" Enums(timestamp, -timestamp)
+// @Success 200 {object} api.SuccessResponse{data=GetLogsData} "Logs retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/logs [get]
+func GetLogsAPI(c *gin.Context) {
+	service := newLogsService()
+
+	query, err := service.ValidateGetLogsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.GetLogs(query)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Logs are retrieved successfully", data)
+}
+
+// @Description Query parameters for exporting the audit log
+type ExportLogsQueryParams struct {
+	FilterQueryParams
+	FlagIds []uint `form:"flag_id"`
+	Format  string `form:"format" binding:"required,oneof=csv ndjson"`
+}
+
+// @Summary Export audit logs
+// @Description Stream every audit log entry matching the filters as CSV or newline delimited JSON. The response is
+// @Description gzip compressed when the client accepts it. Nested values are JSON encoded in CSV cells.
+// @Tags logs
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string true "Export format" Enums(csv, ndjson)
+// @Param from query string false "Only entries at or after this RFC 3339 time"
+// @Param to query string false "Only entries before this RFC 3339 time"
+// @Param flag_id query []int false "Only entries of these flags" collectionFormat(multi)
+// @Param action query string false "Only entries of this action" Enums(created, toggled, dependencies_updated, targets_added, targets_removed, auto_disabled, rolled_back, undone)
+// @Param actor query string false "Only entries made by this actor"
+// @Param reason query string false "Only entries whose reason contains this text, case-insensitively"
+// @Param cascade query bool false "Only cascaded entries when true, none when false"
+// @Param sort query string false "timestamp for oldest first, -timestamp for newest first (default)" Enums(timestamp, -timestamp)
+// @Success 200 {string} string "Exported entries"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/logs/export [get]
+func ExportLogsAPI(c *gin.Context) {
+	service := newLogsService()
+
+	query, apiErr := service.ValidateExportLogsRequest(c)
+	if apiErr != nil {
+		api.RespondAPIError(c, apiErr)
+		return
+	}
+
+	ctx := c.Request.Context()
+	cursor, apiErr := service.OpenExport(ctx, query)
+	if apiErr != nil {
+		api.RespondAPIError(c, apiErr)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if query.Format == ExportFormatNDJSON {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="logs.%s"`, query.Format))
+	c.Header("Vary", "Accept-Encoding")
+
+	var w io.Writer = c.Writer
+	var gz *gzip.Writer
+	if acceptsGzip(c.GetHeader("Accept-Encoding")) {
+		c.Header("Content-Encoding", "gzip")
+		gz = gzip.NewWriter(c.Writer)
+		w = gz
+	}
+	c.Status(http.StatusOK)
+
+	err := service.WriteExport(ctx, cursor, query.Format, w)
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		// The status is already sent, so a failure midway can only cut the
+		// export short; dropping the connection keeps it from looking
+		// complete.
+		c.Error(err)
+		if conn, _, hijackErr := c.Writer.Hijack(); hijackErr == nil {
+			conn.Close()
+		}
+	}
+}
+
+// @Summary Verify the audit log
+// @Description Walk the hash chain of the audit log in sequence order and report the first entry that was changed,
+// @Description removed or inserted out of order. The chain starts at the oldest retained entry.
+// @Tags logs
+// @Produce json
+// @Success 200 {object} api.SuccessResponse{data=logger.ChainVerification} "Audit log verified"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/logs/verify [get]
+func VerifyLogsAPI(c *gin.Context) {
+	service := newLogsService()
+
+	data, err := service.VerifyChain(c.Request.Context())
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Audit log is verified", data)
+}
+
+func acceptsGzip(acceptEncoding string) bool {
+	for _, encoding := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) == "gzip" && strings.ReplaceAll(params, " ", "") != "q=0" {
+			return true
+		}
+	}
+	return false
+}
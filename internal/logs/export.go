@@ -0,0 +1,144 @@
+package logs
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+var csvHeader = []string{
+	"timestamp",
+	"action",
+	"entity_type",
+	"entity_id",
+	"actor",
+	"reason",
+	"request_id",
+	"caused_by",
+	"before",
+	"after",
+	"message",
+	"metadata",
+}
+
+type exportWriter interface {
+	Write(entry *logger.LogEntry) error
+	Close() error
+}
+
+func newExportWriter(format string, w io.Writer) (exportWriter, error) {
+	if format == ExportFormatCSV {
+		writer := &csvExportWriter{w: csv.NewWriter(w)}
+		if err := writer.w.Write(csvHeader); err != nil {
+			return nil, err
+		}
+		return writer, nil
+	}
+	return &ndjsonExportWriter{encoder: json.NewEncoder(w)}, nil
+}
+
+type ndjsonExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonExportWriter) Write(entry *logger.LogEntry) error {
+	return n.encoder.Encode(entry)
+}
+
+func (n *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+// csvExportWriter writes one row per entry, with states and metadata as
+// JSON cells.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) Write(entry *logger.LogEntry) error {
+	before, err := jsonCell(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := jsonCell(entry.After)
+	if err != nil {
+		return err
+	}
+	metadata, err := jsonCell(entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	causedBy := ""
+	if entry.CausedBy != 0 {
+		causedBy = strconv.FormatUint(uint64(entry.CausedBy), 10)
+	}
+	return c.w.Write([]string{
+		entry.Timestamp.UTC().Format(time.RFC3339Nano),
+		string(entry.Action),
+		string(entry.EntityType),
+		strconv.FormatUint(uint64(entry.EntityID), 10),
+		textCell(entry.Actor),
+		textCell(entry.Reason),
+		textCell(entry.RequestID),
+		causedBy,
+		before,
+		after,
+		textCell(entry.Message),
+		metadata,
+	})
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// textCell keeps spreadsheets from evaluating user supplied text that
+// looks like a formula.
+func textCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func jsonCell[T any](value T) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	if string(raw) == "null" {
+		return "", nil
+	}
+	return string(raw), nil
+}
+
+// writeExport writes every entry of cursor to w in format.
+func writeExport(ctx context.Context, cursor LogCursor, format string, w io.Writer) error {
+	writer, err := newExportWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	for cursor.Next(ctx) {
+		if err := writer.Write(cursor.Entry()); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	return writer.Close()
+}
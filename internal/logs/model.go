@@ -0,0 +1,71 @@
+package logs
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+)
+
+// @Description Pagination of the log endpoints: by page, or by cursor when page is left out
+type PageQueryParams struct {
+	Size   uint   `form:"size" binding:"min=1,max=20"`
+	Page   uint   `form:"page" binding:"omitempty,min=1"`
+	Cursor string `form:"cursor" binding:"max=255"`
+	// Total asks cursor pages for the number of matching entries, which
+	// page/size pagination always counts.
+	Total bool `form:"total"`
+}
+
+// @Description Cursors of the pages around a page of log entries, absent at either end
+type CursorPaginationResponse struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// PageCursor returns the decoded cursor, nil for the first page or when
+// paging by page. The cursor is checked by ValidatePageQueryParams.
+func (q *PageQueryParams) PageCursor() *logger.PageCursor {
+	if q.Cursor == "" {
+		return nil
+	}
+	cursor, _ := logger.DecodePageCursor(q.Cursor)
+	return cursor
+}
+
+func NewCursorPaginationResponse(page *logger.LogPage) CursorPaginationResponse {
+	var response CursorPaginationResponse
+	if page.Next != nil {
+		response.Next = page.Next.Encode()
+	}
+	if page.Prev != nil {
+		response.Prev = page.Prev.Encode()
+	}
+	return response
+}
+
+// @Description Filters shared by the global and per-flag log endpoints
+type FilterQueryParams struct {
+	From    time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To      time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Action  string    `form:"action" binding:"omitempty,oneof=created toggled dependencies_updated targets_added targets_removed auto_disabled rolled_back undone"`
+	Actor   string    `form:"actor" binding:"max=255"`
+	Reason  string    `form:"reason" binding:"max=255"`
+	Cascade string    `form:"cascade" binding:"omitempty,oneof=true false"`
+	Sort    string    `form:"sort" binding:"omitempty,oneof=timestamp -timestamp"`
+}
+
+func (q *FilterQueryParams) ToFilter() *logger.Filter {
+	filter := &logger.Filter{
+		From:      q.From,
+		To:        q.To,
+		Action:    logger.Action(q.Action),
+		Actor:     q.Actor,
+		Reason:    q.Reason,
+		Ascending: q.Sort == "timestamp",
+	}
+	if q.Cascade != "" {
+		cascade := q.Cascade == "true"
+		filter.Cascade = &cascade
+	}
+	return filter
+}
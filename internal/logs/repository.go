@@ -0,0 +1,78 @@
+package logs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/mongodb"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// LogCursor iterates over log entries one at a time, see logger.LogCursor.
+type LogCursor interface {
+	Next(ctx context.Context) bool
+	Entry() *logger.LogEntry
+	Err() error
+	Close(ctx context.Context) error
+}
+
+type IRepository interface {
+	GetLogs(filter *logger.Filter, page, size uint) ([]*logger.LogEntry, uint, uint, error)
+	GetLogsPage(filter *logger.Filter, cursor *logger.PageCursor, size uint) (*logger.LogPage, error)
+	CountLogs(filter *logger.Filter) (uint, error)
+	OpenLogs(ctx context.Context, filter *logger.Filter) (LogCursor, error)
+	VerifyChain(ctx context.Context) (*logger.ChainVerification, error)
+}
+
+type Repository struct {
+	collection *mongo.Collection
+}
+
+var (
+	repo     IRepository
+	onceRepo sync.Once
+)
+
+func GetRepository() IRepository {
+	onceRepo.Do(func() {
+		collection, err := logger.GetCollection()
+		if err != nil {
+			panic("Failed to get logger collection: " + err.Error())
+		}
+		repo = &Repository{
+			collection: mongodb.GetCollection(collection),
+		}
+	})
+	return repo
+}
+
+func (r *Repository) GetLogs(filter *logger.Filter, page, size uint) ([]*logger.LogEntry, uint, uint, error) {
+	pager := &mongodb.Pager{
+		Page: page,
+		Size: size,
+	}
+
+	logs, err := logger.FindLogs(context.Background(), r.collection, filter, pager)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return logs, pager.Total, pager.TotalPages, nil
+}
+
+func (r *Repository) GetLogsPage(filter *logger.Filter, cursor *logger.PageCursor, size uint) (*logger.LogPage, error) {
+	return logger.FindLogsPage(context.Background(), r.collection, filter, cursor, size)
+}
+
+func (r *Repository) CountLogs(filter *logger.Filter) (uint, error) {
+	return logger.CountLogs(context.Background(), r.collection, filter)
+}
+
+func (r *Repository) OpenLogs(ctx context.Context, filter *logger.Filter) (LogCursor, error) {
+	return logger.OpenLogCursor(ctx, r.collection, filter)
+}
+
+func (r *Repository) VerifyChain(ctx context.Context) (*logger.ChainVerification, error) {
+	return logger.VerifyChain(ctx, r.collection)
+}
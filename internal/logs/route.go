@@ -0,0 +1,14 @@
+package logs
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	{
+		v1 := router.Group("/api/v1")
+		v1.GET("/logs", GetLogsAPI)
+		v1.GET("/logs/export", ExportLogsAPI)
+		v1.GET("/logs/verify", VerifyLogsAPI)
+	}
+}
@@ -0,0 +1,158 @@
+package logs
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type Service struct {
+	Repo IRepository
+}
+
+var (
+	service     *Service
+	onceService sync.Once
+)
+
+func GetService(repo IRepository) *Service {
+	onceService.Do(func() {
+		service = &Service{
+			Repo: repo,
+		}
+	})
+	return service
+}
+
+// ValidateFilterQueryParams checks the parts of a filter binding tags
+// cannot express.
+func ValidateFilterQueryParams(query *FilterQueryParams) *api.APIError {
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return api.BadRequestError("Invalid input format", "from must be before to")
+	}
+	return nil
+}
+
+// ValidatePageQueryParams checks that a page and a cursor are not mixed and
+// that the cursor was made for the order of filter.
+func ValidatePageQueryParams(query *PageQueryParams, filter *FilterQueryParams) *api.APIError {
+	if query.Cursor == "" {
+		return nil
+	}
+	if query.Page != 0 {
+		return api.BadRequestError("Invalid input format", "page and cursor cannot be used together")
+	}
+
+	cursor, err := logger.DecodePageCursor(query.Cursor)
+	if err != nil {
+		return api.BadRequestError("Invalid input format", err.Error())
+	}
+	if cursor.Ascending != filter.ToFilter().Ascending {
+		return api.BadRequestError("Invalid input format", "cursor was made for the other sort order")
+	}
+	return nil
+}
+
+func (s *Service) ValidateGetLogsRequest(c *gin.Context) (*GetLogsQueryParams, *api.APIError) {
+	var query GetLogsQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	if apiErr := ValidateFilterQueryParams(&query.FilterQueryParams); apiErr != nil {
+		return nil, apiErr
+	}
+	if apiErr := ValidatePageQueryParams(&query.PageQueryParams, &query.FilterQueryParams); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &query, nil
+}
+
+func (s *Service) GetLogs(query *GetLogsQueryParams) (*GetLogsData, *api.APIError) {
+	filter := query.ToFilter()
+	filter.FlagIds = query.FlagIds
+
+	if query.Page == 0 {
+		return s.getLogsPage(filter, &query.PageQueryParams)
+	}
+
+	logs, total, totalPages, err := s.Repo.GetLogs(filter, query.Page, query.Size)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	return &GetLogsData{
+		Logs: logs,
+		PaginationResponse: api.PaginationResponse{
+			Page:       query.Page,
+			Size:       query.Size,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}, nil
+}
+
+func (s *Service) getLogsPage(filter *logger.Filter, query *PageQueryParams) (*GetLogsData, *api.APIError) {
+	page, err := s.Repo.GetLogsPage(filter, query.PageCursor(), query.Size)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data := &GetLogsData{
+		Logs:                     page.Logs,
+		PaginationResponse:       api.PaginationResponse{Size: query.Size},
+		CursorPaginationResponse: NewCursorPaginationResponse(page),
+	}
+	if query.Total {
+		total, err := s.Repo.CountLogs(filter)
+		if err != nil {
+			return nil, api.InternalServerError("Internal Server Error", err.Error())
+		}
+		data.Total = total
+		data.TotalPages = (total + query.Size - 1) / query.Size
+	}
+	return data, nil
+}
+
+func (s *Service) ValidateExportLogsRequest(c *gin.Context) (*ExportLogsQueryParams, *api.APIError) {
+	var query ExportLogsQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	if apiErr := ValidateFilterQueryParams(&query.FilterQueryParams); apiErr != nil {
+		return nil, apiErr
+	}
+
+	return &query, nil
+}
+
+// OpenExport runs the export query up front, so that failing to run it
+// can still be reported before the response starts.
+func (s *Service) OpenExport(ctx context.Context, query *ExportLogsQueryParams) (LogCursor, *api.APIError) {
+	filter := query.ToFilter()
+	filter.FlagIds = query.FlagIds
+
+	cursor, err := s.Repo.OpenLogs(ctx, filter)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	return cursor, nil
+}
+
+// WriteExport streams the entries of cursor to w and closes the cursor.
+func (s *Service) WriteExport(ctx context.Context, cursor LogCursor, format string, w io.Writer) error {
+	defer cursor.Close(context.Background())
+	return writeExport(ctx, cursor, format, w)
+}
+
+func (s *Service) VerifyChain(ctx context.Context) (*logger.ChainVerification, *api.APIError) {
+	verification, err := s.Repo.VerifyChain(ctx)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	return verification, nil
+}
@@ -0,0 +1,172 @@
+package logs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	mockLogs "github.com/ArshiAbolghasemi/dom-cobb/internal/logs/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/testutils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Export", func() {
+	var (
+		repo    *mockLogs.MockRepository
+		service *logs.Service
+		entries []*logger.LogEntry
+	)
+
+	BeforeEach(func() {
+		repo = &mockLogs.MockRepository{}
+		service = &logs.Service{Repo: repo}
+		entries = []*logger.LogEntry{
+			{
+				SchemaVersion: logger.SchemaVersion,
+				Action:        logger.ActionToggled,
+				EntityType:    logger.EntityTypeFlag,
+				EntityID:      2,
+				Actor:         "alice",
+				Reason:        "=HYPERLINK(\"x\")",
+				RequestID:     "req-1",
+				Before:        &logger.FlagState{Name: "checkout", IsActive: false, Dependencies: []uint{1}},
+				After:         &logger.FlagState{Name: "checkout", IsActive: true, Dependencies: []uint{1}},
+				Message:       "Feature Flag is toggled successfully",
+				Timestamp:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+			{
+				SchemaVersion: logger.SchemaVersion,
+				Action:        logger.ActionAutoDisabled,
+				EntityType:    logger.EntityTypeFlag,
+				EntityID:      3,
+				CausedBy:      2,
+				Message:       "Flag is auto disabled",
+				Timestamp:     time.Date(2026, 1, 2, 3, 4, 6, 0, time.UTC),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		repo.AssertExpectations(GinkgoT())
+	})
+
+	Describe("Validate Export Logs Request", func() {
+		DescribeTable("should reject invalid requests",
+			func(url string) {
+				c, _ := testutils.CreateJSONRequest(http.MethodGet, url, nil)
+
+				query, err := service.ValidateExportLogsRequest(c)
+
+				Expect(query).To(BeNil())
+				Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			},
+			Entry("missing format", "/api/v1/logs/export"),
+			Entry("unknown format", "/api/v1/logs/export?format=xml"),
+			Entry("from after to", "/api/v1/logs/export?format=csv&from=2026-02-01T00:00:00Z&to=2026-01-01T00:00:00Z"),
+		)
+
+		It("should bind the filters without pagination", func() {
+			c, _ := testutils.CreateJSONRequest(http.MethodGet, "/api/v1/logs/export?format=ndjson&flag_id=2&action=toggled", nil)
+
+			query, err := service.ValidateExportLogsRequest(c)
+
+			Expect(err).To(BeNil())
+			Expect(query.Format).To(Equal(logs.ExportFormatNDJSON))
+			Expect(query.FlagIds).To(Equal([]uint{2}))
+			Expect(query.Action).To(Equal("toggled"))
+		})
+	})
+
+	Describe("Open Export", func() {
+		It("should open a cursor over the filtered entries", func() {
+			cursor := &mockLogs.SliceCursor{}
+			repo.On("OpenLogs", mock.Anything, &logger.Filter{FlagIds: []uint{2}, Actor: "alice"}).Return(cursor, nil)
+
+			query := &logs.ExportLogsQueryParams{
+				FilterQueryParams: logs.FilterQueryParams{Actor: "alice"},
+				FlagIds:           []uint{2},
+				Format:            logs.ExportFormatCSV,
+			}
+
+			result, err := service.OpenExport(context.Background(), query)
+
+			Expect(err).To(BeNil())
+			Expect(result).To(Equal(cursor))
+		})
+
+		It("should return api error with status code 500", func() {
+			repo.On("OpenLogs", mock.Anything, &logger.Filter{}).Return(nil, errors.New("connection refused"))
+
+			result, err := service.OpenExport(context.Background(), &logs.ExportLogsQueryParams{Format: logs.ExportFormatCSV})
+
+			Expect(result).To(BeNil())
+			Expect(err.StatusCode).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("Write Export", func() {
+		It("should write one csv row per entry", func() {
+			cursor := &mockLogs.SliceCursor{Entries: entries}
+			var out bytes.Buffer
+
+			Expect(service.WriteExport(context.Background(), cursor, logs.ExportFormatCSV, &out)).To(Succeed())
+
+			rows, err := csv.NewReader(&out).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rows).To(HaveLen(3))
+			Expect(rows[0]).To(Equal([]string{
+				"timestamp", "action", "entity_type", "entity_id", "actor", "reason",
+				"request_id", "caused_by", "before", "after", "message", "metadata",
+			}))
+			Expect(rows[1]).To(Equal([]string{
+				"2026-01-02T03:04:05Z",
+				"toggled",
+				"flag",
+				"2",
+				"alice",
+				"'=HYPERLINK(\"x\")",
+				"req-1",
+				"",
+				`{"name":"checkout","is_active":false,"dependencies":[1]}`,
+				`{"name":"checkout","is_active":true,"dependencies":[1]}`,
+				"Feature Flag is toggled successfully",
+				"",
+			}))
+			Expect(rows[2][1]).To(Equal("auto_disabled"))
+			Expect(rows[2][7]).To(Equal("2"))
+			Expect(cursor.Closed).To(BeTrue())
+		})
+
+		It("should write one json document per line", func() {
+			cursor := &mockLogs.SliceCursor{Entries: entries}
+			var out bytes.Buffer
+
+			Expect(service.WriteExport(context.Background(), cursor, logs.ExportFormatNDJSON, &out)).To(Succeed())
+
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			Expect(lines).To(HaveLen(2))
+			var entry logger.LogEntry
+			Expect(json.Unmarshal([]byte(lines[1]), &entry)).To(Succeed())
+			Expect(&entry).To(Equal(entries[1]))
+		})
+
+		It("should return the error of a failing cursor", func() {
+			failure := errors.New("cursor killed")
+			cursor := &mockLogs.SliceCursor{Entries: entries, Failure: failure}
+
+			err := service.WriteExport(context.Background(), cursor, logs.ExportFormatNDJSON, &bytes.Buffer{})
+
+			Expect(err).To(MatchError(failure))
+			Expect(cursor.Closed).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,36 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+)
+
+// SliceCursor iterates over Entries and then reports Failure, if set.
+type SliceCursor struct {
+	Entries []*logger.LogEntry
+	Failure error
+	Closed  bool
+	next    int
+}
+
+func (c *SliceCursor) Next(ctx context.Context) bool {
+	if c.next >= len(c.Entries) {
+		return false
+	}
+	c.next++
+	return true
+}
+
+func (c *SliceCursor) Entry() *logger.LogEntry {
+	return c.Entries[c.next-1]
+}
+
+func (c *SliceCursor) Err() error {
+	return c.Failure
+}
+
+func (c *SliceCursor) Close(ctx context.Context) error {
+	c.Closed = true
+	return nil
+}
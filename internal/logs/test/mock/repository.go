@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetLogs(filter *logger.Filter, page, size uint) ([]*logger.LogEntry, uint, uint, error) {
+	args := m.Called(filter, page, size)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
+	}
+	return args.Get(0).([]*logger.LogEntry), args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
+}
+
+func (m *MockRepository) GetLogsPage(
+	filter *logger.Filter,
+	cursor *logger.PageCursor,
+	size uint,
+) (*logger.LogPage, error) {
+	args := m.Called(filter, cursor, size)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*logger.LogPage), args.Error(1)
+}
+
+func (m *MockRepository) CountLogs(filter *logger.Filter) (uint, error) {
+	args := m.Called(filter)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockRepository) OpenLogs(ctx context.Context, filter *logger.Filter) (logs.LogCursor, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(logs.LogCursor), args.Error(1)
+}
+
+func (m *MockRepository) VerifyChain(ctx context.Context) (*logger.ChainVerification, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*logger.ChainVerification), args.Error(1)
+}
@@ -0,0 +1,214 @@
+package logs_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	mockLogs "github.com/ArshiAbolghasemi/dom-cobb/internal/logs/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/testutils"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var newestFirstCursor = &logger.PageCursor{
+	Timestamp: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	ID:        primitive.NewObjectID(),
+}
+
+var _ = Describe("Service", func() {
+	Describe("Validate Get Logs Request", func() {
+		It("should bind every filter", func() {
+			service := &logs.Service{Repo: &mockLogs.MockRepository{}}
+
+			c, _ := testutils.CreateJSONRequest(http.MethodGet,
+				"/api/v1/logs?page=1&size=10&flag_id=1&flag_id=2&from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z"+
+					"&action=toggled&actor=alice&reason=incident&cascade=true&sort=timestamp", nil)
+
+			query, err := service.ValidateGetLogsRequest(c)
+
+			Expect(err).To(BeNil())
+			Expect(query.FlagIds).To(Equal([]uint{1, 2}))
+			Expect(query.From).To(BeTemporally("==", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(query.To).To(BeTemporally("==", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(query.Action).To(Equal("toggled"))
+			Expect(query.Actor).To(Equal("alice"))
+			Expect(query.Reason).To(Equal("incident"))
+			Expect(query.Cascade).To(Equal("true"))
+			Expect(query.Sort).To(Equal("timestamp"))
+		})
+
+		DescribeTable("should reject invalid filters",
+			func(url string) {
+				service := &logs.Service{Repo: &mockLogs.MockRepository{}}
+
+				c, _ := testutils.CreateJSONRequest(http.MethodGet, url, nil)
+
+				query, err := service.ValidateGetLogsRequest(c)
+
+				Expect(query).To(BeNil())
+				Expect(err).NotTo(BeNil())
+				Expect(err.StatusCode).To(Equal(http.StatusBadRequest))
+			},
+			Entry("unknown action", "/api/v1/logs?page=1&size=10&action=deleted"),
+			Entry("non boolean cascade", "/api/v1/logs?page=1&size=10&cascade=maybe"),
+			Entry("unknown sort", "/api/v1/logs?page=1&size=10&sort=message"),
+			Entry("malformed time", "/api/v1/logs?page=1&size=10&from=yesterday"),
+			Entry("from after to", "/api/v1/logs?page=1&size=10&from=2026-02-01T00:00:00Z&to=2026-01-01T00:00:00Z"),
+			Entry("empty time range", "/api/v1/logs?page=1&size=10&from=2026-01-01T00:00:00Z&to=2026-01-01T00:00:00Z"),
+			Entry("malformed cursor", "/api/v1/logs?size=10&cursor=not-a-cursor"),
+			Entry("page and cursor", "/api/v1/logs?page=1&size=10&cursor="+newestFirstCursor.Encode()),
+			Entry("cursor of the other order", "/api/v1/logs?size=10&sort=timestamp&cursor="+newestFirstCursor.Encode()),
+		)
+
+		It("should page by cursor when page is left out", func() {
+			service := &logs.Service{Repo: &mockLogs.MockRepository{}}
+
+			c, _ := testutils.CreateJSONRequest(http.MethodGet,
+				"/api/v1/logs?size=10&total=true&cursor="+newestFirstCursor.Encode(), nil)
+
+			query, err := service.ValidateGetLogsRequest(c)
+
+			Expect(err).To(BeNil())
+			Expect(query.Page).To(BeZero())
+			Expect(query.Total).To(BeTrue())
+			Expect(query.PageCursor()).To(Equal(newestFirstCursor))
+		})
+	})
+
+	Describe("Get Logs", func() {
+		It("should pass the filter and pagination to the repository", func() {
+			repo := &mockLogs.MockRepository{}
+			service := &logs.Service{Repo: repo}
+			cascade := false
+			entries := []*logger.LogEntry{{Message: "Feature Flag is toggled successfully"}}
+			repo.On("GetLogs", &logger.Filter{
+				FlagIds:   []uint{3},
+				Action:    logger.ActionToggled,
+				Cascade:   &cascade,
+				Ascending: true,
+			}, uint(2), uint(5)).Return(entries, uint(6), uint(2), nil)
+
+			query := &logs.GetLogsQueryParams{
+				PageQueryParams: logs.PageQueryParams{Page: 2, Size: 5},
+				FilterQueryParams: logs.FilterQueryParams{
+					Action:  "toggled",
+					Cascade: "false",
+					Sort:    "timestamp",
+				},
+				FlagIds: []uint{3},
+			}
+
+			data, err := service.GetLogs(query)
+
+			Expect(err).To(BeNil())
+			Expect(data.Logs).To(Equal(entries))
+			Expect(data.Page).To(Equal(uint(2)))
+			Expect(data.Size).To(Equal(uint(5)))
+			Expect(data.Total).To(Equal(uint(6)))
+			Expect(data.TotalPages).To(Equal(uint(2)))
+
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should return api error with status code 500", func() {
+			repo := &mockLogs.MockRepository{}
+			service := &logs.Service{Repo: repo}
+			err := errors.New("connection refused")
+			repo.On("GetLogs", &logger.Filter{}, uint(1), uint(10)).Return(nil, uint(0), uint(0), err)
+
+			query := &logs.GetLogsQueryParams{
+				PageQueryParams: logs.PageQueryParams{Page: 1, Size: 10},
+			}
+
+			data, result := service.GetLogs(query)
+
+			Expect(data).To(BeNil())
+			Expect(result).To(Equal(api.InternalServerError("Internal Server Error", err.Error())))
+
+			repo.AssertExpectations(GinkgoT())
+		})
+	})
+
+	Describe("Get Logs By Cursor", func() {
+		It("should return the cursors of the pages around the page", func() {
+			repo := &mockLogs.MockRepository{}
+			service := &logs.Service{Repo: repo}
+			entries := []*logger.LogEntry{{Message: "Feature Flag is toggled successfully"}}
+			next := &logger.PageCursor{Timestamp: newestFirstCursor.Timestamp.Add(-time.Minute), ID: primitive.NewObjectID()}
+			repo.On("GetLogsPage", &logger.Filter{}, newestFirstCursor, uint(5)).Return(&logger.LogPage{
+				Logs: entries,
+				Next: next,
+			}, nil)
+
+			data, err := service.GetLogs(&logs.GetLogsQueryParams{
+				PageQueryParams: logs.PageQueryParams{Size: 5, Cursor: newestFirstCursor.Encode()},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(data.Logs).To(Equal(entries))
+			Expect(data.Next).To(Equal(next.Encode()))
+			Expect(data.Prev).To(BeEmpty())
+			Expect(data.Size).To(Equal(uint(5)))
+			Expect(data.Total).To(BeZero())
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should count the entries only when asked to", func() {
+			repo := &mockLogs.MockRepository{}
+			service := &logs.Service{Repo: repo}
+			filter := &logger.Filter{Actor: "alice"}
+			repo.On("GetLogsPage", filter, (*logger.PageCursor)(nil), uint(5)).Return(&logger.LogPage{
+				Logs: []*logger.LogEntry{},
+			}, nil)
+			repo.On("CountLogs", filter).Return(uint(12), nil)
+
+			data, err := service.GetLogs(&logs.GetLogsQueryParams{
+				PageQueryParams:   logs.PageQueryParams{Size: 5, Total: true},
+				FilterQueryParams: logs.FilterQueryParams{Actor: "alice"},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(data.Total).To(Equal(uint(12)))
+			Expect(data.TotalPages).To(Equal(uint(3)))
+			repo.AssertExpectations(GinkgoT())
+		})
+	})
+
+	Describe("Verify Chain", func() {
+		It("should return the verification of the repository", func() {
+			repo := &mockLogs.MockRepository{}
+			service := &logs.Service{Repo: repo}
+			verification := &logger.ChainVerification{
+				Break: &logger.ChainBreak{Sequence: 7, Reason: "content does not match its hash"},
+			}
+			repo.On("VerifyChain", context.Background()).Return(verification, nil)
+
+			data, err := service.VerifyChain(context.Background())
+
+			Expect(err).To(BeNil())
+			Expect(data).To(Equal(verification))
+
+			repo.AssertExpectations(GinkgoT())
+		})
+
+		It("should return api error with status code 500", func() {
+			repo := &mockLogs.MockRepository{}
+			service := &logs.Service{Repo: repo}
+			err := errors.New("connection refused")
+			repo.On("VerifyChain", context.Background()).Return(nil, err)
+
+			data, result := service.VerifyChain(context.Background())
+
+			Expect(data).To(BeNil())
+			Expect(result).To(Equal(api.InternalServerError("Internal Server Error", err.Error())))
+
+			repo.AssertExpectations(GinkgoT())
+		})
+	})
+})
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get metrics
+// @Description Expose process metrics in the Prometheus text format
+// @Tags metrics
+// @Produce plain
+// @Success 200 {string} string "Metrics"
+// @Router /metrics [get]
+func MetricsAPI(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4")
+	c.Status(http.StatusOK)
+	Write(c.Writer)
+}
@@ -0,0 +1,9 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	router.GET("/metrics", MetricsAPI)
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+type gauge struct {
+	help  string
+	value func() float64
+}
+
+var (
+	mu     sync.RWMutex
+	gauges = map[string]*gauge{}
+)
+
+func RegisterGauge(name, help string, value func() float64) {
+	mu.Lock()
+	defer mu.Unlock()
+	gauges[name] = &gauge{help: help, value: value}
+}
+
+func Write(w io.Writer) error {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(gauges))
+	for name := range gauges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g := gauges[name]
+		_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, g.help, name, name, g.value())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
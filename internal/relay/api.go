@@ -0,0 +1,159 @@
+package relay
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a feature flag from the relay
+// @Description Retrieve a feature flag by its ID including its dependencies and dependents, from the relay's local configuration
+// @Tags relay
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Success 200 {object} api.SuccessResponse{data=flags.FeatureFlagData} "Feature flag retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Router /api/v1/flags/{id} [get]
+func GetFeatureFlagAPI(c *gin.Context) {
+	service := GetService()
+
+	flag, err := service.ValidateGetFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag is retrieved successfully", service.GetFeatureFlag(flag))
+}
+
+// @Summary Get feature flag targets from the relay
+// @Description Retrieve the targeting keys that always receive the on (allow) or off (deny) variant of a feature flag
+// @Tags relay
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Success 200 {object} api.SuccessResponse{data=flags.FeatureFlagTargetsData} "Feature flag targets retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Router /api/v1/flags/{id}/targets [get]
+func GetFeatureFlagTargetsAPI(c *gin.Context) {
+	service := GetService()
+
+	flag, err := service.ValidateGetFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag targets are retrieved successfully", service.GetFeatureFlagTargets(flag))
+}
+
+// @Summary Evaluate a feature flag on the relay
+// @Description Evaluate a feature flag for the given context against the relay's local configuration
+// @Tags relay
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param request body flags.EvaluateFeatureFlagRequest true "Evaluation context"
+// @Success 200 {object} api.SuccessResponse{data=flags.EvaluateFeatureFlagData} "Feature flag evaluated successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Router /api/v1/flags/{id}/evaluate [post]
+func EvaluateFeatureFlagAPI(c *gin.Context) {
+	service := GetService()
+
+	flag, req, err := service.ValidateEvaluateFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag is evaluated successfully", service.EvaluateFeatureFlag(flag, req))
+}
+
+// @Summary Explain a feature flag evaluation on the relay
+// @Description Evaluate a feature flag for the given context against the relay's local configuration and return the trace
+// @Tags relay
+// @Accept json
+// @Produce json
+// @Param id path int true "Feature Flag ID"
+// @Param request body flags.EvaluateFeatureFlagRequest true "Evaluation context"
+// @Success 200 {object} api.SuccessResponse{data=flags.ExplainFeatureFlagData} "Feature flag evaluation explained successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Router /api/v1/flags/{id}/explain [post]
+func ExplainFeatureFlagAPI(c *gin.Context) {
+	service := GetService()
+
+	flag, req, err := service.ValidateEvaluateFeatureFlagRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag evaluation is explained successfully", service.ExplainFeatureFlag(flag, req))
+}
+
+// @Summary Evaluate all feature flags on the relay
+// @Description Evaluate every feature flag for the given context against the relay's local configuration
+// @Tags relay
+// @Accept json
+// @Produce json
+// @Param request body flags.EvaluateAllFeatureFlagsRequest true "Evaluation context"
+// @Success 200 {object} api.SuccessResponse{data=flags.EvaluateAllFeatureFlagsData} "Feature flags evaluated successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Router /api/v1/evaluate/all [post]
+func EvaluateAllFeatureFlagsAPI(c *gin.Context) {
+	service := GetService()
+
+	req, err := service.ValidateEvaluateAllFeatureFlagsRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flags are evaluated successfully", service.EvaluateAllFeatureFlags(req))
+}
+
+// @Summary Get feature flags configuration from the relay
+// @Description Retrieve the relay's local copy of the full evaluation configuration. Responds 304 when If-None-Match matches the ETag.
+// @Tags relay
+// @Accept json
+// @Produce json
+// @Param If-None-Match header string false "ETag of the configuration the client already has"
+// @Success 200 {object} api.SuccessResponse{data=evaluation.Config} "Feature flags configuration retrieved successfully"
+// @Success 304 "Feature flags configuration is not modified"
+// @Router /api/v1/config [get]
+func GetFeatureFlagsConfigAPI(c *gin.Context) {
+	data, etag := GetService().GetFeatureFlagsConfig()
+
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flags configuration is retrieved successfully", data)
+}
+
+// @Description Relay synchronization status
+type StatusData struct {
+	Flags        int        `json:"flags"`
+	SyncedAt     *time.Time `json:"synced_at"`
+	SyncFailures uint64     `json:"sync_failures"`
+	SyncError    string     `json:"sync_error,omitempty"`
+}
+
+// @Summary Get relay status
+// @Description Report how many flags the relay serves, when it last synced them from the upstream server or snapshot file, and its failed syncs
+// @Tags relay
+// @Produce json
+// @Success 200 {object} api.SuccessResponse{data=StatusData} "Relay status retrieved successfully"
+// @Router /api/v1/relay/status [get]
+func GetStatusAPI(c *gin.Context) {
+	api.RespondSuccess(c, http.StatusOK, "Relay status is retrieved successfully", GetService().GetStatus())
+}
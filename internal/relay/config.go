@@ -0,0 +1,50 @@
+package relay
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+func GetPort() (string, error) {
+	port, exists := os.LookupEnv("RELAY_PORT")
+	if !exists {
+		return "", fmt.Errorf("Relay port is undefined")
+	}
+	return port, nil
+}
+
+func GetUpstreamURL() (string, error) {
+	upstreamURL, exists := os.LookupEnv("RELAY_UPSTREAM_URL")
+	if !exists {
+		return "", fmt.Errorf("Relay upstream url is undefined")
+	}
+	return upstreamURL, nil
+}
+
+func GetPollInterval() (time.Duration, error) {
+	intervalStr, exists := os.LookupEnv("RELAY_POLL_INTERVAL")
+	if !exists {
+		return -1, fmt.Errorf("Relay poll interval is undefined")
+	}
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil {
+		return -1, err
+	}
+	return time.Duration(interval), nil
+}
+
+func IsStreamingEnabled() (bool, error) {
+	enabledStr, exists := os.LookupEnv("RELAY_STREAMING")
+	if !exists {
+		return false, nil
+	}
+	return strconv.ParseBool(enabledStr)
+}
+
+// GetSnapshotFile returns the path the relay starts from and keeps up to
+// date, or an empty string when no snapshot file is configured.
+func GetSnapshotFile() string {
+	return os.Getenv("RELAY_SNAPSHOT_FILE")
+}
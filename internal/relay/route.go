@@ -0,0 +1,18 @@
+package relay
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	{
+		v1 := router.Group("/api/v1")
+		v1.GET("/flags/:id", GetFeatureFlagAPI)
+		v1.GET("/flags/:id/targets", GetFeatureFlagTargetsAPI)
+		v1.POST("/flags/:id/evaluate", EvaluateFeatureFlagAPI)
+		v1.POST("/flags/:id/explain", ExplainFeatureFlagAPI)
+		v1.POST("/evaluate/all", EvaluateAllFeatureFlagsAPI)
+		v1.GET("/config", GetFeatureFlagsConfigAPI)
+		v1.GET("/relay/status", GetStatusAPI)
+	}
+}
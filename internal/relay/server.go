@@ -0,0 +1,78 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/client"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	"github.com/gin-gonic/gin"
+	"github.com/joho/godotenv"
+)
+
+func Run() {
+	// The relay is usually configured through the environment alone.
+	_ = godotenv.Load()
+
+	upstreamURL, err := GetUpstreamURL()
+	if err != nil {
+		panic(err)
+	}
+	pollInterval, err := GetPollInterval()
+	if err != nil {
+		panic("Failed to get relay poll interval: " + err.Error())
+	}
+	streaming, err := IsStreamingEnabled()
+	if err != nil {
+		panic("Failed to get relay streaming config: " + err.Error())
+	}
+	port, err := GetPort()
+	if err != nil {
+		panic(err)
+	}
+
+	service := GetService()
+	snapshotFile := GetSnapshotFile()
+
+	opts := []client.Option{
+		client.WithPollInterval(pollInterval * time.Second),
+		client.WithChangeHandler(func(config *evaluation.Config) {
+			// Failures are reported by the status endpoint.
+			_ = service.Sync(config, snapshotFile)
+		}),
+	}
+	if streaming {
+		opts = append(opts, client.WithStreaming())
+	}
+	if snapshotFile != "" {
+		config, err := ReadSnapshotFile(snapshotFile)
+		switch {
+		case err == nil:
+			var syncedAt time.Time
+			if info, err := os.Stat(snapshotFile); err == nil {
+				syncedAt = info.ModTime()
+			}
+			if err := service.Update(config, syncedAt); err != nil {
+				panic("Failed to load relay snapshot file: " + err.Error())
+			}
+			opts = append(opts, client.WithInitialConfig(config))
+		case !errors.Is(err, fs.ErrNotExist):
+			panic("Failed to read relay snapshot file: " + err.Error())
+		}
+	}
+
+	// Without a snapshot file this fails until the upstream server is
+	// reachable once; with one the relay starts serving it right away.
+	c, err := client.New(context.Background(), upstreamURL, opts...)
+	if err != nil {
+		panic("Failed to sync feature flags configuration: " + err.Error())
+	}
+	defer c.Close()
+
+	r := gin.Default()
+	SetupRoutes(r)
+	r.Run(":" + port)
+}
@@ -0,0 +1,222 @@
+package relay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	"github.com/gin-gonic/gin"
+)
+
+type Service struct {
+	state        atomic.Pointer[state]
+	syncFailures atomic.Uint64
+	syncError    atomic.Pointer[string]
+}
+
+type state struct {
+	config     *evaluation.Config
+	etag       string
+	syncedAt   time.Time
+	evaluator  *evaluation.Evaluator
+	flags      map[uint]*evaluation.Flag
+	dependents map[uint][]uint
+}
+
+var (
+	service     *Service
+	onceService sync.Once
+)
+
+func GetService() *Service {
+	onceService.Do(func() {
+		service = NewService()
+	})
+	return service
+}
+
+func NewService() *Service {
+	s := &Service{}
+	s.Update(&evaluation.Config{Flags: []*evaluation.Flag{}}, time.Time{})
+	return s
+}
+
+func (s *Service) Update(config *evaluation.Config, syncedAt time.Time) error {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(body)
+
+	next := &state{
+		config:     config,
+		etag:       fmt.Sprintf("%q", hex.EncodeToString(hash[:])),
+		syncedAt:   syncedAt,
+		evaluator:  evaluation.NewEvaluator(config.Flags),
+		flags:      make(map[uint]*evaluation.Flag, len(config.Flags)),
+		dependents: make(map[uint][]uint),
+	}
+	for _, flag := range config.Flags {
+		next.flags[flag.ID] = flag
+		for _, dependencyID := range flag.Dependencies {
+			next.dependents[dependencyID] = append(next.dependents[dependencyID], flag.ID)
+		}
+	}
+
+	s.state.Store(next)
+	return nil
+}
+
+// Sync serves config synced from the upstream server and, when
+// snapshotFile is set, writes it there. The client syncs in the
+// background, so failures are also kept for the status endpoint until the
+// next sync succeeds.
+func (s *Service) Sync(config *evaluation.Config, snapshotFile string) error {
+	err := s.Update(config, time.Now())
+	if err == nil && snapshotFile != "" {
+		err = WriteSnapshotFile(snapshotFile, config)
+	}
+
+	if err != nil {
+		message := err.Error()
+		s.syncFailures.Add(1)
+		s.syncError.Store(&message)
+		return err
+	}
+	s.syncError.Store(nil)
+	return nil
+}
+
+func (s *Service) ValidateGetFeatureFlagRequest(c *gin.Context) (*evaluation.Flag, *api.APIError) {
+	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	flag, ok := s.state.Load().flags[uint(flagId)]
+	if !ok {
+		return nil, api.NotFoundError("Invalid flag id", "")
+	}
+
+	return flag, nil
+}
+
+func (s *Service) GetFeatureFlag(flag *evaluation.Flag) *flags.FeatureFlagData {
+	dependents := s.state.Load().dependents[flag.ID]
+	if dependents == nil {
+		dependents = []uint{}
+	}
+
+	return &flags.FeatureFlagData{
+		ID:           flag.ID,
+		Name:         flag.Name,
+		Active:       flag.Active,
+		Dependencies: flag.Dependencies,
+		Dependents:   dependents,
+	}
+}
+
+func (s *Service) GetFeatureFlagTargets(flag *evaluation.Flag) *flags.FeatureFlagTargetsData {
+	data := &flags.FeatureFlagTargetsData{
+		Allow: append([]string{}, flag.Allow...),
+		Deny:  append([]string{}, flag.Deny...),
+	}
+	return data
+}
+
+func (s *Service) ValidateEvaluateFeatureFlagRequest(
+	c *gin.Context,
+) (
+	*evaluation.Flag,
+	*flags.EvaluateFeatureFlagRequest,
+	*api.APIError,
+) {
+	flagId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	var req flags.EvaluateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+	flag, ok := s.state.Load().flags[uint(flagId)]
+	if !ok {
+		return nil, nil, api.NotFoundError("Invalid flag id", "")
+	}
+
+	return flag, &req, nil
+}
+
+func (s *Service) EvaluateFeatureFlag(flag *evaluation.Flag, req *flags.EvaluateFeatureFlagRequest) *flags.EvaluateFeatureFlagData {
+	result := s.state.Load().evaluator.Evaluate(flag.ID, req.Context)
+
+	return &flags.EvaluateFeatureFlagData{
+		ID:     flag.ID,
+		Name:   flag.Name,
+		Result: *result,
+	}
+}
+
+func (s *Service) ExplainFeatureFlag(flag *evaluation.Flag, req *flags.EvaluateFeatureFlagRequest) *flags.ExplainFeatureFlagData {
+	result, trace := s.state.Load().evaluator.Explain(flag.ID, req.Context)
+
+	return &flags.ExplainFeatureFlagData{
+		EvaluateFeatureFlagData: flags.EvaluateFeatureFlagData{
+			ID:     flag.ID,
+			Name:   flag.Name,
+			Result: *result,
+		},
+		Trace: trace,
+	}
+}
+
+func (s *Service) ValidateEvaluateAllFeatureFlagsRequest(c *gin.Context) (*flags.EvaluateAllFeatureFlagsRequest, *api.APIError) {
+	var req flags.EvaluateAllFeatureFlagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	return &req, nil
+}
+
+func (s *Service) EvaluateAllFeatureFlags(req *flags.EvaluateAllFeatureFlagsRequest) *flags.EvaluateAllFeatureFlagsData {
+	st := s.state.Load()
+	results := st.evaluator.EvaluateAll(req.Context)
+
+	data := &flags.EvaluateAllFeatureFlagsData{
+		Flags: make(map[string]*evaluation.Result, len(results)),
+	}
+	for _, flag := range st.config.Flags {
+		data.Flags[flag.Name] = results[flag.ID]
+	}
+	return data
+}
+
+func (s *Service) GetFeatureFlagsConfig() (*evaluation.Config, string) {
+	st := s.state.Load()
+	return st.config, st.etag
+}
+
+func (s *Service) GetStatus() *StatusData {
+	st := s.state.Load()
+	data := &StatusData{
+		Flags:        len(st.config.Flags),
+		SyncFailures: s.syncFailures.Load(),
+	}
+	if syncError := s.syncError.Load(); syncError != nil {
+		data.SyncError = *syncError
+	}
+	if !st.syncedAt.IsZero() {
+		syncedAt := st.syncedAt
+		data.SyncedAt = &syncedAt
+	}
+	return data
+}
@@ -0,0 +1,46 @@
+package relay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+func ReadSnapshotFile(path string) (*evaluation.Config, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config evaluation.Config
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// WriteSnapshotFile replaces the file atomically so a relay restarting
+// mid-write never reads a truncated snapshot.
+func WriteSnapshotFile(path string, config *evaluation.Config) error {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
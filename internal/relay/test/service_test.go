@@ -0,0 +1,155 @@
+package relay_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/relay"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func createConfig() *evaluation.Config {
+	return &evaluation.Config{
+		Flags: []*evaluation.Flag{
+			{ID: 1, Name: "checkout", Active: true, Dependencies: []uint{}, Deny: []string{"user-2"}},
+			{ID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}},
+			{ID: 3, Name: "dark-mode", Active: false, Dependencies: []uint{}},
+		},
+	}
+}
+
+func request(router *gin.Engine, method, url, body string, data any) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if data != nil && w.Code == http.StatusOK {
+		Expect(json.Unmarshal(w.Body.Bytes(), &struct {
+			Data any `json:"data"`
+		}{Data: data})).To(Succeed())
+	}
+	return w
+}
+
+var _ = Describe("Relay", func() {
+	var router *gin.Engine
+
+	BeforeEach(func() {
+		gin.SetMode(gin.TestMode)
+		router = gin.New()
+		relay.SetupRoutes(router)
+		Expect(relay.GetService().Update(createConfig(), time.Now())).To(Succeed())
+	})
+
+	Describe("Get feature flag", func() {
+		It("should return the flag with its dependencies and dependents", func() {
+			var data flags.FeatureFlagData
+			w := request(router, http.MethodGet, "/api/v1/flags/1", "", &data)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(data.Name).To(Equal("checkout"))
+			Expect(data.Dependencies).To(BeEmpty())
+			Expect(data.Dependents).To(Equal([]uint{2}))
+		})
+
+		It("should return not found for unknown flags", func() {
+			w := request(router, http.MethodGet, "/api/v1/flags/42", "", nil)
+
+			Expect(w.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("Evaluate", func() {
+		It("should evaluate a flag against the local configuration", func() {
+			var data flags.EvaluateFeatureFlagData
+			w := request(router, http.MethodPost, "/api/v1/flags/2/evaluate", `{"context":{"targeting_key":"user-2"}}`, &data)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(data.Value).To(BeFalse())
+			Expect(data.Reason).To(Equal(evaluation.ReasonPrerequisiteFailed))
+		})
+
+		It("should evaluate every flag keyed by name", func() {
+			var data flags.EvaluateAllFeatureFlagsData
+			w := request(router, http.MethodPost, "/api/v1/evaluate/all", `{"context":{"targeting_key":"user-1"}}`, &data)
+
+			Expect(w.Code).To(Equal(http.StatusOK))
+			Expect(data.Flags).To(HaveLen(3))
+			Expect(data.Flags["new-cart"].Value).To(BeTrue())
+			Expect(data.Flags["dark-mode"].Reason).To(Equal(evaluation.ReasonOff))
+		})
+	})
+
+	Describe("Config", func() {
+		It("should respond not modified when the ETag matches", func() {
+			w := request(router, http.MethodGet, "/api/v1/config", "", nil)
+			Expect(w.Code).To(Equal(http.StatusOK))
+			etag := w.Header().Get("ETag")
+			Expect(etag).NotTo(BeEmpty())
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/config", nil)
+			req.Header.Set("If-None-Match", etag)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			Expect(w.Code).To(Equal(http.StatusNotModified))
+		})
+	})
+
+	Describe("Snapshot file", func() {
+		It("should read back the configuration it wrote", func() {
+			path := filepath.Join(GinkgoT().TempDir(), "snapshot.json")
+
+			Expect(relay.WriteSnapshotFile(path, createConfig())).To(Succeed())
+			config, err := relay.ReadSnapshotFile(path)
+
+			Expect(err).To(BeNil())
+			Expect(config).To(Equal(createConfig()))
+		})
+	})
+
+	Describe("Sync", func() {
+		It("should serve the configuration and write the snapshot file", func() {
+			service := relay.NewService()
+			path := filepath.Join(GinkgoT().TempDir(), "snapshot.json")
+
+			Expect(service.Sync(createConfig(), path)).To(Succeed())
+
+			config, err := relay.ReadSnapshotFile(path)
+			Expect(err).To(BeNil())
+			Expect(config).To(Equal(createConfig()))
+			status := service.GetStatus()
+			Expect(status.Flags).To(Equal(3))
+			Expect(status.SyncedAt).NotTo(BeNil())
+			Expect(status.SyncFailures).To(BeZero())
+			Expect(status.SyncError).To(BeEmpty())
+		})
+
+		It("should report failed snapshot file writes until a sync succeeds", func() {
+			service := relay.NewService()
+			dir := GinkgoT().TempDir()
+
+			Expect(service.Sync(createConfig(), filepath.Join(dir, "missing", "snapshot.json"))).NotTo(Succeed())
+
+			status := service.GetStatus()
+			Expect(status.Flags).To(Equal(3))
+			Expect(status.SyncFailures).To(Equal(uint64(1)))
+			Expect(status.SyncError).NotTo(BeEmpty())
+
+			Expect(service.Sync(createConfig(), filepath.Join(dir, "snapshot.json"))).To(Succeed())
+
+			status = service.GetStatus()
+			Expect(status.SyncFailures).To(Equal(uint64(1)))
+			Expect(status.SyncError).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,14 @@
+package rpc
+
+import (
+	"fmt"
+	"os"
+)
+
+func GetPort() (string, error) {
+	port, exists := os.LookupEnv("GRPC_PORT")
+	if !exists {
+		return "", fmt.Errorf("gRPC port is undefined")
+	}
+	return port, nil
+}
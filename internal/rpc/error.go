@@ -0,0 +1,36 @@
+package rpc
+
+import (
+	"net/http"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func toStatusCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	default:
+		return codes.Internal
+	}
+}
+
+// toStatus converts an api.APIError into a gRPC status error, keeping the
+// REST error code as the status message and its detail after a colon.
+func toStatus(apiErr *api.APIError) error {
+	message := apiErr.Error
+	if apiErr.Message != "" {
+		message += ": " + apiErr.Message
+	}
+	return status.Error(toStatusCode(apiErr.StatusCode), message)
+}
+
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, "Invalid input format: "+err.Error())
+}
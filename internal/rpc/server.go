@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	domcobbv1 "github.com/ArshiAbolghasemi/dom-cobb/pkg/proto/domcobb/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer returns a gRPC server exposing the flag service, the standard
+// health service and server reflection.
+func NewServer(service *flags.Service) *grpc.Server {
+	server := grpc.NewServer(grpc.UnaryInterceptor(auditInterceptor))
+	domcobbv1.RegisterFlagServiceServer(server, NewFlagServer(service))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(domcobbv1.FlagService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server
+}
+
+// auditedMethods are the calls that write audit entries. Only they reject
+// an actor longer than log filters accept, so reads keep working.
+var auditedMethods = map[string]bool{
+	domcobbv1.FlagService_CreateFlag_FullMethodName:             true,
+	domcobbv1.FlagService_ToggleFlag_FullMethodName:             true,
+	domcobbv1.FlagService_UpdateFlagDependencies_FullMethodName: true,
+}
+
+// auditInterceptor stores the request id and actor of a call in its
+// context for audit log entries. The request id comes from x-request-id,
+// generated when the client sent none, and the actor from the trusted actor
+// header when configured, else AnonymousActor.
+func auditInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := firstMetadataValue(md, api.RequestIDHeader)
+	if requestID == "" || len(requestID) > 128 {
+		requestID = logger.NewRequestID()
+	}
+
+	actor := ""
+	if header := api.GetTrustedActorHeader(); header != "" {
+		actor = strings.TrimSpace(firstMetadataValue(md, header))
+	}
+	if actor == "" {
+		actor = logger.AnonymousActor
+	}
+	if auditedMethods[info.FullMethod] && len(actor) > logger.MaxActorLength {
+		return nil, status.Errorf(codes.InvalidArgument, "actor must be at most %d characters", logger.MaxActorLength)
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(api.RequestIDHeader, requestID))
+	ctx = logger.WithRequestID(ctx, requestID)
+	ctx = logger.WithActor(ctx, actor)
+	return handler(ctx, req)
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func Run(service *flags.Service) {
+	port, err := GetPort()
+	if err != nil {
+		panic(err)
+	}
+
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		panic("Failed to listen on gRPC port: " + err.Error())
+	}
+
+	if err := NewServer(service).Serve(listener); err != nil {
+		panic("Failed to serve gRPC: " + err.Error())
+	}
+}
@@ -0,0 +1,458 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logs"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	domcobbv1 "github.com/ArshiAbolghasemi/dom-cobb/pkg/proto/domcobb/v1"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultPage = 1
+	defaultSize = 10
+)
+
+type FlagServer struct {
+	domcobbv1.UnimplementedFlagServiceServer
+	Service *flags.Service
+}
+
+func NewFlagServer(service *flags.Service) *FlagServer {
+	return &FlagServer{Service: service}
+}
+
+func (s *FlagServer) CreateFlag(
+	ctx context.Context,
+	req *domcobbv1.CreateFlagRequest,
+) (
+	*domcobbv1.CreateFlagResponse,
+	error,
+) {
+	createReq := &flags.CreateFeatureFlagRequest{
+		Name:                      req.GetName(),
+		IsActive:                  req.GetActive(),
+		FeatureFlagIDDependencies: toUintSlice(req.GetDependencies()),
+	}
+	if err := binding.Validator.ValidateStruct(createReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if apiErr := s.Service.ValidateCreateFeatureFlag(createReq); apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	flag, apiErr := s.Service.CreateFeatureFlag(ctx, createReq)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	data, apiErr := s.Service.GetFeatureFlag(flag)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	return &domcobbv1.CreateFlagResponse{Flag: toFlag(data)}, nil
+}
+
+func (s *FlagServer) GetFlag(
+	ctx context.Context,
+	req *domcobbv1.GetFlagRequest,
+) (
+	*domcobbv1.GetFlagResponse,
+	error,
+) {
+	data, err := s.getFlag(uint(req.GetId()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &domcobbv1.GetFlagResponse{Flag: data}, nil
+}
+
+func (s *FlagServer) ListFlags(
+	ctx context.Context,
+	req *domcobbv1.ListFlagsRequest,
+) (
+	*domcobbv1.ListFlagsResponse,
+	error,
+) {
+	query := &flags.ListFeatureFlagsQueryParams{
+		PaginationQueryParam: toPaginationQueryParam(req.GetPage(), req.GetSize()),
+	}
+	if err := binding.Validator.ValidateStruct(query); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	data, apiErr := s.Service.ListFeatureFlags(query)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	res := &domcobbv1.ListFlagsResponse{
+		Flags:      make([]*domcobbv1.Flag, 0, len(data.Flags)),
+		Pagination: toPagination(&data.PaginationResponse),
+	}
+	for _, flag := range data.Flags {
+		res.Flags = append(res.Flags, toFlag(flag))
+	}
+
+	return res, nil
+}
+
+func (s *FlagServer) ToggleFlag(
+	ctx context.Context,
+	req *domcobbv1.ToggleFlagRequest,
+) (
+	*domcobbv1.ToggleFlagResponse,
+	error,
+) {
+	updateReq := &flags.UpdateFeatureFlagRequest{
+		IsActive: req.GetActive(),
+		Reason:   req.GetReason(),
+	}
+	if err := binding.Validator.ValidateStruct(updateReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	flag, apiErr := s.Service.ValidateUpdateFeatureFlag(uint(req.GetId()), updateReq)
+	// The REST API answers 200 when the flag already has the requested
+	// state; gRPC reports it as a successful no-op with a message.
+	if apiErr != nil && apiErr.StatusCode == http.StatusOK {
+		data, err := s.getFlag(uint(req.GetId()))
+		if err != nil {
+			return nil, err
+		}
+		return &domcobbv1.ToggleFlagResponse{Flag: data, Message: apiErr.Error}, nil
+	}
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	if apiErr := s.Service.UpdateFeatureFlag(ctx, flag, updateReq); apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	data, err := s.getFlag(flag.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domcobbv1.ToggleFlagResponse{Flag: data}, nil
+}
+
+func (s *FlagServer) UpdateFlagDependencies(
+	ctx context.Context,
+	req *domcobbv1.UpdateFlagDependenciesRequest,
+) (
+	*domcobbv1.UpdateFlagDependenciesResponse,
+	error,
+) {
+	updateReq := &flags.UpdateFeatureFlagDependenciesRequest{
+		Dependencies: toUintSlice(req.GetDependencies()),
+		Reason:       req.GetReason(),
+	}
+	if err := binding.Validator.ValidateStruct(updateReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	flag, apiErr := s.Service.ValidateUpdateFeatureFlagDependencies(uint(req.GetId()), updateReq)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	if apiErr := s.Service.UpdateFeatureFlagDependencies(ctx, flag, updateReq); apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	data, err := s.getFlag(flag.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domcobbv1.UpdateFlagDependenciesResponse{Flag: data}, nil
+}
+
+func (s *FlagServer) GetFlagLogs(
+	ctx context.Context,
+	req *domcobbv1.GetFlagLogsRequest,
+) (
+	*domcobbv1.GetFlagLogsResponse,
+	error,
+) {
+	pagination := toPaginationQueryParam(req.GetPage(), req.GetSize())
+	query := &flags.GetFeatureFlagLogsQueryParams{
+		PageQueryParams: logs.PageQueryParams{Page: pagination.Page, Size: pagination.Size},
+	}
+	if err := binding.Validator.ValidateStruct(query); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	flag, apiErr := s.Service.GetFeatureFlagById(uint(req.GetId()))
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	data, apiErr := s.Service.GetFeatureFlagLogs(flag, query)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	res := &domcobbv1.GetFlagLogsResponse{
+		Logs:       make([]*domcobbv1.LogEntry, 0, len(data.Logs)),
+		Pagination: toPagination(&data.PaginationResponse),
+	}
+	for _, entry := range data.Logs {
+		logEntry, err := toLogEntry(entry)
+		if err != nil {
+			return nil, toStatus(api.InternalServerError("Internal Server Error", err.Error()))
+		}
+		res.Logs = append(res.Logs, logEntry)
+	}
+
+	return res, nil
+}
+
+func (s *FlagServer) EvaluateFlag(
+	ctx context.Context,
+	req *domcobbv1.EvaluateFlagRequest,
+) (
+	*domcobbv1.EvaluateFlagResponse,
+	error,
+) {
+	flag, evaluateReq, err := s.validateEvaluateFlag(req.GetId(), req.GetContext())
+	if err != nil {
+		return nil, err
+	}
+
+	data, apiErr := s.Service.EvaluateFeatureFlag(flag, evaluateReq)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	return &domcobbv1.EvaluateFlagResponse{
+		Id:     uint32(data.ID),
+		Name:   data.Name,
+		Result: toEvaluationResult(&data.Result),
+	}, nil
+}
+
+func (s *FlagServer) ExplainFlag(
+	ctx context.Context,
+	req *domcobbv1.ExplainFlagRequest,
+) (
+	*domcobbv1.ExplainFlagResponse,
+	error,
+) {
+	flag, evaluateReq, err := s.validateEvaluateFlag(req.GetId(), req.GetContext())
+	if err != nil {
+		return nil, err
+	}
+
+	data, apiErr := s.Service.ExplainFeatureFlag(flag, evaluateReq)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	res := &domcobbv1.ExplainFlagResponse{
+		Id:     uint32(data.ID),
+		Name:   data.Name,
+		Result: toEvaluationResult(&data.Result),
+		Trace:  make([]*domcobbv1.TraceStep, 0, len(data.Trace)),
+	}
+	for _, step := range data.Trace {
+		res.Trace = append(res.Trace, &domcobbv1.TraceStep{
+			FlagId:  uint32(step.FlagID),
+			Depth:   int32(step.Depth),
+			Check:   string(step.Check),
+			Matched: step.Matched,
+			Detail:  step.Detail,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *FlagServer) EvaluateAllFlags(
+	ctx context.Context,
+	req *domcobbv1.EvaluateAllFlagsRequest,
+) (
+	*domcobbv1.EvaluateAllFlagsResponse,
+	error,
+) {
+	evaluateReq := &flags.EvaluateAllFeatureFlagsRequest{
+		Context: toEvaluationContext(req.GetContext()),
+	}
+	if err := binding.Validator.ValidateStruct(evaluateReq); err != nil {
+		return nil, invalidArgument(err)
+	}
+
+	data, apiErr := s.Service.EvaluateAllFeatureFlags(evaluateReq)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	res := &domcobbv1.EvaluateAllFlagsResponse{
+		Flags: make(map[string]*domcobbv1.EvaluationResult, len(data.Flags)),
+	}
+	for name, result := range data.Flags {
+		res.Flags[name] = toEvaluationResult(result)
+	}
+
+	return res, nil
+}
+
+func (s *FlagServer) getFlag(flagId uint) (*domcobbv1.Flag, error) {
+	flag, apiErr := s.Service.GetFeatureFlagById(flagId)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	data, apiErr := s.Service.GetFeatureFlag(flag)
+	if apiErr != nil {
+		return nil, toStatus(apiErr)
+	}
+
+	return toFlag(data), nil
+}
+
+func (s *FlagServer) validateEvaluateFlag(
+	flagId uint32,
+	ctx *domcobbv1.EvaluationContext,
+) (
+	*flags.FeatureFlag,
+	*flags.EvaluateFeatureFlagRequest,
+	error,
+) {
+	evaluateReq := &flags.EvaluateFeatureFlagRequest{Context: toEvaluationContext(ctx)}
+	if err := binding.Validator.ValidateStruct(evaluateReq); err != nil {
+		return nil, nil, invalidArgument(err)
+	}
+
+	flag, apiErr := s.Service.GetFeatureFlagById(uint(flagId))
+	if apiErr != nil {
+		return nil, nil, toStatus(apiErr)
+	}
+
+	return flag, evaluateReq, nil
+}
+
+func toFlag(data *flags.FeatureFlagData) *domcobbv1.Flag {
+	return &domcobbv1.Flag{
+		Id:           uint32(data.ID),
+		Name:         data.Name,
+		Active:       data.Active,
+		Dependencies: toUint32Slice(data.Dependencies),
+		Dependents:   toUint32Slice(data.Dependents),
+	}
+}
+
+func toPaginationQueryParam(page, size uint32) api.PaginationQueryParam {
+	query := api.PaginationQueryParam{Page: uint(page), Size: uint(size)}
+	if query.Page == 0 {
+		query.Page = defaultPage
+	}
+	if query.Size == 0 {
+		query.Size = defaultSize
+	}
+	return query
+}
+
+func toPagination(pagination *api.PaginationResponse) *domcobbv1.Pagination {
+	return &domcobbv1.Pagination{
+		Page:       uint32(pagination.Page),
+		Size:       uint32(pagination.Size),
+		Total:      uint32(pagination.Total),
+		TotalPages: uint32(pagination.TotalPages),
+	}
+}
+
+// toLogEntry converts log metadata through JSON so that values such as
+// Mongo integers and nested documents map onto protobuf Struct values.
+func toLogEntry(entry *logger.LogEntry) (*domcobbv1.LogEntry, error) {
+	logEntry := &domcobbv1.LogEntry{
+		Message:       entry.Message,
+		Timestamp:     timestamppb.New(entry.Timestamp),
+		SchemaVersion: uint32(entry.SchemaVersion),
+		Action:        string(entry.Action),
+		EntityType:    string(entry.EntityType),
+		EntityId:      uint32(entry.EntityID),
+		Actor:         entry.Actor,
+		Reason:        entry.Reason,
+		RequestId:     entry.RequestID,
+		CausedBy:      uint32(entry.CausedBy),
+		Before:        toFlagState(entry.Before),
+		After:         toFlagState(entry.After),
+		Sequence:      entry.Sequence,
+		PrevHash:      entry.PrevHash,
+		Hash:          entry.Hash,
+	}
+	if len(entry.Metadata) == 0 {
+		return logEntry, nil
+	}
+
+	raw, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, err
+	}
+	logEntry.Metadata, err = structpb.NewStruct(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return logEntry, nil
+}
+
+func toEvaluationContext(ctx *domcobbv1.EvaluationContext) evaluation.Context {
+	return evaluation.Context{TargetingKey: ctx.GetTargetingKey()}
+}
+
+func toEvaluationResult(result *evaluation.Result) *domcobbv1.EvaluationResult {
+	if result == nil {
+		return nil
+	}
+	return &domcobbv1.EvaluationResult{
+		Value:   result.Value,
+		Variant: result.Variant,
+		Reason:  string(result.Reason),
+	}
+}
+
+func toUintSlice(ids []uint32) []uint {
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uint(id))
+	}
+	return result
+}
+
+func toUint32Slice(ids []uint) []uint32 {
+	result := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uint32(id))
+	}
+	return result
+}
+
+func toFlagState(state *logger.FlagState) *domcobbv1.FlagState {
+	if state == nil {
+		return nil
+	}
+
+	return &domcobbv1.FlagState{
+		Name:         state.Name,
+		Active:       state.IsActive,
+		Dependencies: toUint32Slice(state.Dependencies),
+	}
+}
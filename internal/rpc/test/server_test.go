@@ -0,0 +1,321 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	mockFlags "github.com/ArshiAbolghasemi/dom-cobb/internal/flags/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	mockLogger "github.com/ArshiAbolghasemi/dom-cobb/internal/logger/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/rpc"
+	domcobbv1 "github.com/ArshiAbolghasemi/dom-cobb/pkg/proto/domcobb/v1"
+	"github.com/brianvoe/gofakeit/v7"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		repo   *mockFlags.MockRepository
+		log    *mockLogger.MockLogger
+		server *grpc.Server
+		conn   *grpc.ClientConn
+		client domcobbv1.FlagServiceClient
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		repo = &mockFlags.MockRepository{}
+		log = &mockLogger.MockLogger{}
+		ctx = context.Background()
+
+		listener := bufconn.Listen(1024 * 1024)
+		server = rpc.NewServer(&flags.Service{Repo: repo, Logger: log})
+		go server.Serve(listener)
+
+		var err error
+		conn, err = grpc.NewClient(
+			"passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		client = domcobbv1.NewFlagServiceClient(conn)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		server.Stop()
+		repo.AssertExpectations(GinkgoT())
+		log.AssertExpectations(GinkgoT())
+	})
+
+	expectCode := func(err error, code codes.Code) {
+		Expect(err).To(HaveOccurred())
+		Expect(status.Code(err)).To(Equal(code))
+	}
+
+	Describe("Health", func() {
+		It("should report the flag service as serving", func() {
+			res, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+				Service: domcobbv1.FlagService_ServiceDesc.ServiceName,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetStatus()).To(Equal(healthpb.HealthCheckResponse_SERVING))
+		})
+	})
+
+	Describe("Create Flag", func() {
+		It("should return invalid argument for an empty name", func() {
+			_, err := client.CreateFlag(ctx, &domcobbv1.CreateFlagRequest{})
+
+			expectCode(err, codes.InvalidArgument)
+		})
+
+		It("should return already exists for a duplicate name", func() {
+			repo.On("GetFlagByName", "checkout").Return(mockFlags.CreateFeatureFlag(mockFlags.WithName("checkout")), nil)
+
+			_, err := client.CreateFlag(ctx, &domcobbv1.CreateFlagRequest{Name: "checkout"})
+
+			expectCode(err, codes.AlreadyExists)
+		})
+
+		It("should return the created flag", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"), mockFlags.WithIsActive(true))
+			repo.On("GetFlagByName", "checkout").Return(nil, nil)
+			repo.On("CreateFlag", "checkout", true, []uint{}).Return(flag, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagDependents", flag).Return([]*flags.FeatureFlag{}, nil)
+			log.On("Log", mock.AnythingOfType("*logger.LogEntry")).Return(nil)
+
+			res, err := client.CreateFlag(ctx, &domcobbv1.CreateFlagRequest{Name: "checkout", Active: true})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetFlag().GetId()).To(Equal(uint32(1)))
+			Expect(res.GetFlag().GetName()).To(Equal("checkout"))
+			Expect(res.GetFlag().GetActive()).To(BeTrue())
+		})
+
+		It("should log the request id and trusted actor of the call", func() {
+			GinkgoT().Setenv("AUTH_TRUSTED_ACTOR_HEADER", "X-Forwarded-User")
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"))
+			repo.On("GetFlagByName", "checkout").Return(nil, nil)
+			repo.On("CreateFlag", "checkout", false, []uint{}).Return(flag, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagDependents", flag).Return([]*flags.FeatureFlag{}, nil)
+
+			var entry *logger.LogEntry
+			log.On("Log", mock.AnythingOfType("*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entry = args.Get(0).(*logger.LogEntry)
+			}).Return(nil)
+
+			var header metadata.MD
+			callCtx := metadata.AppendToOutgoingContext(ctx, "x-request-id", "req-7", "x-forwarded-user", "alice")
+			_, err := client.CreateFlag(callCtx, &domcobbv1.CreateFlagRequest{Name: "checkout"}, grpc.Header(&header))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(header.Get("x-request-id")).To(Equal([]string{"req-7"}))
+			Expect(entry.RequestID).To(Equal("req-7"))
+			Expect(entry.Actor).To(Equal("alice"))
+		})
+
+		It("should log anonymous calls without a trusted actor header", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"))
+			repo.On("GetFlagByName", "checkout").Return(nil, nil)
+			repo.On("CreateFlag", "checkout", false, []uint{}).Return(flag, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagDependents", flag).Return([]*flags.FeatureFlag{}, nil)
+
+			var entry *logger.LogEntry
+			log.On("Log", mock.AnythingOfType("*logger.LogEntry")).Run(func(args mock.Arguments) {
+				entry = args.Get(0).(*logger.LogEntry)
+			}).Return(nil)
+
+			callCtx := metadata.AppendToOutgoingContext(ctx, "x-forwarded-user", "mallory")
+			_, err := client.CreateFlag(callCtx, &domcobbv1.CreateFlagRequest{Name: "checkout"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entry.Actor).To(Equal(logger.AnonymousActor))
+			Expect(entry.RequestID).NotTo(BeEmpty())
+		})
+
+		It("should reject a call by an actor longer than the filter accepts", func() {
+			GinkgoT().Setenv("AUTH_TRUSTED_ACTOR_HEADER", "X-Forwarded-User")
+
+			actor := strings.Repeat("a", logger.MaxActorLength+1)
+			callCtx := metadata.AppendToOutgoingContext(ctx, "x-forwarded-user", actor)
+			_, err := client.CreateFlag(callCtx, &domcobbv1.CreateFlagRequest{Name: "checkout"})
+
+			expectCode(err, codes.InvalidArgument)
+		})
+	})
+
+	Describe("Get Flag", func() {
+		It("should return not found for an unknown flag", func() {
+			repo.On("GetFlagById", uint(7)).Return(nil, nil)
+
+			_, err := client.GetFlag(ctx, &domcobbv1.GetFlagRequest{Id: 7})
+
+			expectCode(err, codes.NotFound)
+		})
+
+		It("should return internal for a repository error", func() {
+			repo.On("GetFlagById", uint(7)).Return(nil, gofakeit.ErrorDatabase())
+
+			_, err := client.GetFlag(ctx, &domcobbv1.GetFlagRequest{Id: 7})
+
+			expectCode(err, codes.Internal)
+		})
+
+		It("should serve a call by an actor longer than the filter accepts", func() {
+			GinkgoT().Setenv("AUTH_TRUSTED_ACTOR_HEADER", "X-Forwarded-User")
+			repo.On("GetFlagById", uint(7)).Return(nil, nil)
+
+			actor := strings.Repeat("a", logger.MaxActorLength+1)
+			callCtx := metadata.AppendToOutgoingContext(ctx, "x-forwarded-user", actor)
+			_, err := client.GetFlag(callCtx, &domcobbv1.GetFlagRequest{Id: 7})
+
+			expectCode(err, codes.NotFound)
+		})
+
+		It("should return the flag with its dependencies and dependents", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithName("new-cart"))
+			repo.On("GetFlagById", uint(2)).Return(flag, nil)
+			repo.On("GetFlagDependencies", flag).Return(mockFlags.CreateFeatureFlagByIds([]uint{1}), nil)
+			repo.On("GetFlagDependents", flag).Return(mockFlags.CreateFeatureFlagByIds([]uint{3}), nil)
+
+			res, err := client.GetFlag(ctx, &domcobbv1.GetFlagRequest{Id: 2})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetFlag().GetDependencies()).To(Equal([]uint32{1}))
+			Expect(res.GetFlag().GetDependents()).To(Equal([]uint32{3}))
+		})
+	})
+
+	Describe("List Flags", func() {
+		It("should apply the default page and size", func() {
+			repo.On("GetFlags", 0, 10).Return([]*flags.FeatureFlag{
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout")),
+			}, uint(1), nil)
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{}, nil)
+
+			res, err := client.ListFlags(ctx, &domcobbv1.ListFlagsRequest{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetFlags()).To(HaveLen(1))
+			Expect(res.GetPagination().GetPage()).To(Equal(uint32(1)))
+			Expect(res.GetPagination().GetSize()).To(Equal(uint32(10)))
+			Expect(res.GetPagination().GetTotalPages()).To(Equal(uint32(1)))
+		})
+
+		It("should return invalid argument for an oversized page", func() {
+			_, err := client.ListFlags(ctx, &domcobbv1.ListFlagsRequest{Size: 100})
+
+			expectCode(err, codes.InvalidArgument)
+		})
+	})
+
+	Describe("Toggle Flag", func() {
+		It("should return invalid argument without a reason", func() {
+			_, err := client.ToggleFlag(ctx, &domcobbv1.ToggleFlagRequest{Id: 1, Active: true})
+
+			expectCode(err, codes.InvalidArgument)
+		})
+
+		It("should succeed with a message when the flag already has the state", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(true))
+			repo.On("GetFlagById", uint(1)).Return(flag, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{}, nil)
+			repo.On("GetFlagDependents", flag).Return([]*flags.FeatureFlag{}, nil)
+
+			res, err := client.ToggleFlag(ctx, &domcobbv1.ToggleFlagRequest{Id: 1, Active: true, Reason: "release"})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetMessage()).To(Equal("Flag is already active"))
+			Expect(res.GetFlag().GetActive()).To(BeTrue())
+		})
+
+		It("should return invalid argument when a dependency is inactive", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithIsActive(false))
+			repo.On("GetFlagById", uint(2)).Return(flag, nil)
+			repo.On("GetFlagDependencies", flag).Return([]*flags.FeatureFlag{
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithIsActive(false)),
+			}, nil)
+
+			_, err := client.ToggleFlag(ctx, &domcobbv1.ToggleFlagRequest{Id: 2, Active: true, Reason: "release"})
+
+			expectCode(err, codes.InvalidArgument)
+		})
+	})
+
+	Describe("Update Flag Dependencies", func() {
+		It("should return invalid argument for a self dependency", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1))
+			repo.On("GetFlagById", uint(1)).Return(flag, nil)
+			repo.On("GetFlagByIds", []uint{1}).Return([]*flags.FeatureFlag{flag}, nil)
+
+			_, err := client.UpdateFlagDependencies(ctx, &domcobbv1.UpdateFlagDependenciesRequest{
+				Id:           1,
+				Dependencies: []uint32{1},
+				Reason:       "cleanup",
+			})
+
+			expectCode(err, codes.InvalidArgument)
+		})
+	})
+
+	Describe("Get Flag Logs", func() {
+		It("should convert log metadata into a struct", func() {
+			flag := mockFlags.CreateFeatureFlag(mockFlags.WithId(1))
+			repo.On("GetFlagById", uint(1)).Return(flag, nil)
+			repo.On("GetFeatureFlagLogs", flag, &logger.Filter{}, uint(1), uint(10)).Return([]*logger.LogEntry{
+				{
+					Message:   "Feature Flag is toggled successfully",
+					Timestamp: time.Unix(1700000000, 0),
+					Metadata:  map[string]any{"flag_id": uint(1), "reason": "release"},
+				},
+			}, uint(1), uint(1), nil)
+
+			res, err := client.GetFlagLogs(ctx, &domcobbv1.GetFlagLogsRequest{Id: 1})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetLogs()).To(HaveLen(1))
+			Expect(res.GetLogs()[0].GetTimestamp().AsTime().Unix()).To(Equal(int64(1700000000)))
+			Expect(res.GetLogs()[0].GetMetadata().AsMap()).To(Equal(map[string]any{
+				"flag_id": float64(1),
+				"reason":  "release",
+			}))
+		})
+	})
+
+	Describe("Evaluate All Flags", func() {
+		It("should return results keyed by flag name", func() {
+			repo.On("GetAllFlags").Return([]*flags.FeatureFlag{
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(1), mockFlags.WithName("checkout"), mockFlags.WithIsActive(true)),
+				mockFlags.CreateFeatureFlag(mockFlags.WithId(2), mockFlags.WithName("dark-mode"), mockFlags.WithIsActive(false)),
+			}, nil)
+			repo.On("GetAllDependencies").Return([]*flags.FlagDependency{}, nil)
+
+			res, err := client.EvaluateAllFlags(ctx, &domcobbv1.EvaluateAllFlagsRequest{})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.GetFlags()["checkout"].GetValue()).To(BeTrue())
+			Expect(res.GetFlags()["checkout"].GetReason()).To(Equal("FALLTHROUGH"))
+			Expect(res.GetFlags()["dark-mode"].GetReason()).To(Equal("OFF"))
+		})
+	})
+})
@@ -0,0 +1,177 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func validateStreamRequest(c *gin.Context) (uint64, bool, *api.APIError) {
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID == "" {
+		return 0, false, nil
+	}
+
+	revision, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return 0, false, api.BadRequestError("Invalid input format", err.Error())
+	}
+	return revision, true, nil
+}
+
+func writeEvent(w io.Writer, event *flags.FlagEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Revision, event.Type, data)
+	return err
+}
+
+// @Summary Stream feature flag changes
+// @Description Server-Sent Events stream of flag changes (created, toggled, auto_disabled, targets_changed, dependencies_changed). Each event id is the
+// @Description global change revision; reconnect with Last-Event-ID (or last_event_id) to resume after it.
+// @Tags stream
+// @Produce text/event-stream
+// @Param Last-Event-ID header int false "Revision of the last event received"
+// @Param last_event_id query int false "Revision of the last event received, for clients that can't set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Router /api/v1/stream [get]
+func StreamAPI(c *gin.Context) {
+	revision, resume, apiErr := validateStreamRequest(c)
+	if apiErr != nil {
+		api.RespondAPIError(c, apiErr)
+		return
+	}
+
+	heartbeatInterval, err := GetHeartbeatInterval()
+	if err != nil {
+		panic("Failed to get stream heartbeat interval: " + err.Error())
+	}
+
+	broker := GetBroker()
+	events, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if resume {
+		revision, err = broker.Replay(revision, func(event *flags.FlagEvent) error {
+			return writeEvent(c.Writer, event)
+		})
+		if err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Revision <= revision {
+				continue
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				return
+			}
+			revision = event.Revision
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// @Summary Get feature flag changes
+// @Description Retrieve the configuration of every flag changed after the given revision, plus tombstones for
+// @Description flags that no longer exist. With wait > 0 the request is held until something changes or the
+// @Description wait (in seconds) passes.
+// @Tags stream
+// @Accept json
+// @Produce json
+// @Param since query int false "Revision the client already has (default: 0)"
+// @Param wait query int false "Seconds to wait for a change when there is none yet (default: 0)" maximum(60)
+// @Success 200 {object} api.SuccessResponse{data=flags.FeatureFlagChangesData} "Feature flag changes retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/flags/changes [get]
+func GetFeatureFlagChangesAPI(c *gin.Context) {
+	service := flags.GetService(flags.GetRepository(), logger.NewService())
+
+	query, apiErr := service.ValidateGetFeatureFlagChangesRequest(c)
+	if apiErr != nil {
+		api.RespondAPIError(c, apiErr)
+		return
+	}
+
+	var events <-chan *flags.FlagEvent
+	if query.Wait > 0 {
+		subscription, unsubscribe := GetBroker().Subscribe()
+		defer unsubscribe()
+		events = subscription
+	}
+
+	data, apiErr := service.GetFeatureFlagChanges(query.Since)
+	if apiErr != nil {
+		api.RespondAPIError(c, apiErr)
+		return
+	}
+
+	if len(data.Flags) == 0 && len(data.Deleted) == 0 && query.Wait > 0 {
+		timeout := time.NewTimer(time.Duration(query.Wait) * time.Second)
+		defer timeout.Stop()
+
+		revision := query.Since
+	wait:
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-timeout.C:
+				break wait
+			case event, ok := <-events:
+				if !ok {
+					break wait
+				}
+				if event.Revision > query.Since {
+					revision = event.Revision
+					break wait
+				}
+			}
+		}
+
+		data, apiErr = service.GetFeatureFlagChangesUntil(query.Since, revision)
+		if apiErr != nil {
+			api.RespondAPIError(c, apiErr)
+			return
+		}
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Feature flag changes are retrieved successfully", data)
+}
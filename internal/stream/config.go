@@ -0,0 +1,38 @@
+package stream
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+func GetPollInterval() (time.Duration, error) {
+	intervalStr, exists := os.LookupEnv("STREAM_POLL_INTERVAL")
+	if !exists {
+		return -1, fmt.Errorf("Stream poll interval is undefined")
+	}
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil {
+		return -1, err
+	}
+	if interval <= 0 {
+		return -1, fmt.Errorf("Stream poll interval must be positive")
+	}
+	return time.Duration(interval), nil
+}
+
+func GetHeartbeatInterval() (time.Duration, error) {
+	intervalStr, exists := os.LookupEnv("STREAM_HEARTBEAT_INTERVAL")
+	if !exists {
+		return -1, fmt.Errorf("Stream heartbeat interval is undefined")
+	}
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil {
+		return -1, err
+	}
+	if interval <= 0 {
+		return -1, fmt.Errorf("Stream heartbeat interval must be positive")
+	}
+	return time.Duration(interval), nil
+}
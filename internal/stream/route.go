@@ -0,0 +1,13 @@
+package stream
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	{
+		v1 := router.Group("/api/v1")
+		v1.GET("/stream", StreamAPI)
+		v1.GET("/flags/changes", GetFeatureFlagChangesAPI)
+	}
+}
@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+)
+
+const (
+	eventBatchSize   = 500
+	subscriberBuffer = 64
+)
+
+type Broker struct {
+	repo         flags.IRepository
+	pollInterval time.Duration
+
+	mu          sync.Mutex
+	revision    uint64
+	subscribers map[chan *flags.FlagEvent]struct{}
+}
+
+var (
+	broker     *Broker
+	onceBroker sync.Once
+)
+
+func GetBroker() *Broker {
+	onceBroker.Do(func() {
+		pollInterval, err := GetPollInterval()
+		if err != nil {
+			panic("Failed to get stream poll interval: " + err.Error())
+		}
+		b, err := NewBroker(flags.GetRepository(), pollInterval*time.Second)
+		if err != nil {
+			panic("Failed to start stream broker: " + err.Error())
+		}
+		notifications, _ := postgres.GetListener().Subscribe()
+		go b.Run(nil, notifications)
+		broker = b
+	})
+	return broker
+}
+
+func NewBroker(repo flags.IRepository, pollInterval time.Duration) (*Broker, error) {
+	revision, err := repo.GetLatestFlagEventRevision()
+	if err != nil {
+		return nil, err
+	}
+	return &Broker{
+		repo:         repo,
+		pollInterval: pollInterval,
+		revision:     revision,
+		subscribers:  make(map[chan *flags.FlagEvent]struct{}),
+	}, nil
+}
+
+// Run reads flag_events for revisions newer than the last one seen and fans
+// them out to local subscribers whenever a change is notified, and on every
+// poll interval as a safety net. Every replica runs its own broker against
+// the same table, so all of them deliver every change.
+func (b *Broker) Run(stop <-chan struct{}, notifications <-chan postgres.Notification) {
+	ticker := time.NewTicker(b.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-notifications:
+			b.Poll()
+		case <-ticker.C:
+			b.Poll()
+		}
+	}
+}
+
+func (b *Broker) Poll() error {
+	for {
+		b.mu.Lock()
+		revision := b.revision
+		b.mu.Unlock()
+
+		events, err := b.repo.GetFlagEventsSince(revision, eventBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			b.publish(event)
+		}
+		if len(events) < eventBatchSize {
+			return nil
+		}
+	}
+}
+
+func (b *Broker) Revision() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.revision
+}
+
+func (b *Broker) publish(event *flags.FlagEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.revision = event.Revision
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			// A subscriber that can't keep up is dropped; its client
+			// reconnects with Last-Event-ID and replays what it missed.
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (b *Broker) Subscribe() (<-chan *flags.FlagEvent, func()) {
+	subscriber := make(chan *flags.FlagEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[subscriber] = struct{}{}
+	b.mu.Unlock()
+
+	return subscriber, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}
+}
+
+func (b *Broker) Replay(revision uint64, send func(*flags.FlagEvent) error) (uint64, error) {
+	for {
+		events, err := b.repo.GetFlagEventsSince(revision, eventBatchSize)
+		if err != nil {
+			return revision, err
+		}
+		for _, event := range events {
+			if err := send(event); err != nil {
+				return revision, err
+			}
+			revision = event.Revision
+		}
+		if len(events) < eventBatchSize {
+			return revision, nil
+		}
+	}
+}
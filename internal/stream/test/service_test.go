@@ -0,0 +1,135 @@
+package stream_test
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	mockFlags "github.com/ArshiAbolghasemi/dom-cobb/internal/flags/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/stream"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func createFlagEvents(from, to uint64) []*flags.FlagEvent {
+	var events []*flags.FlagEvent
+	for revision := from; revision <= to; revision++ {
+		events = append(events, &flags.FlagEvent{
+			Revision: revision,
+			Type:     flags.FlagEventToggled,
+			FlagID:   uint(revision),
+		})
+	}
+	return events
+}
+
+var _ = Describe("Broker", func() {
+	var (
+		repo   *mockFlags.MockRepository
+		broker *stream.Broker
+	)
+
+	BeforeEach(func() {
+		repo = &mockFlags.MockRepository{}
+		repo.On("GetLatestFlagEventRevision").Return(uint64(10), nil)
+
+		var err error
+		broker, err = stream.NewBroker(repo, time.Hour)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		repo.AssertExpectations(GinkgoT())
+	})
+
+	Describe("Poll", func() {
+		It("should publish events newer than the latest revision to every subscriber", func() {
+			repo.On("GetFlagEventsSince", uint64(10), 500).Return(createFlagEvents(11, 12), nil)
+			first, unsubscribeFirst := broker.Subscribe()
+			defer unsubscribeFirst()
+			second, unsubscribeSecond := broker.Subscribe()
+			defer unsubscribeSecond()
+
+			Expect(broker.Poll()).To(Succeed())
+
+			Expect(broker.Revision()).To(Equal(uint64(12)))
+			for _, subscriber := range []<-chan *flags.FlagEvent{first, second} {
+				Expect((<-subscriber).Revision).To(Equal(uint64(11)))
+				Expect((<-subscriber).Revision).To(Equal(uint64(12)))
+			}
+		})
+
+		It("should drop subscribers that can't keep up", func() {
+			repo.On("GetFlagEventsSince", uint64(10), 500).Return(createFlagEvents(11, 110), nil)
+			subscriber, unsubscribe := broker.Subscribe()
+			defer unsubscribe()
+
+			Expect(broker.Poll()).To(Succeed())
+
+			Eventually(func() bool {
+				_, ok := <-subscriber
+				return ok
+			}).Should(BeFalse())
+		})
+	})
+
+	Describe("Run", func() {
+		It("should read new events as soon as a change is notified", func() {
+			repo.On("GetFlagEventsSince", uint64(10), 500).Return(createFlagEvents(11, 11), nil)
+			subscriber, unsubscribe := broker.Subscribe()
+			defer unsubscribe()
+
+			stop := make(chan struct{})
+			defer close(stop)
+			notifications := make(chan postgres.Notification, 1)
+			go broker.Run(stop, notifications)
+
+			notifications <- postgres.Notification{Payload: "11"}
+
+			Eventually(subscriber).Should(Receive(HaveField("Revision", uint64(11))))
+		})
+	})
+
+	Describe("Replay", func() {
+		It("should send every event after the given revision in pages", func() {
+			repo.On("GetFlagEventsSince", uint64(3), 500).Return(createFlagEvents(4, 503), nil)
+			repo.On("GetFlagEventsSince", uint64(503), 500).Return(createFlagEvents(504, 505), nil)
+
+			var sent []uint64
+			revision, err := broker.Replay(3, func(event *flags.FlagEvent) error {
+				sent = append(sent, event.Revision)
+				return nil
+			})
+
+			Expect(err).To(BeNil())
+			Expect(revision).To(Equal(uint64(505)))
+			Expect(sent).To(HaveLen(502))
+			Expect(sent[0]).To(Equal(uint64(4)))
+		})
+	})
+})
+
+var _ = Describe("Config", func() {
+	It("should parse the intervals in seconds", func() {
+		GinkgoT().Setenv("STREAM_POLL_INTERVAL", "5")
+		GinkgoT().Setenv("STREAM_HEARTBEAT_INTERVAL", "15")
+
+		pollInterval, err := stream.GetPollInterval()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pollInterval).To(Equal(time.Duration(5)))
+
+		heartbeatInterval, err := stream.GetHeartbeatInterval()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(heartbeatInterval).To(Equal(time.Duration(15)))
+	})
+
+	It("should reject intervals that are not positive", func() {
+		GinkgoT().Setenv("STREAM_POLL_INTERVAL", "0")
+		GinkgoT().Setenv("STREAM_HEARTBEAT_INTERVAL", "-1")
+
+		_, err := stream.GetPollInterval()
+		Expect(err).To(MatchError("Stream poll interval must be positive"))
+		_, err = stream.GetHeartbeatInterval()
+		Expect(err).To(MatchError("Stream heartbeat interval must be positive"))
+	})
+})
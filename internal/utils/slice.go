@@ -7,3 +7,44 @@ func ToAnySlice[T any](entries []T) []any {
 	}
 	return interfaces
 }
+
+func Chunk[T any](entries []T, size int) [][]T {
+	chunks := make([][]T, 0, (len(entries)+size-1)/size)
+	for size < len(entries) {
+		entries, chunks = entries[size:], append(chunks, entries[:size])
+	}
+	if len(entries) > 0 {
+		chunks = append(chunks, entries)
+	}
+	return chunks
+}
+
+func Unique[T comparable](entries []T) []T {
+	seen := make(map[T]struct{}, len(entries))
+	unique := make([]T, 0, len(entries))
+	for _, v := range entries {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		unique = append(unique, v)
+	}
+	return unique
+}
+
+// SameElements reports whether a and b hold the same distinct elements,
+// ignoring order and duplicates.
+func SameElements[T comparable](a, b []T) bool {
+	set := make(map[T]struct{}, len(a))
+	for _, v := range a {
+		set[v] = struct{}{}
+	}
+	seen := make(map[T]struct{}, len(b))
+	for _, v := range b {
+		if _, ok := set[v]; !ok {
+			return false
+		}
+		seen[v] = struct{}{}
+	}
+	return len(seen) == len(set)
+}
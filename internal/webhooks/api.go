@@ -0,0 +1,219 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/gin-gonic/gin"
+)
+
+func newWebhookService() *Service {
+	return GetService(GetRepository(), flags.GetRepository())
+}
+
+// @Description Request payload for creating a webhook subscription
+type CreateWebhookRequest struct {
+	URL     string   `json:"url" binding:"required,url,max=2048"`
+	Secret  string   `json:"secret" binding:"omitempty,min=16,max=255"`
+	Events  []string `json:"events" binding:"dive,oneof=created toggled auto_disabled targets_changed dependencies_changed"`
+	FlagIDs []uint   `json:"flag_ids"`
+}
+
+// @Description Webhook subscription. Empty events or flag_ids match everything.
+type WebhookData struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	FlagIDs   []uint    `json:"flag_ids"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// @Description Created webhook subscription including the HMAC-SHA256 signing secret, which is only returned once
+type CreateWebhookData struct {
+	WebhookData
+	Secret string `json:"secret"`
+}
+
+// @Summary Create a webhook
+// @Description Subscribe a URL to flag lifecycle events. Deliveries are signed with HMAC-SHA256 over
+// @Description "<timestamp>.<body>" in the X-Dom-Cobb-Signature header. A secret is generated when none is given.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body CreateWebhookRequest true "Webhook creation request"
+// @Success 201 {object} api.SuccessResponse{data=CreateWebhookData} "Webhook created successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Feature flag not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/webhooks [post]
+func CreateWebhookAPI(c *gin.Context) {
+	service := newWebhookService()
+
+	req, err := service.ValidateCreateWebhookRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.CreateWebhook(req)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusCreated, "Webhook is created successfully", data)
+}
+
+// @Summary List webhooks
+// @Description Retrieve every webhook subscription
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} api.SuccessResponse{data=[]WebhookData} "Webhooks retrieved successfully"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/webhooks [get]
+func GetWebhooksAPI(c *gin.Context) {
+	service := newWebhookService()
+
+	data, err := service.GetWebhooks()
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Webhooks are retrieved successfully", data)
+}
+
+// @Summary Get a webhook
+// @Description Retrieve a webhook subscription by its ID
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} api.SuccessResponse{data=WebhookData} "Webhook retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Webhook not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/webhooks/{id} [get]
+func GetWebhookAPI(c *gin.Context) {
+	service := newWebhookService()
+
+	webhook, err := service.ValidateGetWebhookRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Webhook is retrieved successfully", service.GetWebhook(webhook))
+}
+
+// @Summary Delete a webhook
+// @Description Delete a webhook subscription. Its pending deliveries are marked as failed.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} api.SuccessResponse "Webhook deleted successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Webhook not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/webhooks/{id} [delete]
+func DeleteWebhookAPI(c *gin.Context) {
+	service := newWebhookService()
+
+	webhook, err := service.ValidateGetWebhookRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	err = service.DeleteWebhook(webhook)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Webhook is deleted successfully", nil)
+}
+
+// @Description Query parameters for paginated webhook deliveries request
+type GetWebhookDeliveriesQueryParams struct {
+	api.PaginationQueryParam
+}
+
+// @Description Webhook delivery attempt state with the signed payload
+type WebhookDeliveryData struct {
+	WebhookDelivery
+	Payload json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+// @Description Paginated response containing webhook deliveries
+type GetWebhookDeliveriesData struct {
+	Deliveries []*WebhookDeliveryData `json:"deliveries"`
+	api.PaginationResponse
+}
+
+// @Summary Get webhook deliveries
+// @Description Retrieve the paginated delivery log of a webhook, newest first
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param size query int false "Number of items per page (default: 10)" minimum(1) maximum(20)
+// @Success 200 {object} api.SuccessResponse{data=GetWebhookDeliveriesData} "Webhook deliveries retrieved successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Webhook not found"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func GetWebhookDeliveriesAPI(c *gin.Context) {
+	service := newWebhookService()
+
+	query, webhook, err := service.ValidateGetWebhookDeliveriesRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	data, err := service.GetWebhookDeliveries(webhook, query)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusOK, "Webhook deliveries are retrieved successfully", data)
+}
+
+// @Summary Redeliver a webhook delivery
+// @Description Schedule a succeeded or failed delivery to be sent again with the same payload
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} api.SuccessResponse "Webhook delivery scheduled successfully"
+// @Failure 400 {object} api.ErrorResponse "Bad request - validation error"
+// @Failure 404 {object} api.ErrorResponse "Webhook or delivery not found"
+// @Failure 409 {object} api.ErrorResponse "Delivery is already pending"
+// @Failure 500 {object} api.ErrorResponse "Internal server error"
+// @Router /api/v1/webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func RedeliverWebhookDeliveryAPI(c *gin.Context) {
+	service := newWebhookService()
+
+	delivery, err := service.ValidateRedeliverWebhookDeliveryRequest(c)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	err = service.RedeliverWebhookDelivery(delivery)
+	if err != nil {
+		api.RespondAPIError(c, err)
+		return
+	}
+
+	api.RespondSuccess(c, http.StatusAccepted, "Webhook delivery is scheduled successfully", nil)
+}
@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+func GetPollInterval() (time.Duration, error) {
+	intervalStr, exists := os.LookupEnv("WEBHOOK_POLL_INTERVAL")
+	if !exists {
+		return -1, fmt.Errorf("Webhook poll interval is undefined")
+	}
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil {
+		return -1, err
+	}
+	return time.Duration(interval), nil
+}
+
+func GetTimeout() (time.Duration, error) {
+	timeoutStr, exists := os.LookupEnv("WEBHOOK_TIMEOUT")
+	if !exists {
+		return -1, fmt.Errorf("Webhook timeout is undefined")
+	}
+	timeout, err := strconv.Atoi(timeoutStr)
+	if err != nil {
+		return -1, err
+	}
+	return time.Duration(timeout), nil
+}
+
+func GetRetryBaseDelay() (time.Duration, error) {
+	delayStr, exists := os.LookupEnv("WEBHOOK_RETRY_BASE_DELAY")
+	if !exists {
+		return -1, fmt.Errorf("Webhook retry base delay is undefined")
+	}
+	delay, err := strconv.Atoi(delayStr)
+	if err != nil {
+		return -1, err
+	}
+	return time.Duration(delay), nil
+}
+
+func GetMaxAttempts() (uint, error) {
+	maxAttemptsStr, exists := os.LookupEnv("WEBHOOK_MAX_ATTEMPTS")
+	if !exists {
+		return 0, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS environment variable not set")
+	}
+	maxAttempts, err := strconv.ParseUint(maxAttemptsStr, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be a valid integer: %w", err)
+	}
+	return uint(maxAttempts), nil
+}
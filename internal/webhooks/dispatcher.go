@@ -0,0 +1,201 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+)
+
+const (
+	SignatureHeader = "X-Dom-Cobb-Signature"
+	TimestampHeader = "X-Dom-Cobb-Timestamp"
+	EventHeader     = "X-Dom-Cobb-Event"
+	DeliveryHeader  = "X-Dom-Cobb-Delivery"
+
+	enqueueBatchSize  = 500
+	deliveryBatchSize = 50
+	maxRetryDelay     = time.Hour
+)
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Dispatcher struct {
+	repo           IRepository
+	httpClient     *http.Client
+	lease          time.Duration
+	pollInterval   time.Duration
+	retryBaseDelay time.Duration
+	maxAttempts    uint
+}
+
+var (
+	dispatcher     *Dispatcher
+	onceDispatcher sync.Once
+)
+
+func GetDispatcher() *Dispatcher {
+	onceDispatcher.Do(func() {
+		pollInterval, err := GetPollInterval()
+		if err != nil {
+			panic("Failed to get webhook poll interval: " + err.Error())
+		}
+		timeout, err := GetTimeout()
+		if err != nil {
+			panic("Failed to get webhook timeout: " + err.Error())
+		}
+		retryBaseDelay, err := GetRetryBaseDelay()
+		if err != nil {
+			panic("Failed to get webhook retry base delay: " + err.Error())
+		}
+		maxAttempts, err := GetMaxAttempts()
+		if err != nil {
+			panic("Failed to get webhook max attempts: " + err.Error())
+		}
+		dispatcher = NewDispatcher(
+			GetRepository(),
+			timeout*time.Second,
+			pollInterval*time.Second,
+			retryBaseDelay*time.Second,
+			maxAttempts,
+		)
+	})
+	return dispatcher
+}
+
+func NewDispatcher(
+	repo IRepository,
+	timeout, pollInterval, retryBaseDelay time.Duration,
+	maxAttempts uint,
+) *Dispatcher {
+	return &Dispatcher{
+		repo:           repo,
+		httpClient:     &http.Client{Timeout: timeout},
+		lease:          2 * timeout,
+		pollInterval:   pollInterval,
+		retryBaseDelay: retryBaseDelay,
+		maxAttempts:    maxAttempts,
+	}
+}
+
+func (d *Dispatcher) Run(stop <-chan struct{}, notifications <-chan postgres.Notification) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-notifications:
+		case <-ticker.C:
+		}
+		// Failures are retried on the next tick.
+		_ = d.Dispatch()
+	}
+}
+
+func (d *Dispatcher) Dispatch() error {
+	if _, err := d.repo.EnqueueDeliveries(enqueueBatchSize); err != nil {
+		return err
+	}
+
+	deliveries, err := d.repo.ClaimDueDeliveries(deliveryBatchSize, d.lease)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(deliveries))
+	for i, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = d.deliver(delivery)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) Backoff(attempts uint) time.Duration {
+	delay := d.retryBaseDelay
+	for i := uint(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func (d *Dispatcher) deliver(delivery *WebhookDelivery) error {
+	delivery.Attempts++
+
+	var err error
+	if delivery.Webhook == nil {
+		delivery.Attempts = d.maxAttempts
+		err = fmt.Errorf("webhook is deleted")
+	} else {
+		delivery.LastStatusCode, err = d.send(delivery)
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = DeliveryStatusSucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.maxAttempts:
+		delivery.Status = DeliveryStatusFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.Status = DeliveryStatusPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.Backoff(delivery.Attempts))
+	}
+
+	return d.repo.UpdateDelivery(delivery)
+}
+
+func (d *Dispatcher) send(delivery *WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
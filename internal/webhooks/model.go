@@ -0,0 +1,123 @@
+package webhooks
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"gorm.io/gorm"
+)
+
+var EventTypes = []string{
+	flags.FlagEventCreated,
+	flags.FlagEventToggled,
+	flags.FlagEventAutoDisabled,
+	flags.FlagEventTargets,
+	flags.FlagEventDependencies,
+}
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+)
+
+type Webhook struct {
+	gorm.Model
+	URL     string   `gorm:"size:2048;not null" json:"url"`
+	Secret  string   `gorm:"size:255;not null" json:"-"`
+	Events  []string `gorm:"type:jsonb;serializer:json;not null" json:"events"`
+	FlagIDs []uint   `gorm:"type:jsonb;serializer:json;not null" json:"flag_ids"`
+}
+
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	WebhookID      uint       `gorm:"not null;uniqueIndex:idx_webhook_deliveries_webhook_revision" json:"webhook_id"`
+	Webhook        *Webhook   `gorm:"foreignKey:WebhookID" json:"-"`
+	EventRevision  uint64     `gorm:"not null;uniqueIndex:idx_webhook_deliveries_webhook_revision" json:"event_revision"`
+	EventType      string     `gorm:"size:32;not null" json:"event_type"`
+	Payload        string     `gorm:"type:jsonb;not null" json:"-"`
+	Status         string     `gorm:"size:16;not null;index" json:"status"`
+	Attempts       uint       `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"not null;index" json:"next_attempt_at"`
+	LastStatusCode int        `gorm:"not null;default:0" json:"last_status_code,omitempty"`
+	LastError      string     `gorm:"type:text;not null;default:''" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type WebhookDispatchState struct {
+	ID       uint   `gorm:"primaryKey"`
+	Revision uint64 `gorm:"not null"`
+}
+
+type FlagState struct {
+	Active       bool   `json:"active"`
+	Dependencies []uint `json:"dependencies,omitempty"`
+}
+
+type Payload struct {
+	Event     string     `json:"event"`
+	Revision  uint64     `json:"revision"`
+	FlagID    uint       `json:"flag_id"`
+	FlagName  string     `json:"flag_name"`
+	Reason    string     `json:"reason,omitempty"`
+	Actor     string     `json:"actor,omitempty"`
+	Before    *FlagState `json:"before"`
+	After     *FlagState `json:"after"`
+	Timestamp time.Time  `json:"timestamp"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (WebhookDispatchState) TableName() string {
+	return "webhook_dispatch_state"
+}
+
+func (w *Webhook) Matches(event *flags.FlagEvent) bool {
+	if event.CreatedAt.Before(w.CreatedAt) {
+		return false
+	}
+	if len(w.Events) > 0 && !slices.Contains(w.Events, event.Type) {
+		return false
+	}
+	if len(w.FlagIDs) > 0 && !slices.Contains(w.FlagIDs, event.FlagID) {
+		return false
+	}
+	return true
+}
+
+func NewPayload(event *flags.FlagEvent) *Payload {
+	payload := &Payload{
+		Event:     event.Type,
+		Revision:  event.Revision,
+		FlagID:    event.FlagID,
+		FlagName:  event.Name,
+		Reason:    event.Reason,
+		Actor:     event.Actor,
+		After:     &FlagState{Active: event.Active},
+		Timestamp: event.CreatedAt,
+	}
+
+	switch event.Type {
+	case flags.FlagEventCreated:
+		payload.Before = nil
+		payload.After.Dependencies = event.Dependencies
+	case flags.FlagEventDependencies:
+		payload.Before = &FlagState{Active: event.Active, Dependencies: event.PreviousDependencies}
+		payload.After.Dependencies = event.Dependencies
+	case flags.FlagEventToggled, flags.FlagEventAutoDisabled:
+		payload.Before = &FlagState{Active: !event.Active}
+	default:
+		payload.Before = &FlagState{Active: event.Active}
+	}
+
+	return payload
+}
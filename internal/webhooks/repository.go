@@ -0,0 +1,245 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/database/postgres"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRepository interface {
+	CreateWebhook(webhook *Webhook) error
+	GetWebhooks() ([]*Webhook, error)
+	GetWebhookById(webhookId uint) (*Webhook, error)
+	DeleteWebhook(webhook *Webhook) error
+	GetWebhookDeliveries(webhook *Webhook, page, size uint) ([]*WebhookDelivery, uint, uint, error)
+	GetWebhookDeliveryById(webhook *Webhook, deliveryId uint) (*WebhookDelivery, error)
+	RedeliverWebhookDelivery(delivery *WebhookDelivery) error
+	EnqueueDeliveries(limit int) (int, error)
+	ClaimDueDeliveries(limit int, lease time.Duration) ([]*WebhookDelivery, error)
+	UpdateDelivery(delivery *WebhookDelivery) error
+}
+
+const dispatchStateID = 1
+
+type Repository struct {
+	db *gorm.DB
+}
+
+var (
+	repo     IRepository
+	onceRepo sync.Once
+)
+
+func GetRepository() IRepository {
+	onceRepo.Do(func() {
+		repo = &Repository{
+			db: postgres.GetDB(),
+		}
+	})
+	return repo
+}
+
+func (r *Repository) CreateWebhook(webhook *Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *Repository) GetWebhooks() ([]*Webhook, error) {
+	var webhooks []*Webhook
+	err := r.db.Order("id").Find(&webhooks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (r *Repository) GetWebhookById(webhookId uint) (*Webhook, error) {
+	var webhook Webhook
+	err := r.db.Where("id = ?", webhookId).First(&webhook).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (r *Repository) DeleteWebhook(webhook *Webhook) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&WebhookDelivery{}).
+			Where("webhook_id = ? AND status = ?", webhook.ID, DeliveryStatusPending).
+			Updates(map[string]any{
+				"status":     DeliveryStatusFailed,
+				"last_error": "webhook is deleted",
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Delete(webhook).Error
+	})
+}
+
+func (r *Repository) GetWebhookDeliveries(
+	webhook *Webhook,
+	page, size uint,
+) (
+	[]*WebhookDelivery,
+	uint,
+	uint,
+	error,
+) {
+	query := r.db.Model(&WebhookDelivery{}).Where("webhook_id = ?", webhook.ID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	var deliveries []*WebhookDelivery
+	err := query.Order("id DESC").Limit(int(size)).Offset(int((page - 1) * size)).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return deliveries, uint(total), (uint(total) + size - 1) / size, nil
+}
+
+func (r *Repository) GetWebhookDeliveryById(webhook *Webhook, deliveryId uint) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := r.db.Where("id = ? AND webhook_id = ?", deliveryId, webhook.ID).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+func (r *Repository) RedeliverWebhookDelivery(delivery *WebhookDelivery) error {
+	delivery.Status = DeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	return r.db.Model(delivery).Select("status", "attempts", "next_attempt_at").Updates(delivery).Error
+}
+
+// EnqueueDeliveries turns flag events after the dispatch cursor into pending
+// deliveries for every matching webhook. The cursor row is locked for the
+// duration of the transaction so concurrent replicas never enqueue twice.
+func (r *Repository) EnqueueDeliveries(limit int) (int, error) {
+	enqueued := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO webhook_dispatch_state (id, revision)
+			SELECT ?, COALESCE(MAX(revision), 0) FROM flag_events
+			ON CONFLICT (id) DO NOTHING
+		`, dispatchStateID).Error
+		if err != nil {
+			return err
+		}
+
+		var state WebhookDispatchState
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&state, dispatchStateID).Error
+		if err != nil {
+			return err
+		}
+
+		var events []*flags.FlagEvent
+		err = tx.Where("revision > ?", state.Revision).Order("revision").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		var webhooks []*Webhook
+		if err := tx.Find(&webhooks).Error; err != nil {
+			return err
+		}
+
+		var deliveries []*WebhookDelivery
+		for _, event := range events {
+			payload, err := json.Marshal(NewPayload(event))
+			if err != nil {
+				return err
+			}
+			for _, webhook := range webhooks {
+				if !webhook.Matches(event) {
+					continue
+				}
+				deliveries = append(deliveries, &WebhookDelivery{
+					WebhookID:     webhook.ID,
+					EventRevision: event.Revision,
+					EventType:     event.Type,
+					Payload:       string(payload),
+					Status:        DeliveryStatusPending,
+					NextAttemptAt: time.Now(),
+				})
+			}
+		}
+
+		if len(deliveries) > 0 {
+			err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+			if err != nil {
+				return err
+			}
+		}
+		enqueued = len(deliveries)
+
+		state.Revision = events[len(events)-1].Revision
+		return tx.Save(&state).Error
+	})
+
+	return enqueued, err
+}
+
+// ClaimDueDeliveries leases due deliveries by pushing their next attempt past
+// the lease, so other replicas skip them while they are being sent.
+func (r *Repository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*WebhookDelivery, error) {
+	var deliveries []*WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", DeliveryStatusPending, time.Now()).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+		}
+		err = tx.Model(&WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", time.Now().Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Preload("Webhook").Where("id IN ?", ids).Find(&deliveries).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *Repository) UpdateDelivery(delivery *WebhookDelivery) error {
+	return r.db.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at").
+		Updates(delivery).Error
+}
@@ -0,0 +1,17 @@
+package webhooks
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func SetupRoutes(router *gin.Engine) {
+	{
+		v1 := router.Group("/api/v1")
+		v1.POST("/webhooks", CreateWebhookAPI)
+		v1.GET("/webhooks", GetWebhooksAPI)
+		v1.GET("/webhooks/:id", GetWebhookAPI)
+		v1.DELETE("/webhooks/:id", DeleteWebhookAPI)
+		v1.GET("/webhooks/:id/deliveries", GetWebhookDeliveriesAPI)
+		v1.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", RedeliverWebhookDeliveryAPI)
+	}
+}
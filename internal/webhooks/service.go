@@ -0,0 +1,222 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/api"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type Service struct {
+	Repo      IRepository
+	FlagsRepo flags.IRepository
+}
+
+var (
+	service     *Service
+	onceService sync.Once
+)
+
+func GetService(repo IRepository, flagsRepo flags.IRepository) *Service {
+	onceService.Do(func() {
+		service = &Service{
+			Repo:      repo,
+			FlagsRepo: flagsRepo,
+		}
+	})
+	return service
+}
+
+func newWebhookData(webhook *Webhook) *WebhookData {
+	return &WebhookData{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		FlagIDs:   webhook.FlagIDs,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func (s *Service) ValidateCreateWebhookRequest(c *gin.Context) (*CreateWebhookRequest, *api.APIError) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	if len(req.FlagIDs) == 0 {
+		return &req, nil
+	}
+
+	req.FlagIDs = utils.Unique(req.FlagIDs)
+	flags, err := s.FlagsRepo.GetFlagByIds(req.FlagIDs)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if len(flags) != len(req.FlagIDs) {
+		return nil, api.NotFoundError("Invalid feature flag ids", "")
+	}
+
+	return &req, nil
+}
+
+func (s *Service) CreateWebhook(req *CreateWebhookRequest) (*CreateWebhookData, *api.APIError) {
+	secret := req.Secret
+	if secret == "" {
+		bytes := make([]byte, 32)
+		if _, err := rand.Read(bytes); err != nil {
+			return nil, api.InternalServerError("Internal Server Error", err.Error())
+		}
+		secret = hex.EncodeToString(bytes)
+	}
+
+	webhook := &Webhook{
+		URL:     req.URL,
+		Secret:  secret,
+		Events:  req.Events,
+		FlagIDs: req.FlagIDs,
+	}
+	if webhook.Events == nil {
+		webhook.Events = []string{}
+	}
+	if webhook.FlagIDs == nil {
+		webhook.FlagIDs = []uint{}
+	}
+
+	if err := s.Repo.CreateWebhook(webhook); err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	return &CreateWebhookData{
+		WebhookData: *newWebhookData(webhook),
+		Secret:      secret,
+	}, nil
+}
+
+func (s *Service) GetWebhooks() ([]*WebhookData, *api.APIError) {
+	webhooks, err := s.Repo.GetWebhooks()
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data := make([]*WebhookData, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		data = append(data, newWebhookData(webhook))
+	}
+	return data, nil
+}
+
+func (s *Service) ValidateGetWebhookRequest(c *gin.Context) (*Webhook, *api.APIError) {
+	webhookId, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	webhook, err := s.Repo.GetWebhookById(uint(webhookId))
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if webhook == nil {
+		return nil, api.NotFoundError("Invalid webhook id", "")
+	}
+
+	return webhook, nil
+}
+
+func (s *Service) GetWebhook(webhook *Webhook) *WebhookData {
+	return newWebhookData(webhook)
+}
+
+func (s *Service) DeleteWebhook(webhook *Webhook) *api.APIError {
+	if err := s.Repo.DeleteWebhook(webhook); err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+	return nil
+}
+
+func (s *Service) ValidateGetWebhookDeliveriesRequest(
+	c *gin.Context,
+) (
+	*GetWebhookDeliveriesQueryParams,
+	*Webhook,
+	*api.APIError,
+) {
+	webhook, apiErr := s.ValidateGetWebhookRequest(c)
+	if apiErr != nil {
+		return nil, nil, apiErr
+	}
+
+	var query GetWebhookDeliveriesQueryParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		return nil, nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	return &query, webhook, nil
+}
+
+func (s *Service) GetWebhookDeliveries(
+	webhook *Webhook,
+	query *GetWebhookDeliveriesQueryParams,
+) (
+	*GetWebhookDeliveriesData,
+	*api.APIError,
+) {
+	deliveries, total, totalPages, err := s.Repo.GetWebhookDeliveries(webhook, query.Page, query.Size)
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+
+	data := &GetWebhookDeliveriesData{
+		Deliveries: make([]*WebhookDeliveryData, 0, len(deliveries)),
+		PaginationResponse: api.PaginationResponse{
+			Page:       query.Page,
+			Size:       query.Size,
+			Total:      total,
+			TotalPages: totalPages,
+		},
+	}
+	for _, delivery := range deliveries {
+		data.Deliveries = append(data.Deliveries, &WebhookDeliveryData{
+			WebhookDelivery: *delivery,
+			Payload:         json.RawMessage(delivery.Payload),
+		})
+	}
+	return data, nil
+}
+
+func (s *Service) ValidateRedeliverWebhookDeliveryRequest(c *gin.Context) (*WebhookDelivery, *api.APIError) {
+	webhook, apiErr := s.ValidateGetWebhookRequest(c)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	deliveryId, err := strconv.ParseUint(c.Param("delivery_id"), 10, 32)
+	if err != nil {
+		return nil, api.BadRequestError("Invalid input format", err.Error())
+	}
+
+	delivery, err := s.Repo.GetWebhookDeliveryById(webhook, uint(deliveryId))
+	if err != nil {
+		return nil, api.InternalServerError("Internal Server Error", err.Error())
+	}
+	if delivery == nil {
+		return nil, api.NotFoundError("Invalid delivery id", "")
+	}
+	if delivery.Status == DeliveryStatusPending {
+		return nil, api.ConflictError("Delivery is already pending", "")
+	}
+
+	return delivery, nil
+}
+
+func (s *Service) RedeliverWebhookDelivery(delivery *WebhookDelivery) *api.APIError {
+	if err := s.Repo.RedeliverWebhookDelivery(delivery); err != nil {
+		return api.InternalServerError("Internal Server Error", err.Error())
+	}
+	return nil
+}
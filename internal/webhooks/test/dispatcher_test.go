@@ -0,0 +1,177 @@
+package webhooks_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/flags"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks"
+	mockWebhooks "github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks/test/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*receivedRequest
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, &receivedRequest{header: req.Header, body: body})
+	w.WriteHeader(r.status)
+}
+
+func newDelivery(url, secret string) *webhooks.WebhookDelivery {
+	payload, _ := json.Marshal(webhooks.NewPayload(&flags.FlagEvent{
+		Revision:  42,
+		Type:      flags.FlagEventToggled,
+		FlagID:    7,
+		Name:      "checkout",
+		Active:    false,
+		Reason:    "incident",
+		Actor:     "alice",
+		CreatedAt: time.Now(),
+	}))
+	return &webhooks.WebhookDelivery{
+		ID:            3,
+		WebhookID:     1,
+		Webhook:       &webhooks.Webhook{URL: url, Secret: secret},
+		EventRevision: 42,
+		EventType:     flags.FlagEventToggled,
+		Payload:       string(payload),
+		Status:        webhooks.DeliveryStatusPending,
+	}
+}
+
+var _ = Describe("NewPayload", func() {
+	It("should carry the replaced dependencies of a dependencies_changed event", func() {
+		payload := webhooks.NewPayload(&flags.FlagEvent{
+			Revision:             43,
+			Type:                 flags.FlagEventDependencies,
+			FlagID:               7,
+			Name:                 "checkout",
+			Active:               true,
+			Dependencies:         []uint{2, 3},
+			PreviousDependencies: []uint{1},
+			Actor:                "alice",
+		})
+
+		Expect(payload.Actor).To(Equal("alice"))
+		Expect(payload.Before).To(Equal(&webhooks.FlagState{Active: true, Dependencies: []uint{1}}))
+		Expect(payload.After).To(Equal(&webhooks.FlagState{Active: true, Dependencies: []uint{2, 3}}))
+	})
+})
+
+var _ = Describe("Dispatcher", func() {
+	var (
+		repo       *mockWebhooks.MockRepository
+		dispatcher *webhooks.Dispatcher
+		recv       *receiver
+		server     *httptest.Server
+	)
+
+	BeforeEach(func() {
+		repo = &mockWebhooks.MockRepository{}
+		dispatcher = webhooks.NewDispatcher(repo, time.Second, time.Second, 5*time.Second, 3)
+		recv = &receiver{status: http.StatusOK}
+		server = httptest.NewServer(recv)
+	})
+
+	AfterEach(func() {
+		server.Close()
+		repo.AssertExpectations(GinkgoT())
+	})
+
+	Describe("Dispatch", func() {
+		When("the receiver accepts the delivery", func() {
+			It("should send a signed payload and mark the delivery as succeeded", func() {
+				delivery := newDelivery(server.URL, "top-secret-signing-key")
+				repo.On("EnqueueDeliveries", 500).Return(1, nil)
+				repo.On("ClaimDueDeliveries", 50, 2*time.Second).Return([]*webhooks.WebhookDelivery{delivery}, nil)
+				repo.On("UpdateDelivery", delivery).Return(nil)
+
+				Expect(dispatcher.Dispatch()).To(Succeed())
+
+				Expect(recv.requests).To(HaveLen(1))
+				request := recv.requests[0]
+				timestamp, err := strconv.ParseInt(request.header.Get(webhooks.TimestampHeader), 10, 64)
+				Expect(err).To(BeNil())
+				Expect(request.header.Get(webhooks.SignatureHeader)).To(
+					Equal(webhooks.Sign("top-secret-signing-key", timestamp, request.body)),
+				)
+				Expect(request.header.Get(webhooks.EventHeader)).To(Equal(flags.FlagEventToggled))
+				Expect(request.header.Get(webhooks.DeliveryHeader)).To(Equal("3"))
+
+				var payload webhooks.Payload
+				Expect(json.Unmarshal(request.body, &payload)).To(Succeed())
+				Expect(payload.Reason).To(Equal("incident"))
+				Expect(payload.Actor).To(Equal("alice"))
+				Expect(payload.Before.Active).To(BeTrue())
+				Expect(payload.After.Active).To(BeFalse())
+
+				Expect(delivery.Status).To(Equal(webhooks.DeliveryStatusSucceeded))
+				Expect(delivery.Attempts).To(Equal(uint(1)))
+				Expect(delivery.LastStatusCode).To(Equal(http.StatusOK))
+				Expect(delivery.DeliveredAt).NotTo(BeNil())
+			})
+		})
+
+		When("the receiver rejects the delivery", func() {
+			It("should schedule a retry with exponential backoff", func() {
+				recv.status = http.StatusBadGateway
+				delivery := newDelivery(server.URL, "top-secret-signing-key")
+				delivery.Attempts = 1
+				repo.On("EnqueueDeliveries", 500).Return(0, nil)
+				repo.On("ClaimDueDeliveries", 50, 2*time.Second).Return([]*webhooks.WebhookDelivery{delivery}, nil)
+				repo.On("UpdateDelivery", mock.AnythingOfType("*webhooks.WebhookDelivery")).Return(nil)
+
+				before := time.Now()
+				Expect(dispatcher.Dispatch()).To(Succeed())
+
+				Expect(delivery.Status).To(Equal(webhooks.DeliveryStatusPending))
+				Expect(delivery.Attempts).To(Equal(uint(2)))
+				Expect(delivery.LastStatusCode).To(Equal(http.StatusBadGateway))
+				Expect(delivery.LastError).NotTo(BeEmpty())
+				Expect(delivery.NextAttemptAt).To(BeTemporally("~", before.Add(10*time.Second), time.Second))
+			})
+
+			It("should give up after the maximum attempts", func() {
+				recv.status = http.StatusInternalServerError
+				delivery := newDelivery(server.URL, "top-secret-signing-key")
+				delivery.Attempts = 2
+				repo.On("EnqueueDeliveries", 500).Return(0, nil)
+				repo.On("ClaimDueDeliveries", 50, 2*time.Second).Return([]*webhooks.WebhookDelivery{delivery}, nil)
+				repo.On("UpdateDelivery", delivery).Return(nil)
+
+				Expect(dispatcher.Dispatch()).To(Succeed())
+
+				Expect(delivery.Status).To(Equal(webhooks.DeliveryStatusFailed))
+				Expect(delivery.Attempts).To(Equal(uint(3)))
+			})
+		})
+	})
+
+	Describe("Backoff", func() {
+		It("should double the delay per attempt up to an hour", func() {
+			Expect(dispatcher.Backoff(1)).To(Equal(5 * time.Second))
+			Expect(dispatcher.Backoff(2)).To(Equal(10 * time.Second))
+			Expect(dispatcher.Backoff(4)).To(Equal(40 * time.Second))
+			Expect(dispatcher.Backoff(30)).To(Equal(time.Hour))
+		})
+	})
+})
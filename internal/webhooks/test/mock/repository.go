@@ -0,0 +1,85 @@
+package mock
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateWebhook(webhook *webhooks.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetWebhooks() ([]*webhooks.Webhook, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhooks.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) GetWebhookById(id uint) (*webhooks.Webhook, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhooks.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) DeleteWebhook(webhook *webhooks.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetWebhookDeliveries(
+	webhook *webhooks.Webhook,
+	page, size uint,
+) (
+	[]*webhooks.WebhookDelivery,
+	uint,
+	uint,
+	error,
+) {
+	args := m.Called(webhook, page, size)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
+	}
+	return args.Get(0).([]*webhooks.WebhookDelivery), args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
+}
+
+func (m *MockRepository) GetWebhookDeliveryById(webhook *webhooks.Webhook, id uint) (*webhooks.WebhookDelivery, error) {
+	args := m.Called(webhook, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*webhooks.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) RedeliverWebhookDelivery(delivery *webhooks.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockRepository) EnqueueDeliveries(limit int) (int, error) {
+	args := m.Called(limit)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*webhooks.WebhookDelivery, error) {
+	args := m.Called(limit, lease)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*webhooks.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) UpdateDelivery(delivery *webhooks.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
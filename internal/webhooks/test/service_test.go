@@ -0,0 +1,91 @@
+package webhooks_test
+
+import (
+	"net/http"
+
+	mockFlags "github.com/ArshiAbolghasemi/dom-cobb/internal/flags/test/mock"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/testutils"
+	"github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks"
+	mockWebhooks "github.com/ArshiAbolghasemi/dom-cobb/internal/webhooks/test/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Service", func() {
+	var (
+		repo      *mockWebhooks.MockRepository
+		flagsRepo *mockFlags.MockRepository
+		service   *webhooks.Service
+	)
+
+	BeforeEach(func() {
+		repo = &mockWebhooks.MockRepository{}
+		flagsRepo = &mockFlags.MockRepository{}
+		service = &webhooks.Service{Repo: repo, FlagsRepo: flagsRepo}
+	})
+
+	AfterEach(func() {
+		repo.AssertExpectations(GinkgoT())
+		flagsRepo.AssertExpectations(GinkgoT())
+	})
+
+	Describe("Validate Create Webhook Request", func() {
+		DescribeTable("should reject invalid requests",
+			func(setupMock func(), req map[string]any, httpCode int) {
+				setupMock()
+				c, _ := testutils.CreateJSONRequest(http.MethodPost, "/api/v1/webhooks", req)
+
+				result, err := service.ValidateCreateWebhookRequest(c)
+
+				Expect(result).To(BeNil())
+				Expect(err).NotTo(BeNil())
+				Expect(err.StatusCode).To(Equal(httpCode))
+			},
+			Entry("url is missed", func() {}, map[string]any{}, http.StatusBadRequest),
+			Entry(
+				"event type is unknown",
+				func() {},
+				map[string]any{"url": "https://deploy.example.com/hook", "events": []string{"archived"}},
+				http.StatusBadRequest,
+			),
+			Entry(
+				"flag ids do not exist",
+				func() {
+					flagsRepo.On("GetFlagByIds", []uint{1, 2}).Return(mockFlags.CreateFeatureFlagByIds([]uint{1}), nil)
+				},
+				map[string]any{"url": "https://deploy.example.com/hook", "flag_ids": []uint{1, 2}},
+				http.StatusNotFound,
+			),
+		)
+
+		It("should accept repeated flag ids once", func() {
+			flagsRepo.On("GetFlagByIds", []uint{1, 2}).Return(mockFlags.CreateFeatureFlagByIds([]uint{1, 2}), nil)
+			c, _ := testutils.CreateJSONRequest(http.MethodPost, "/api/v1/webhooks", map[string]any{
+				"url":      "https://deploy.example.com/hook",
+				"flag_ids": []uint{1, 2, 1},
+			})
+
+			result, err := service.ValidateCreateWebhookRequest(c)
+
+			Expect(err).To(BeNil())
+			Expect(result.FlagIDs).To(Equal([]uint{1, 2}))
+		})
+	})
+
+	Describe("Create Webhook", func() {
+		It("should generate a signing secret when none is given", func() {
+			repo.On("CreateWebhook", mock.AnythingOfType("*webhooks.Webhook")).Return(nil)
+
+			result, err := service.CreateWebhook(&webhooks.CreateWebhookRequest{
+				URL:    "https://deploy.example.com/hook",
+				Events: []string{"toggled"},
+			})
+
+			Expect(err).To(BeNil())
+			Expect(result.Secret).To(HaveLen(64))
+			Expect(result.Events).To(Equal([]string{"toggled"}))
+			Expect(result.FlagIDs).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,275 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+var ErrFlagNotFound = errors.New("feature flag not found")
+
+type Client struct {
+	baseURL      string
+	httpClient   *http.Client
+	pollInterval time.Duration
+	streaming    bool
+	onChange     func(*evaluation.Config)
+
+	mu    sync.Mutex
+	etag  string
+	state atomic.Pointer[state]
+
+	changed   chan struct{}
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+type state struct {
+	config    *evaluation.Config
+	evaluator *evaluation.Evaluator
+	flagIDs   map[string]uint
+}
+
+func newState(config *evaluation.Config) *state {
+	flagIDs := make(map[string]uint, len(config.Flags))
+	for _, flag := range config.Flags {
+		flagIDs[flag.Name] = flag.ID
+	}
+	return &state{
+		config:    config,
+		evaluator: evaluation.NewEvaluator(config.Flags),
+		flagIDs:   flagIDs,
+	}
+}
+
+func New(ctx context.Context, baseURL string, opts ...Option) (*Client, error) {
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		pollInterval: defaultPollInterval,
+		changed:      make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := c.Refresh(ctx); err != nil && c.state.Load() == nil {
+		return nil, err
+	}
+
+	c.wg.Add(1)
+	go c.poll()
+	if c.streaming {
+		c.wg.Add(1)
+		go c.stream()
+	}
+	return c, nil
+}
+
+func (c *Client) Close() {
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		c.wg.Wait()
+	})
+}
+
+func (c *Client) Config() *evaluation.Config {
+	return c.state.Load().config
+}
+
+func (c *Client) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+configPath, nil)
+	if err != nil {
+		return err
+	}
+	if c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch feature flags configuration: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("failed to fetch feature flags configuration: unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data evaluation.Config `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("failed to decode feature flags configuration: %w", err)
+	}
+
+	c.state.Store(newState(&body.Data))
+	c.etag = resp.Header.Get("ETag")
+	if c.onChange != nil {
+		c.onChange(&body.Data)
+	}
+	return nil
+}
+
+func (c *Client) poll() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+		case <-c.changed:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), c.pollInterval)
+		// On failure the last known configuration keeps being served.
+		_ = c.Refresh(ctx)
+		cancel()
+	}
+}
+
+// stream listens to the server's change stream and wakes the poller on
+// every event. Events missed while disconnected are covered by refreshing
+// again after each reconnect.
+func (c *Client) stream() {
+	defer c.wg.Done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.stop
+		cancel()
+	}()
+
+	delay := time.Second
+	for {
+		connected := c.readStream(ctx)
+		if connected {
+			delay = time.Second
+		}
+
+		select {
+		case <-c.stop:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxStreamRetryDelay)
+	}
+}
+
+func (c *Client) readStream(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+streamPath, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream is long-lived, so it can't share the client's timeout.
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+
+	c.notifyChanged()
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "data:") {
+			c.notifyChanged()
+		}
+	}
+	return true
+}
+
+func (c *Client) notifyChanged() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Client) Evaluate(key string, ctx evaluation.Context) (*evaluation.Result, error) {
+	s := c.state.Load()
+	flagID, ok := s.flagIDs[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+	}
+	return s.evaluator.Evaluate(flagID, ctx), nil
+}
+
+func (c *Client) EvaluateAll(ctx evaluation.Context) map[string]*evaluation.Result {
+	s := c.state.Load()
+	results := s.evaluator.EvaluateAll(ctx)
+
+	byName := make(map[string]*evaluation.Result, len(results))
+	for name, flagID := range s.flagIDs {
+		byName[name] = results[flagID]
+	}
+	return byName
+}
+
+func (c *Client) Bool(key string, ctx evaluation.Context, defaultValue bool) bool {
+	result, err := c.Evaluate(key, ctx)
+	if err != nil || result.Reason == evaluation.ReasonError {
+		return defaultValue
+	}
+	return result.Value
+}
+
+func (c *Client) String(key string, ctx evaluation.Context, defaultValue string) string {
+	result, err := c.Evaluate(key, ctx)
+	if err != nil || result.Reason == evaluation.ReasonError {
+		return defaultValue
+	}
+	return result.Variant
+}
+
+// Int returns 1 for a flag that is on and 0 for one that is off.
+func (c *Client) Int(key string, ctx evaluation.Context, defaultValue int64) int64 {
+	result, err := c.Evaluate(key, ctx)
+	if err != nil || result.Reason == evaluation.ReasonError {
+		return defaultValue
+	}
+	if result.Value {
+		return 1
+	}
+	return 0
+}
+
+// JSON returns the value of the flag as the JSON document true or false.
+func (c *Client) JSON(key string, ctx evaluation.Context, defaultValue json.RawMessage) json.RawMessage {
+	result, err := c.Evaluate(key, ctx)
+	if err != nil || result.Reason == evaluation.ReasonError {
+		return defaultValue
+	}
+	value, err := json.Marshal(result.Value)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
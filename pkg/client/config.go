@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+const (
+	configPath          = "/api/v1/config"
+	streamPath          = "/api/v1/stream"
+	defaultPollInterval = 30 * time.Second
+	defaultTimeout      = 10 * time.Second
+	maxStreamRetryDelay = 30 * time.Second
+)
+
+type Option func(*Client)
+
+// WithPollInterval sets how often the configuration is refreshed. Intervals
+// that are not positive keep the default.
+func WithPollInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		if interval > 0 {
+			c.pollInterval = interval
+		}
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithStreaming refreshes the configuration as soon as the server streams a
+// change, in addition to polling.
+func WithStreaming() Option {
+	return func(c *Client) {
+		c.streaming = true
+	}
+}
+
+// WithInitialConfig serves the given configuration until the first
+// successful fetch, and lets New succeed while the server is unreachable.
+func WithInitialConfig(config *evaluation.Config) Option {
+	return func(c *Client) {
+		c.state.Store(newState(config))
+	}
+}
+
+// WithChangeHandler is called with every newly fetched configuration.
+func WithChangeHandler(handler func(*evaluation.Config)) Option {
+	return func(c *Client) {
+		c.onChange = handler
+	}
+}
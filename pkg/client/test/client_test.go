@@ -0,0 +1,259 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/client"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeServer struct {
+	events      chan struct{}
+	mu          sync.Mutex
+	config      *evaluation.Config
+	version     int
+	unavailable bool
+	requests    atomic.Int32
+	notModified atomic.Int32
+}
+
+func (f *fakeServer) setConfig(config *evaluation.Config) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.config = config
+	f.version++
+}
+
+func (f *fakeServer) setUnavailable(unavailable bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.unavailable = unavailable
+}
+
+func (f *fakeServer) serveStream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	for revision := 1; ; revision++ {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-f.events:
+			fmt.Fprintf(w, "id: %d\nevent: toggled\ndata: {}\n\n", revision)
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v1/stream" && f.events != nil {
+		f.serveStream(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests.Add(1)
+
+	if r.URL.Path != "/api/v1/config" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if f.unavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	etag := fmt.Sprintf("%q", fmt.Sprint(f.version))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		f.notModified.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": f.config})
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fake   *fakeServer
+		server *httptest.Server
+		c      *client.Client
+	)
+
+	BeforeEach(func() {
+		fake = &fakeServer{}
+		fake.setConfig(&evaluation.Config{
+			Flags: []*evaluation.Flag{
+				{ID: 1, Name: "checkout", Active: true, Deny: []string{"user-2"}},
+				{ID: 2, Name: "new-cart", Active: true, Dependencies: []uint{1}},
+				{ID: 3, Name: "dark-mode", Active: false},
+			},
+		})
+		server = httptest.NewServer(fake)
+
+		var err error
+		c, err = client.New(context.Background(), server.URL, client.WithPollInterval(10*time.Millisecond))
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		c.Close()
+		server.Close()
+	})
+
+	Describe("Typed getters", func() {
+		It("should evaluate flags locally", func() {
+			Expect(c.Bool("checkout", evaluation.Context{TargetingKey: "user-1"}, false)).To(BeTrue())
+			Expect(c.Bool("new-cart", evaluation.Context{TargetingKey: "user-2"}, true)).To(BeFalse())
+			Expect(c.String("dark-mode", evaluation.Context{}, "")).To(Equal(evaluation.VariantOff))
+			Expect(c.Int("checkout", evaluation.Context{TargetingKey: "user-1"}, -1)).To(Equal(int64(1)))
+			Expect(c.Int("dark-mode", evaluation.Context{}, -1)).To(Equal(int64(0)))
+			Expect(c.JSON("checkout", evaluation.Context{TargetingKey: "user-1"}, nil)).To(MatchJSON("true"))
+			Expect(c.JSON("new-cart", evaluation.Context{TargetingKey: "user-2"}, nil)).To(MatchJSON("false"))
+		})
+
+		It("should return the default for unknown flags", func() {
+			Expect(c.Bool("unknown", evaluation.Context{}, true)).To(BeTrue())
+			Expect(c.String("unknown", evaluation.Context{}, "fallback")).To(Equal("fallback"))
+			Expect(c.Int("unknown", evaluation.Context{}, 7)).To(Equal(int64(7)))
+			Expect(c.JSON("unknown", evaluation.Context{}, json.RawMessage(`{"a":1}`))).To(MatchJSON(`{"a":1}`))
+
+			_, err := c.Evaluate("unknown", evaluation.Context{})
+			Expect(err).To(MatchError(client.ErrFlagNotFound))
+		})
+	})
+
+	Describe("Polling", func() {
+		It("should send the ETag and keep the configuration on 304", func() {
+			Eventually(fake.notModified.Load).Should(BeNumerically(">", 0))
+			Expect(c.Bool("checkout", evaluation.Context{}, false)).To(BeTrue())
+		})
+
+		It("should pick up configuration changes", func() {
+			fake.setConfig(&evaluation.Config{
+				Flags: []*evaluation.Flag{{ID: 1, Name: "checkout", Active: false}},
+			})
+
+			Eventually(func() bool {
+				return c.Bool("checkout", evaluation.Context{}, true)
+			}).Should(BeFalse())
+		})
+
+		It("should keep serving the last known configuration when the server is unreachable", func() {
+			fake.setUnavailable(true)
+			requests := fake.requests.Load()
+			Eventually(fake.requests.Load).Should(BeNumerically(">", requests+1))
+
+			Expect(c.Refresh(context.Background())).NotTo(Succeed())
+			Expect(c.Bool("checkout", evaluation.Context{}, false)).To(BeTrue())
+
+			server.Close()
+			Expect(c.Refresh(context.Background())).NotTo(Succeed())
+			Expect(c.Bool("checkout", evaluation.Context{}, false)).To(BeTrue())
+		})
+	})
+
+	Describe("EvaluateAll", func() {
+		It("should evaluate every flag keyed by name", func() {
+			results := c.EvaluateAll(evaluation.Context{TargetingKey: "user-2"})
+
+			Expect(results).To(HaveLen(3))
+			Expect(results["checkout"].Reason).To(Equal(evaluation.ReasonTargetMatch))
+			Expect(results["new-cart"].Reason).To(Equal(evaluation.ReasonPrerequisiteFailed))
+			Expect(results["dark-mode"].Reason).To(Equal(evaluation.ReasonOff))
+		})
+	})
+
+	Describe("Streaming", func() {
+		It("should refresh as soon as the server streams a change", func() {
+			streamFake := &fakeServer{events: make(chan struct{})}
+			streamFake.setConfig(&evaluation.Config{
+				Flags: []*evaluation.Flag{{ID: 3, Name: "dark-mode", Active: false}},
+			})
+			streamServer := httptest.NewServer(streamFake)
+			defer streamServer.Close()
+
+			streamed, err := client.New(context.Background(), streamServer.URL,
+				client.WithPollInterval(time.Hour),
+				client.WithStreaming(),
+			)
+			Expect(err).To(BeNil())
+			defer streamed.Close()
+
+			streamFake.setConfig(&evaluation.Config{
+				Flags: []*evaluation.Flag{{ID: 3, Name: "dark-mode", Active: true}},
+			})
+			Eventually(streamFake.events).Should(BeSent(struct{}{}))
+
+			Eventually(func() bool {
+				return streamed.Bool("dark-mode", evaluation.Context{}, false)
+			}).Should(BeTrue())
+		})
+	})
+
+	Describe("New", func() {
+		It("should fail when the initial configuration cannot be fetched", func() {
+			fake.setUnavailable(true)
+
+			result, err := client.New(context.Background(), server.URL)
+
+			Expect(err).NotTo(BeNil())
+			Expect(result).To(BeNil())
+		})
+
+		It("should keep the default poll interval for intervals that are not positive", func() {
+			result, err := client.New(context.Background(), server.URL, client.WithPollInterval(0))
+			Expect(err).To(BeNil())
+			defer result.Close()
+
+			Expect(result.Bool("checkout", evaluation.Context{}, false)).To(BeTrue())
+		})
+
+		It("should serve the initial configuration until the server is reachable", func() {
+			fake.setUnavailable(true)
+
+			result, err := client.New(context.Background(), server.URL,
+				client.WithPollInterval(10*time.Millisecond),
+				client.WithInitialConfig(&evaluation.Config{
+					Flags: []*evaluation.Flag{{ID: 3, Name: "dark-mode", Active: true}},
+				}),
+			)
+			Expect(err).To(BeNil())
+			defer result.Close()
+			Expect(result.Bool("dark-mode", evaluation.Context{}, false)).To(BeTrue())
+
+			fake.setUnavailable(false)
+			Eventually(func() bool {
+				return result.Bool("dark-mode", evaluation.Context{}, true)
+			}).Should(BeFalse())
+		})
+
+		It("should call the change handler with every fetched configuration", func() {
+			var changes atomic.Int32
+			result, err := client.New(context.Background(), server.URL,
+				client.WithPollInterval(10*time.Millisecond),
+				client.WithChangeHandler(func(config *evaluation.Config) {
+					changes.Add(1)
+				}),
+			)
+			Expect(err).To(BeNil())
+			defer result.Close()
+			Expect(changes.Load()).To(Equal(int32(1)))
+
+			fake.setConfig(&evaluation.Config{})
+			Eventually(changes.Load).Should(Equal(int32(2)))
+			Consistently(changes.Load, 50*time.Millisecond).Should(Equal(int32(2)))
+		})
+	})
+})
@@ -0,0 +1,63 @@
+package evaluation
+
+type Reason string
+
+const (
+	ReasonOff                Reason = "OFF"
+	ReasonPrerequisiteFailed Reason = "PREREQUISITE_FAILED"
+	ReasonTargetMatch        Reason = "TARGET_MATCH"
+	ReasonFallthrough        Reason = "FALLTHROUGH"
+	ReasonError              Reason = "ERROR"
+)
+
+const (
+	VariantOn  = "on"
+	VariantOff = "off"
+)
+
+// @Description Context a feature flag is evaluated for
+type Context struct {
+	TargetingKey string `json:"targeting_key" binding:"max=255"`
+}
+
+// @Description Evaluation configuration of a single feature flag
+type Flag struct {
+	ID           uint     `json:"id"`
+	Name         string   `json:"name"`
+	Active       bool     `json:"active"`
+	Dependencies []uint   `json:"dependencies"`
+	Allow        []string `json:"allow,omitempty"`
+	Deny         []string `json:"deny,omitempty"`
+}
+
+// @Description Result of evaluating a feature flag for a context
+type Result struct {
+	Value   bool   `json:"value"`
+	Variant string `json:"variant"`
+	Reason  Reason `json:"reason"`
+}
+
+type Check string
+
+const (
+	CheckActive       Check = "active"
+	CheckPrerequisite Check = "prerequisite"
+	CheckAllowList    Check = "allow_list"
+	CheckDenyList     Check = "deny_list"
+	CheckCached       Check = "cached"
+	CheckDecision     Check = "decision"
+)
+
+// @Description A single step walked while evaluating a feature flag
+type TraceStep struct {
+	FlagID  uint   `json:"flag_id"`
+	Depth   int    `json:"depth"`
+	Check   Check  `json:"check"`
+	Matched bool   `json:"matched"`
+	Detail  string `json:"detail"`
+}
+
+// @Description Full evaluation configuration of every feature flag
+type Config struct {
+	Flags []*Flag `json:"flags"`
+}
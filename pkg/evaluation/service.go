@@ -0,0 +1,154 @@
+package evaluation
+
+import "fmt"
+
+type Evaluator struct {
+	flags map[uint]*Flag
+	allow map[uint]map[string]struct{}
+	deny  map[uint]map[string]struct{}
+}
+
+func NewEvaluator(flags []*Flag) *Evaluator {
+	e := &Evaluator{
+		flags: make(map[uint]*Flag, len(flags)),
+		allow: make(map[uint]map[string]struct{}),
+		deny:  make(map[uint]map[string]struct{}),
+	}
+	for _, flag := range flags {
+		e.flags[flag.ID] = flag
+		if len(flag.Allow) > 0 {
+			e.allow[flag.ID] = toSet(flag.Allow)
+		}
+		if len(flag.Deny) > 0 {
+			e.deny[flag.ID] = toSet(flag.Deny)
+		}
+	}
+	return e
+}
+
+func toSet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
+
+type run struct {
+	ctx      Context
+	results  map[uint]*Result
+	visiting map[uint]bool
+	tracing  bool
+	depth    int
+	trace    []*TraceStep
+}
+
+func newRun(ctx Context, tracing bool) *run {
+	return &run{
+		ctx:      ctx,
+		results:  map[uint]*Result{},
+		visiting: map[uint]bool{},
+		tracing:  tracing,
+	}
+}
+
+func (r *run) step(flagID uint, check Check, matched bool, format string, args ...any) {
+	if !r.tracing {
+		return
+	}
+	r.trace = append(r.trace, &TraceStep{
+		FlagID:  flagID,
+		Depth:   r.depth,
+		Check:   check,
+		Matched: matched,
+		Detail:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (e *Evaluator) Evaluate(flagID uint, ctx Context) *Result {
+	return e.evaluate(flagID, newRun(ctx, false))
+}
+
+func (e *Evaluator) EvaluateAll(ctx Context) map[uint]*Result {
+	r := newRun(ctx, false)
+	for flagID := range e.flags {
+		e.evaluate(flagID, r)
+	}
+	return r.results
+}
+
+func (e *Evaluator) Explain(flagID uint, ctx Context) (*Result, []*TraceStep) {
+	r := newRun(ctx, true)
+	result := e.evaluate(flagID, r)
+	return result, r.trace
+}
+
+func (e *Evaluator) evaluate(flagID uint, r *run) *Result {
+	if result, ok := r.results[flagID]; ok {
+		r.step(flagID, CheckCached, result.Value, "already evaluated to %s (%s)", result.Variant, result.Reason)
+		return result
+	}
+
+	flag, ok := e.flags[flagID]
+	if !ok {
+		r.step(flagID, CheckDecision, false, "flag %d is unknown", flagID)
+		return newResult(false, ReasonError)
+	}
+	if r.visiting[flagID] {
+		r.step(flagID, CheckDecision, false, "flag %d is part of a dependency cycle", flagID)
+		return newResult(false, ReasonError)
+	}
+
+	r.visiting[flagID] = true
+	defer delete(r.visiting, flagID)
+
+	result := e.evaluateFlag(flag, r)
+	r.step(flag.ID, CheckDecision, result.Value, "%q evaluates to %s (%s)", flag.Name, result.Variant, result.Reason)
+	r.results[flagID] = result
+	return result
+}
+
+func (e *Evaluator) evaluateFlag(flag *Flag, r *run) *Result {
+	r.step(flag.ID, CheckActive, flag.Active, "%q is active: %t", flag.Name, flag.Active)
+	if !flag.Active {
+		return newResult(false, ReasonOff)
+	}
+
+	for _, dependencyID := range flag.Dependencies {
+		r.depth++
+		dependency := e.evaluate(dependencyID, r)
+		r.depth--
+		r.step(flag.ID, CheckPrerequisite, dependency.Value, "prerequisite flag %d is %s", dependencyID, dependency.Variant)
+		if dependency.Reason == ReasonError {
+			return newResult(false, ReasonError)
+		}
+		if !dependency.Value {
+			return newResult(false, ReasonPrerequisiteFailed)
+		}
+	}
+
+	_, allowed := e.allow[flag.ID][r.ctx.TargetingKey]
+	r.step(flag.ID, CheckAllowList, allowed, "targeting key %q in allow list: %t", r.ctx.TargetingKey, allowed)
+	if allowed {
+		return newResult(true, ReasonTargetMatch)
+	}
+	_, denied := e.deny[flag.ID][r.ctx.TargetingKey]
+	r.step(flag.ID, CheckDenyList, denied, "targeting key %q in deny list: %t", r.ctx.TargetingKey, denied)
+	if denied {
+		return newResult(false, ReasonTargetMatch)
+	}
+
+	return newResult(true, ReasonFallthrough)
+}
+
+func newResult(value bool, reason Reason) *Result {
+	variant := VariantOff
+	if value {
+		variant = VariantOn
+	}
+	return &Result{
+		Value:   value,
+		Variant: variant,
+		Reason:  reason,
+	}
+}
@@ -0,0 +1,144 @@
+package evaluation_test
+
+import (
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Evaluator", func() {
+	Describe("Evaluate", func() {
+		DescribeTable("should return the value, variant and reason of the flag",
+			func(flags []*evaluation.Flag, flagID uint, ctx evaluation.Context, expected *evaluation.Result) {
+				evaluator := evaluation.NewEvaluator(flags)
+
+				result := evaluator.Evaluate(flagID, ctx)
+
+				Expect(result).To(Equal(expected))
+			},
+			Entry(
+				"inactive flag is off even for allowed keys",
+				[]*evaluation.Flag{
+					{ID: 1, Active: false, Allow: []string{"user-1"}},
+				},
+				uint(1),
+				evaluation.Context{TargetingKey: "user-1"},
+				&evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonOff},
+			),
+			Entry(
+				"active flag without targets falls through",
+				[]*evaluation.Flag{
+					{ID: 1, Active: true},
+				},
+				uint(1),
+				evaluation.Context{TargetingKey: "user-1"},
+				&evaluation.Result{Value: true, Variant: evaluation.VariantOn, Reason: evaluation.ReasonFallthrough},
+			),
+			Entry(
+				"key in allow list is a target match",
+				[]*evaluation.Flag{
+					{ID: 1, Active: true, Allow: []string{"user-1"}, Deny: []string{"user-2"}},
+				},
+				uint(1),
+				evaluation.Context{TargetingKey: "user-1"},
+				&evaluation.Result{Value: true, Variant: evaluation.VariantOn, Reason: evaluation.ReasonTargetMatch},
+			),
+			Entry(
+				"key in deny list is a target match",
+				[]*evaluation.Flag{
+					{ID: 1, Active: true, Allow: []string{"user-1"}, Deny: []string{"user-2"}},
+				},
+				uint(1),
+				evaluation.Context{TargetingKey: "user-2"},
+				&evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonTargetMatch},
+			),
+			Entry(
+				"prerequisite denied for the key fails the dependent",
+				[]*evaluation.Flag{
+					{ID: 1, Active: true, Deny: []string{"user-1"}},
+					{ID: 2, Active: true, Dependencies: []uint{1}, Allow: []string{"user-1"}},
+				},
+				uint(2),
+				evaluation.Context{TargetingKey: "user-1"},
+				&evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonPrerequisiteFailed},
+			),
+			Entry(
+				"transitive prerequisites are walked",
+				[]*evaluation.Flag{
+					{ID: 1, Active: false},
+					{ID: 2, Active: true, Dependencies: []uint{1}},
+					{ID: 3, Active: true, Dependencies: []uint{2}},
+				},
+				uint(3),
+				evaluation.Context{},
+				&evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonPrerequisiteFailed},
+			),
+			Entry(
+				"unknown flag is an error",
+				[]*evaluation.Flag{},
+				uint(1),
+				evaluation.Context{},
+				&evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonError},
+			),
+			Entry(
+				"circular prerequisites are an error",
+				[]*evaluation.Flag{
+					{ID: 1, Active: true, Dependencies: []uint{2}},
+					{ID: 2, Active: true, Dependencies: []uint{1}},
+				},
+				uint(1),
+				evaluation.Context{},
+				&evaluation.Result{Value: false, Variant: evaluation.VariantOff, Reason: evaluation.ReasonError},
+			),
+		)
+	})
+
+	Describe("Evaluate All", func() {
+		It("should evaluate every flag once and share prerequisite results", func() {
+			evaluator := evaluation.NewEvaluator([]*evaluation.Flag{
+				{ID: 1, Active: true, Deny: []string{"user-1"}},
+				{ID: 2, Active: true, Dependencies: []uint{1}},
+				{ID: 3, Active: false},
+			})
+
+			results := evaluator.EvaluateAll(evaluation.Context{TargetingKey: "user-1"})
+
+			Expect(results).To(HaveLen(3))
+			Expect(results[1].Reason).To(Equal(evaluation.ReasonTargetMatch))
+			Expect(results[2].Reason).To(Equal(evaluation.ReasonPrerequisiteFailed))
+			Expect(results[3].Reason).To(Equal(evaluation.ReasonOff))
+		})
+	})
+
+	Describe("Explain", func() {
+		It("should trace prerequisites, targeting lists and the final decision", func() {
+			evaluator := evaluation.NewEvaluator([]*evaluation.Flag{
+				{ID: 1, Name: "payments", Active: true},
+				{ID: 2, Name: "checkout", Active: true, Dependencies: []uint{1}, Deny: []string{"user-1"}},
+			})
+
+			result, trace := evaluator.Explain(2, evaluation.Context{TargetingKey: "user-1"})
+
+			Expect(result.Reason).To(Equal(evaluation.ReasonTargetMatch))
+			checks := make([]evaluation.Check, 0, len(trace))
+			for _, step := range trace {
+				checks = append(checks, step.Check)
+			}
+			Expect(checks).To(Equal([]evaluation.Check{
+				evaluation.CheckActive,
+				evaluation.CheckActive,
+				evaluation.CheckAllowList,
+				evaluation.CheckDenyList,
+				evaluation.CheckDecision,
+				evaluation.CheckPrerequisite,
+				evaluation.CheckAllowList,
+				evaluation.CheckDenyList,
+				evaluation.CheckDecision,
+			}))
+			Expect(trace[1].FlagID).To(Equal(uint(1)))
+			Expect(trace[1].Depth).To(Equal(1))
+			Expect(trace[3].Matched).To(BeFalse())
+			Expect(trace[7].Matched).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package evaluation_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestEvaluation(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Evaluation Suite")
+}
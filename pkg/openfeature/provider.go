@@ -0,0 +1,227 @@
+package openfeature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/client"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	ProviderName = "dom-cobb"
+
+	// PrerequisiteFailedReason has no OpenFeature equivalent, so it is kept
+	// as a custom reason.
+	PrerequisiteFailedReason of.Reason = "PREREQUISITE_FAILED"
+
+	eventBuffer = 16
+)
+
+// Provider resolves flags against dom-cobb by evaluating a locally synced
+// copy of the configuration with pkg/client. Flags are booleans with the
+// "on" and "off" variants, so string, number and object evaluations always
+// fail with TYPE_MISMATCH.
+type Provider struct {
+	baseURL string
+	opts    []client.Option
+
+	mu     sync.RWMutex
+	client *client.Client
+	config *evaluation.Config
+
+	stop   chan struct{}
+	events chan of.Event
+}
+
+func NewProvider(baseURL string, opts ...client.Option) *Provider {
+	return &Provider{
+		baseURL: baseURL,
+		opts:    opts,
+		events:  make(chan of.Event, eventBuffer),
+	}
+}
+
+func (p *Provider) Metadata() of.Metadata {
+	return of.Metadata{Name: ProviderName}
+}
+
+func (p *Provider) Hooks() []of.Hook {
+	return []of.Hook{}
+}
+
+func (p *Provider) Init(evaluationContext of.EvaluationContext) error {
+	opts := append(append([]client.Option{}, p.opts...), client.WithChangeHandler(p.onChange))
+	c, err := client.New(context.Background(), p.baseURL, opts...)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.client = c
+	p.config = c.Config()
+	p.stop = make(chan struct{})
+	return nil
+}
+
+func (p *Provider) Shutdown() {
+	p.mu.Lock()
+	c, stop := p.client, p.stop
+	p.client = nil
+	p.mu.Unlock()
+
+	if c != nil {
+		close(stop)
+		c.Close()
+	}
+}
+
+func (p *Provider) EventChannel() <-chan of.Event {
+	return p.events
+}
+
+func (p *Provider) onChange(config *evaluation.Config) {
+	p.mu.Lock()
+	previous := p.config
+	p.config = config
+	initialized, stop := p.client != nil, p.stop
+	p.mu.Unlock()
+
+	// The initial fetch happens inside Init and is reported by the SDK as
+	// PROVIDER_READY.
+	if !initialized {
+		return
+	}
+	changed := changedFlags(previous, config)
+	if len(changed) == 0 {
+		return
+	}
+
+	select {
+	case p.events <- of.Event{
+		ProviderName: ProviderName,
+		EventType:    of.ProviderConfigChange,
+		ProviderEventDetails: of.ProviderEventDetails{
+			Message:     "Feature flags configuration changed",
+			FlagChanges: changed,
+		},
+	}:
+	case <-stop:
+	}
+}
+
+func changedFlags(previous, next *evaluation.Config) []string {
+	before := make(map[string]*evaluation.Flag)
+	if previous != nil {
+		for _, flag := range previous.Flags {
+			before[flag.Name] = flag
+		}
+	}
+
+	var changed []string
+	for _, flag := range next.Flags {
+		if !reflect.DeepEqual(before[flag.Name], flag) {
+			changed = append(changed, flag.Name)
+		}
+		delete(before, flag.Name)
+	}
+	for name := range before {
+		changed = append(changed, name)
+	}
+	return changed
+}
+
+func (p *Provider) evaluate(flag string, flatCtx of.FlattenedContext) (*evaluation.Result, of.ProviderResolutionDetail) {
+	p.mu.RLock()
+	c := p.client
+	p.mu.RUnlock()
+	if c == nil {
+		return nil, errorDetail(of.NewProviderNotReadyResolutionError("provider is not initialized"))
+	}
+
+	var ctx evaluation.Context
+	if targetingKey, ok := flatCtx[of.TargetingKey]; ok {
+		key, ok := targetingKey.(string)
+		if !ok {
+			return nil, errorDetail(of.NewInvalidContextResolutionError("targeting key must be a string"))
+		}
+		ctx.TargetingKey = key
+	}
+
+	result, err := c.Evaluate(flag, ctx)
+	if errors.Is(err, client.ErrFlagNotFound) {
+		return nil, errorDetail(of.NewFlagNotFoundResolutionError(err.Error()))
+	}
+	if err != nil {
+		return nil, errorDetail(of.NewGeneralResolutionError(err.Error()))
+	}
+	if result.Reason == evaluation.ReasonError {
+		return nil, errorDetail(of.NewGeneralResolutionError(fmt.Sprintf("flag %s could not be evaluated", flag)))
+	}
+
+	return result, of.ProviderResolutionDetail{
+		Reason:  mapReason(result.Reason),
+		Variant: result.Variant,
+	}
+}
+
+func mapReason(reason evaluation.Reason) of.Reason {
+	switch reason {
+	case evaluation.ReasonOff:
+		return of.DisabledReason
+	case evaluation.ReasonTargetMatch:
+		return of.TargetingMatchReason
+	case evaluation.ReasonFallthrough:
+		return of.DefaultReason
+	case evaluation.ReasonPrerequisiteFailed:
+		return PrerequisiteFailedReason
+	default:
+		return of.UnknownReason
+	}
+}
+
+func errorDetail(resolutionError of.ResolutionError) of.ProviderResolutionDetail {
+	return of.ProviderResolutionDetail{
+		ResolutionError: resolutionError,
+		Reason:          of.ErrorReason,
+	}
+}
+
+func (p *Provider) BooleanEvaluation(ctx context.Context, flag string, defaultValue bool, flatCtx of.FlattenedContext) of.BoolResolutionDetail {
+	result, detail := p.evaluate(flag, flatCtx)
+	if result == nil {
+		return of.BoolResolutionDetail{Value: defaultValue, ProviderResolutionDetail: detail}
+	}
+	return of.BoolResolutionDetail{Value: result.Value, ProviderResolutionDetail: detail}
+}
+
+func (p *Provider) StringEvaluation(ctx context.Context, flag string, defaultValue string, flatCtx of.FlattenedContext) of.StringResolutionDetail {
+	return of.StringResolutionDetail{Value: defaultValue, ProviderResolutionDetail: p.typeMismatch(flag, flatCtx)}
+}
+
+func (p *Provider) FloatEvaluation(ctx context.Context, flag string, defaultValue float64, flatCtx of.FlattenedContext) of.FloatResolutionDetail {
+	return of.FloatResolutionDetail{Value: defaultValue, ProviderResolutionDetail: p.typeMismatch(flag, flatCtx)}
+}
+
+func (p *Provider) IntEvaluation(ctx context.Context, flag string, defaultValue int64, flatCtx of.FlattenedContext) of.IntResolutionDetail {
+	return of.IntResolutionDetail{Value: defaultValue, ProviderResolutionDetail: p.typeMismatch(flag, flatCtx)}
+}
+
+func (p *Provider) ObjectEvaluation(ctx context.Context, flag string, defaultValue any, flatCtx of.FlattenedContext) of.InterfaceResolutionDetail {
+	return of.InterfaceResolutionDetail{Value: defaultValue, ProviderResolutionDetail: p.typeMismatch(flag, flatCtx)}
+}
+
+// typeMismatch still resolves the flag so unknown flags and bad contexts are
+// reported with their own error codes.
+func (p *Provider) typeMismatch(flag string, flatCtx of.FlattenedContext) of.ProviderResolutionDetail {
+	result, detail := p.evaluate(flag, flatCtx)
+	if result == nil {
+		return detail
+	}
+	return errorDetail(of.NewTypeMismatchResolutionError(fmt.Sprintf("flag %s is a boolean flag", flag)))
+}
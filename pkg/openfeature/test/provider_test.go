@@ -0,0 +1,196 @@
+package openfeature_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/client"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	provider "github.com/ArshiAbolghasemi/dom-cobb/pkg/openfeature"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	of "github.com/open-feature/go-sdk/openfeature"
+)
+
+type fakeServer struct {
+	mu      sync.Mutex
+	config  *evaluation.Config
+	version int
+}
+
+func (f *fakeServer) setConfig(config *evaluation.Config) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.config = config
+	f.version++
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path != "/api/v1/config" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	etag := fmt.Sprintf("%q", fmt.Sprint(f.version))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": f.config})
+}
+
+func createConfig() *evaluation.Config {
+	return &evaluation.Config{
+		Flags: []*evaluation.Flag{
+			{ID: 1, Name: "checkout", Active: true, Dependencies: []uint{}, Allow: []string{"user-1"}},
+			{ID: 2, Name: "new-cart", Active: true, Dependencies: []uint{3}},
+			{ID: 3, Name: "dark-mode", Active: false, Dependencies: []uint{}},
+			{ID: 4, Name: "broken", Active: true, Dependencies: []uint{42}},
+		},
+	}
+}
+
+var _ = Describe("Provider", func() {
+	var (
+		fake   *fakeServer
+		server *httptest.Server
+		p      *provider.Provider
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		fake = &fakeServer{}
+		fake.setConfig(createConfig())
+		server = httptest.NewServer(fake)
+		p = provider.NewProvider(server.URL, client.WithPollInterval(10*time.Millisecond))
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		p.Shutdown()
+		server.Close()
+	})
+
+	When("provider is not initialized", func() {
+		It("should return the default with PROVIDER_NOT_READY", func() {
+			result := p.BooleanEvaluation(ctx, "checkout", true, of.FlattenedContext{})
+
+			Expect(result.Value).To(BeTrue())
+			Expect(result.ResolutionDetail().ErrorCode).To(Equal(of.ProviderNotReadyCode))
+		})
+	})
+
+	When("provider is initialized", func() {
+		BeforeEach(func() {
+			Expect(p.Init(of.EvaluationContext{})).To(Succeed())
+		})
+
+		DescribeTable("should map evaluation reasons",
+			func(flag, targetingKey string, value bool, variant string, reason of.Reason) {
+				flatCtx := of.FlattenedContext{of.TargetingKey: targetingKey}
+
+				result := p.BooleanEvaluation(ctx, flag, !value, flatCtx)
+				Expect(result.Value).To(Equal(value))
+				Expect(result.Reason).To(Equal(reason))
+				Expect(result.Variant).To(Equal(variant))
+				Expect(result.Error()).To(BeNil())
+			},
+			Entry("targeting list", "checkout", "user-1", true, evaluation.VariantOn, of.TargetingMatchReason),
+			Entry("fallthrough", "checkout", "user-2", true, evaluation.VariantOn, of.DefaultReason),
+			Entry("inactive flag", "dark-mode", "user-1", false, evaluation.VariantOff, of.DisabledReason),
+			Entry("failed prerequisite", "new-cart", "user-1", false, evaluation.VariantOff, provider.PrerequisiteFailedReason),
+		)
+
+		DescribeTable("should return the default with an error code",
+			func(evaluate func() (any, of.ResolutionDetail), defaultValue any, code of.ErrorCode) {
+				value, detail := evaluate()
+
+				Expect(value).To(Equal(defaultValue))
+				Expect(detail.Reason).To(Equal(of.ErrorReason))
+				Expect(detail.ErrorCode).To(Equal(code))
+			},
+			Entry("unknown flag", func() (any, of.ResolutionDetail) {
+				result := p.BooleanEvaluation(ctx, "unknown", true, of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, true, of.FlagNotFoundCode),
+			Entry("invalid targeting key", func() (any, of.ResolutionDetail) {
+				result := p.BooleanEvaluation(ctx, "checkout", false, of.FlattenedContext{of.TargetingKey: 42})
+				return result.Value, result.ResolutionDetail()
+			}, false, of.InvalidContextCode),
+			Entry("evaluation error", func() (any, of.ResolutionDetail) {
+				result := p.BooleanEvaluation(ctx, "broken", false, of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, false, of.GeneralCode),
+			Entry("string flag", func() (any, of.ResolutionDetail) {
+				result := p.StringEvaluation(ctx, "checkout", "fallback", of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, "fallback", of.TypeMismatchCode),
+			Entry("number flag", func() (any, of.ResolutionDetail) {
+				result := p.IntEvaluation(ctx, "checkout", 7, of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, int64(7), of.TypeMismatchCode),
+			Entry("float flag", func() (any, of.ResolutionDetail) {
+				result := p.FloatEvaluation(ctx, "checkout", 1.5, of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, 1.5, of.TypeMismatchCode),
+			Entry("object flag", func() (any, of.ResolutionDetail) {
+				result := p.ObjectEvaluation(ctx, "checkout", "fallback", of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, "fallback", of.TypeMismatchCode),
+			Entry("number evaluation of an unknown flag", func() (any, of.ResolutionDetail) {
+				result := p.IntEvaluation(ctx, "unknown", 7, of.FlattenedContext{})
+				return result.Value, result.ResolutionDetail()
+			}, int64(7), of.FlagNotFoundCode),
+		)
+
+		It("should emit a configuration change event with the changed flags", func() {
+			config := createConfig()
+			config.Flags[2].Active = true
+			fake.setConfig(config)
+
+			var event of.Event
+			Eventually(p.EventChannel()).Should(Receive(&event))
+			Expect(event.EventType).To(Equal(of.ProviderConfigChange))
+			Expect(event.FlagChanges).To(Equal([]string{"dark-mode"}))
+		})
+	})
+
+	Describe("OpenFeature SDK", func() {
+		It("should resolve flags and forward configuration changes", func() {
+			domain := "dom-cobb-test"
+			Expect(of.SetNamedProviderAndWait(domain, p)).To(Succeed())
+			c := of.NewClient(domain)
+
+			changes := make(chan of.EventDetails, 1)
+			callback := func(details of.EventDetails) {
+				select {
+				case changes <- details:
+				default:
+				}
+			}
+			c.AddHandler(of.ProviderConfigChange, &callback)
+
+			details, err := c.BooleanValueDetails(ctx, "checkout", false, of.NewEvaluationContext("user-1", nil))
+			Expect(err).To(BeNil())
+			Expect(details.Value).To(BeTrue())
+			Expect(details.Reason).To(Equal(of.TargetingMatchReason))
+
+			config := createConfig()
+			config.Flags[0].Allow = nil
+			fake.setConfig(config)
+
+			var change of.EventDetails
+			Eventually(changes).Should(Receive(&change))
+			Expect(change.FlagChanges).To(Equal([]string{"checkout"}))
+		})
+	})
+})
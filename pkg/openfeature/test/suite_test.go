@@ -0,0 +1,13 @@
+package openfeature_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestOpenFeature(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "OpenFeature Suite")
+}
@@ -0,0 +1,1772 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: domcobb/v1/flags.proto
+
+package domcobbv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Flag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Active        bool                   `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`
+	Dependencies  []uint32               `protobuf:"varint,4,rep,packed,name=dependencies,proto3" json:"dependencies,omitempty"`
+	Dependents    []uint32               `protobuf:"varint,5,rep,packed,name=dependents,proto3" json:"dependents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Flag) Reset() {
+	*x = Flag{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flag) ProtoMessage() {}
+
+func (x *Flag) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flag.ProtoReflect.Descriptor instead.
+func (*Flag) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Flag) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Flag) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Flag) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Flag) GetDependencies() []uint32 {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *Flag) GetDependents() []uint32 {
+	if x != nil {
+		return x.Dependents
+	}
+	return nil
+}
+
+type Pagination struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          uint32                 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Size          uint32                 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Total         uint32                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	TotalPages    uint32                 `protobuf:"varint,4,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Pagination) Reset() {
+	*x = Pagination{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Pagination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Pagination) ProtoMessage() {}
+
+func (x *Pagination) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Pagination.ProtoReflect.Descriptor instead.
+func (*Pagination) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Pagination) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *Pagination) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Pagination) GetTotalPages() uint32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+type CreateFlagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Dependencies  []uint32               `protobuf:"varint,3,rep,packed,name=dependencies,proto3" json:"dependencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFlagRequest) Reset() {
+	*x = CreateFlagRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlagRequest) ProtoMessage() {}
+
+func (x *CreateFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlagRequest.ProtoReflect.Descriptor instead.
+func (*CreateFlagRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateFlagRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateFlagRequest) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *CreateFlagRequest) GetDependencies() []uint32 {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+type CreateFlagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flag          *Flag                  `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateFlagResponse) Reset() {
+	*x = CreateFlagResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateFlagResponse) ProtoMessage() {}
+
+func (x *CreateFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateFlagResponse.ProtoReflect.Descriptor instead.
+func (*CreateFlagResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateFlagResponse) GetFlag() *Flag {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+type GetFlagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFlagRequest) Reset() {
+	*x = GetFlagRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagRequest) ProtoMessage() {}
+
+func (x *GetFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagRequest.ProtoReflect.Descriptor instead.
+func (*GetFlagRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetFlagRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetFlagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flag          *Flag                  `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFlagResponse) Reset() {
+	*x = GetFlagResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagResponse) ProtoMessage() {}
+
+func (x *GetFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagResponse.ProtoReflect.Descriptor instead.
+func (*GetFlagResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetFlagResponse) GetFlag() *Flag {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+type ListFlagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Page number, defaults to 1.
+	Page uint32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	// Number of flags per page, defaults to 10 and is capped at 20.
+	Size          uint32 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFlagsRequest) Reset() {
+	*x = ListFlagsRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlagsRequest) ProtoMessage() {}
+
+func (x *ListFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlagsRequest.ProtoReflect.Descriptor instead.
+func (*ListFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListFlagsRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListFlagsRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type ListFlagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flags         []*Flag                `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListFlagsResponse) Reset() {
+	*x = ListFlagsResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListFlagsResponse) ProtoMessage() {}
+
+func (x *ListFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListFlagsResponse.ProtoReflect.Descriptor instead.
+func (*ListFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListFlagsResponse) GetFlags() []*Flag {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+func (x *ListFlagsResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type ToggleFlagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToggleFlagRequest) Reset() {
+	*x = ToggleFlagRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToggleFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToggleFlagRequest) ProtoMessage() {}
+
+func (x *ToggleFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToggleFlagRequest.ProtoReflect.Descriptor instead.
+func (*ToggleFlagRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ToggleFlagRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ToggleFlagRequest) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *ToggleFlagRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ToggleFlagResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Flag  *Flag                  `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+	// Set when the flag already had the requested state and nothing changed.
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToggleFlagResponse) Reset() {
+	*x = ToggleFlagResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToggleFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToggleFlagResponse) ProtoMessage() {}
+
+func (x *ToggleFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToggleFlagResponse.ProtoReflect.Descriptor instead.
+func (*ToggleFlagResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ToggleFlagResponse) GetFlag() *Flag {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+func (x *ToggleFlagResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UpdateFlagDependenciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Dependencies  []uint32               `protobuf:"varint,2,rep,packed,name=dependencies,proto3" json:"dependencies,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFlagDependenciesRequest) Reset() {
+	*x = UpdateFlagDependenciesRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFlagDependenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagDependenciesRequest) ProtoMessage() {}
+
+func (x *UpdateFlagDependenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagDependenciesRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFlagDependenciesRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateFlagDependenciesRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateFlagDependenciesRequest) GetDependencies() []uint32 {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+func (x *UpdateFlagDependenciesRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type UpdateFlagDependenciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flag          *Flag                  `protobuf:"bytes,1,opt,name=flag,proto3" json:"flag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFlagDependenciesResponse) Reset() {
+	*x = UpdateFlagDependenciesResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFlagDependenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFlagDependenciesResponse) ProtoMessage() {}
+
+func (x *UpdateFlagDependenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFlagDependenciesResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFlagDependenciesResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateFlagDependenciesResponse) GetFlag() *Flag {
+	if x != nil {
+		return x.Flag
+	}
+	return nil
+}
+
+type GetFlagLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Page number, defaults to 1.
+	Page uint32 `protobuf:"varint,2,opt,name=page,proto3" json:"page,omitempty"`
+	// Number of logs per page, defaults to 10 and is capped at 20.
+	Size          uint32 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFlagLogsRequest) Reset() {
+	*x = GetFlagLogsRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFlagLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagLogsRequest) ProtoMessage() {}
+
+func (x *GetFlagLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagLogsRequest.ProtoReflect.Descriptor instead.
+func (*GetFlagLogsRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetFlagLogsRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetFlagLogsRequest) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *GetFlagLogsRequest) GetSize() uint32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type FlagState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Active        bool                   `protobuf:"varint,2,opt,name=active,proto3" json:"active,omitempty"`
+	Dependencies  []uint32               `protobuf:"varint,3,rep,packed,name=dependencies,proto3" json:"dependencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlagState) Reset() {
+	*x = FlagState{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlagState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlagState) ProtoMessage() {}
+
+func (x *FlagState) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlagState.ProtoReflect.Descriptor instead.
+func (*FlagState) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FlagState) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *FlagState) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *FlagState) GetDependencies() []uint32 {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+type LogEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Message   string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Action specific details; entries written before typed events keep all
+	// their fields here.
+	Metadata      *structpb.Struct `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	SchemaVersion uint32           `protobuf:"varint,4,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Action        string           `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	EntityType    string           `protobuf:"bytes,6,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	EntityId      uint32           `protobuf:"varint,7,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	Actor         string           `protobuf:"bytes,8,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string           `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"`
+	RequestId     string           `protobuf:"bytes,10,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Flag whose deactivation auto-disabled the entity, 0 for direct changes.
+	CausedBy uint32     `protobuf:"varint,11,opt,name=caused_by,json=causedBy,proto3" json:"caused_by,omitempty"`
+	Before   *FlagState `protobuf:"bytes,12,opt,name=before,proto3" json:"before,omitempty"`
+	After    *FlagState `protobuf:"bytes,13,opt,name=after,proto3" json:"after,omitempty"`
+	// Position in the audit log hash chain, 0 for entries written before it.
+	Sequence      uint64 `protobuf:"varint,14,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	PrevHash      string `protobuf:"bytes,15,opt,name=prev_hash,json=prevHash,proto3" json:"prev_hash,omitempty"`
+	Hash          string `protobuf:"bytes,16,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *LogEntry) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *LogEntry) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *LogEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *LogEntry) GetEntityType() string {
+	if x != nil {
+		return x.EntityType
+	}
+	return ""
+}
+
+func (x *LogEntry) GetEntityId() uint32 {
+	if x != nil {
+		return x.EntityId
+	}
+	return 0
+}
+
+func (x *LogEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *LogEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *LogEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *LogEntry) GetCausedBy() uint32 {
+	if x != nil {
+		return x.CausedBy
+	}
+	return 0
+}
+
+func (x *LogEntry) GetBefore() *FlagState {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *LogEntry) GetAfter() *FlagState {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *LogEntry) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *LogEntry) GetPrevHash() string {
+	if x != nil {
+		return x.PrevHash
+	}
+	return ""
+}
+
+func (x *LogEntry) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type GetFlagLogsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Logs          []*LogEntry            `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	Pagination    *Pagination            `protobuf:"bytes,2,opt,name=pagination,proto3" json:"pagination,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFlagLogsResponse) Reset() {
+	*x = GetFlagLogsResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFlagLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFlagLogsResponse) ProtoMessage() {}
+
+func (x *GetFlagLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFlagLogsResponse.ProtoReflect.Descriptor instead.
+func (*GetFlagLogsResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetFlagLogsResponse) GetLogs() []*LogEntry {
+	if x != nil {
+		return x.Logs
+	}
+	return nil
+}
+
+func (x *GetFlagLogsResponse) GetPagination() *Pagination {
+	if x != nil {
+		return x.Pagination
+	}
+	return nil
+}
+
+type EvaluationContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetingKey  string                 `protobuf:"bytes,1,opt,name=targeting_key,json=targetingKey,proto3" json:"targeting_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluationContext) Reset() {
+	*x = EvaluationContext{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluationContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluationContext) ProtoMessage() {}
+
+func (x *EvaluationContext) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluationContext.ProtoReflect.Descriptor instead.
+func (*EvaluationContext) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EvaluationContext) GetTargetingKey() string {
+	if x != nil {
+		return x.TargetingKey
+	}
+	return ""
+}
+
+type EvaluationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         bool                   `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Variant       string                 `protobuf:"bytes,2,opt,name=variant,proto3" json:"variant,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluationResult) Reset() {
+	*x = EvaluationResult{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluationResult) ProtoMessage() {}
+
+func (x *EvaluationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluationResult.ProtoReflect.Descriptor instead.
+func (*EvaluationResult) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *EvaluationResult) GetValue() bool {
+	if x != nil {
+		return x.Value
+	}
+	return false
+}
+
+func (x *EvaluationResult) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *EvaluationResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type EvaluateFlagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Context       *EvaluationContext     `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateFlagRequest) Reset() {
+	*x = EvaluateFlagRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateFlagRequest) ProtoMessage() {}
+
+func (x *EvaluateFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateFlagRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateFlagRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *EvaluateFlagRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EvaluateFlagRequest) GetContext() *EvaluationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type EvaluateFlagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Result        *EvaluationResult      `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateFlagResponse) Reset() {
+	*x = EvaluateFlagResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateFlagResponse) ProtoMessage() {}
+
+func (x *EvaluateFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateFlagResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateFlagResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *EvaluateFlagResponse) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *EvaluateFlagResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EvaluateFlagResponse) GetResult() *EvaluationResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type TraceStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FlagId        uint32                 `protobuf:"varint,1,opt,name=flag_id,json=flagId,proto3" json:"flag_id,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	Check         string                 `protobuf:"bytes,3,opt,name=check,proto3" json:"check,omitempty"`
+	Matched       bool                   `protobuf:"varint,4,opt,name=matched,proto3" json:"matched,omitempty"`
+	Detail        string                 `protobuf:"bytes,5,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TraceStep) Reset() {
+	*x = TraceStep{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TraceStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceStep) ProtoMessage() {}
+
+func (x *TraceStep) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceStep.ProtoReflect.Descriptor instead.
+func (*TraceStep) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TraceStep) GetFlagId() uint32 {
+	if x != nil {
+		return x.FlagId
+	}
+	return 0
+}
+
+func (x *TraceStep) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *TraceStep) GetCheck() string {
+	if x != nil {
+		return x.Check
+	}
+	return ""
+}
+
+func (x *TraceStep) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *TraceStep) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type ExplainFlagRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Context       *EvaluationContext     `protobuf:"bytes,2,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainFlagRequest) Reset() {
+	*x = ExplainFlagRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainFlagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainFlagRequest) ProtoMessage() {}
+
+func (x *ExplainFlagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainFlagRequest.ProtoReflect.Descriptor instead.
+func (*ExplainFlagRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ExplainFlagRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExplainFlagRequest) GetContext() *EvaluationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type ExplainFlagResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Result        *EvaluationResult      `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	Trace         []*TraceStep           `protobuf:"bytes,4,rep,name=trace,proto3" json:"trace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExplainFlagResponse) Reset() {
+	*x = ExplainFlagResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExplainFlagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainFlagResponse) ProtoMessage() {}
+
+func (x *ExplainFlagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainFlagResponse.ProtoReflect.Descriptor instead.
+func (*ExplainFlagResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ExplainFlagResponse) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ExplainFlagResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ExplainFlagResponse) GetResult() *EvaluationResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ExplainFlagResponse) GetTrace() []*TraceStep {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+type EvaluateAllFlagsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       *EvaluationContext     `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateAllFlagsRequest) Reset() {
+	*x = EvaluateAllFlagsRequest{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateAllFlagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateAllFlagsRequest) ProtoMessage() {}
+
+func (x *EvaluateAllFlagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateAllFlagsRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateAllFlagsRequest) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *EvaluateAllFlagsRequest) GetContext() *EvaluationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type EvaluateAllFlagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Evaluation results keyed by flag name.
+	Flags         map[string]*EvaluationResult `protobuf:"bytes,1,rep,name=flags,proto3" json:"flags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateAllFlagsResponse) Reset() {
+	*x = EvaluateAllFlagsResponse{}
+	mi := &file_domcobb_v1_flags_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateAllFlagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateAllFlagsResponse) ProtoMessage() {}
+
+func (x *EvaluateAllFlagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_domcobb_v1_flags_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateAllFlagsResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateAllFlagsResponse) Descriptor() ([]byte, []int) {
+	return file_domcobb_v1_flags_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *EvaluateAllFlagsResponse) GetFlags() map[string]*EvaluationResult {
+	if x != nil {
+		return x.Flags
+	}
+	return nil
+}
+
+var File_domcobb_v1_flags_proto protoreflect.FileDescriptor
+
+const file_domcobb_v1_flags_proto_rawDesc = "" +
+	"\n" +
+	"\x16domcobb/v1/flags.proto\x12\n" +
+	"domcobb.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x86\x01\n" +
+	"\x04Flag\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06active\x18\x03 \x01(\bR\x06active\x12\"\n" +
+	"\fdependencies\x18\x04 \x03(\rR\fdependencies\x12\x1e\n" +
+	"\n" +
+	"dependents\x18\x05 \x03(\rR\n" +
+	"dependents\"k\n" +
+	"\n" +
+	"Pagination\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\rR\x04size\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\rR\x05total\x12\x1f\n" +
+	"\vtotal_pages\x18\x04 \x01(\rR\n" +
+	"totalPages\"c\n" +
+	"\x11CreateFlagRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\"\n" +
+	"\fdependencies\x18\x03 \x03(\rR\fdependencies\":\n" +
+	"\x12CreateFlagResponse\x12$\n" +
+	"\x04flag\x18\x01 \x01(\v2\x10.domcobb.v1.FlagR\x04flag\" \n" +
+	"\x0eGetFlagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"7\n" +
+	"\x0fGetFlagResponse\x12$\n" +
+	"\x04flag\x18\x01 \x01(\v2\x10.domcobb.v1.FlagR\x04flag\":\n" +
+	"\x10ListFlagsRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\rR\x04page\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\rR\x04size\"s\n" +
+	"\x11ListFlagsResponse\x12&\n" +
+	"\x05flags\x18\x01 \x03(\v2\x10.domcobb.v1.FlagR\x05flags\x126\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x16.domcobb.v1.PaginationR\n" +
+	"pagination\"S\n" +
+	"\x11ToggleFlagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"T\n" +
+	"\x12ToggleFlagResponse\x12$\n" +
+	"\x04flag\x18\x01 \x01(\v2\x10.domcobb.v1.FlagR\x04flag\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"k\n" +
+	"\x1dUpdateFlagDependenciesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\"\n" +
+	"\fdependencies\x18\x02 \x03(\rR\fdependencies\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"F\n" +
+	"\x1eUpdateFlagDependenciesResponse\x12$\n" +
+	"\x04flag\x18\x01 \x01(\v2\x10.domcobb.v1.FlagR\x04flag\"L\n" +
+	"\x12GetFlagLogsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04page\x18\x02 \x01(\rR\x04page\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\rR\x04size\"[\n" +
+	"\tFlagState\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06active\x18\x02 \x01(\bR\x06active\x12\"\n" +
+	"\fdependencies\x18\x03 \x03(\rR\fdependencies\"\xa3\x04\n" +
+	"\bLogEntry\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x123\n" +
+	"\bmetadata\x18\x03 \x01(\v2\x17.google.protobuf.StructR\bmetadata\x12%\n" +
+	"\x0eschema_version\x18\x04 \x01(\rR\rschemaVersion\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\x12\x1f\n" +
+	"\ventity_type\x18\x06 \x01(\tR\n" +
+	"entityType\x12\x1b\n" +
+	"\tentity_id\x18\a \x01(\rR\bentityId\x12\x14\n" +
+	"\x05actor\x18\b \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\n" +
+	" \x01(\tR\trequestId\x12\x1b\n" +
+	"\tcaused_by\x18\v \x01(\rR\bcausedBy\x12-\n" +
+	"\x06before\x18\f \x01(\v2\x15.domcobb.v1.FlagStateR\x06before\x12+\n" +
+	"\x05after\x18\r \x01(\v2\x15.domcobb.v1.FlagStateR\x05after\x12\x1a\n" +
+	"\bsequence\x18\x0e \x01(\x04R\bsequence\x12\x1b\n" +
+	"\tprev_hash\x18\x0f \x01(\tR\bprevHash\x12\x12\n" +
+	"\x04hash\x18\x10 \x01(\tR\x04hash\"w\n" +
+	"\x13GetFlagLogsResponse\x12(\n" +
+	"\x04logs\x18\x01 \x03(\v2\x14.domcobb.v1.LogEntryR\x04logs\x126\n" +
+	"\n" +
+	"pagination\x18\x02 \x01(\v2\x16.domcobb.v1.PaginationR\n" +
+	"pagination\"8\n" +
+	"\x11EvaluationContext\x12#\n" +
+	"\rtargeting_key\x18\x01 \x01(\tR\ftargetingKey\"Z\n" +
+	"\x10EvaluationResult\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\bR\x05value\x12\x18\n" +
+	"\avariant\x18\x02 \x01(\tR\avariant\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"^\n" +
+	"\x13EvaluateFlagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x127\n" +
+	"\acontext\x18\x02 \x01(\v2\x1d.domcobb.v1.EvaluationContextR\acontext\"p\n" +
+	"\x14EvaluateFlagResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
+	"\x06result\x18\x03 \x01(\v2\x1c.domcobb.v1.EvaluationResultR\x06result\"\x82\x01\n" +
+	"\tTraceStep\x12\x17\n" +
+	"\aflag_id\x18\x01 \x01(\rR\x06flagId\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\x12\x14\n" +
+	"\x05check\x18\x03 \x01(\tR\x05check\x12\x18\n" +
+	"\amatched\x18\x04 \x01(\bR\amatched\x12\x16\n" +
+	"\x06detail\x18\x05 \x01(\tR\x06detail\"]\n" +
+	"\x12ExplainFlagRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x127\n" +
+	"\acontext\x18\x02 \x01(\v2\x1d.domcobb.v1.EvaluationContextR\acontext\"\x9c\x01\n" +
+	"\x13ExplainFlagResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x124\n" +
+	"\x06result\x18\x03 \x01(\v2\x1c.domcobb.v1.EvaluationResultR\x06result\x12+\n" +
+	"\x05trace\x18\x04 \x03(\v2\x15.domcobb.v1.TraceStepR\x05trace\"R\n" +
+	"\x17EvaluateAllFlagsRequest\x127\n" +
+	"\acontext\x18\x01 \x01(\v2\x1d.domcobb.v1.EvaluationContextR\acontext\"\xb9\x01\n" +
+	"\x18EvaluateAllFlagsResponse\x12E\n" +
+	"\x05flags\x18\x01 \x03(\v2/.domcobb.v1.EvaluateAllFlagsResponse.FlagsEntryR\x05flags\x1aV\n" +
+	"\n" +
+	"FlagsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
+	"\x05value\x18\x02 \x01(\v2\x1c.domcobb.v1.EvaluationResultR\x05value:\x028\x012\xf8\x05\n" +
+	"\vFlagService\x12K\n" +
+	"\n" +
+	"CreateFlag\x12\x1d.domcobb.v1.CreateFlagRequest\x1a\x1e.domcobb.v1.CreateFlagResponse\x12B\n" +
+	"\aGetFlag\x12\x1a.domcobb.v1.GetFlagRequest\x1a\x1b.domcobb.v1.GetFlagResponse\x12H\n" +
+	"\tListFlags\x12\x1c.domcobb.v1.ListFlagsRequest\x1a\x1d.domcobb.v1.ListFlagsResponse\x12K\n" +
+	"\n" +
+	"ToggleFlag\x12\x1d.domcobb.v1.ToggleFlagRequest\x1a\x1e.domcobb.v1.ToggleFlagResponse\x12o\n" +
+	"\x16UpdateFlagDependencies\x12).domcobb.v1.UpdateFlagDependenciesRequest\x1a*.domcobb.v1.UpdateFlagDependenciesResponse\x12N\n" +
+	"\vGetFlagLogs\x12\x1e.domcobb.v1.GetFlagLogsRequest\x1a\x1f.domcobb.v1.GetFlagLogsResponse\x12Q\n" +
+	"\fEvaluateFlag\x12\x1f.domcobb.v1.EvaluateFlagRequest\x1a .domcobb.v1.EvaluateFlagResponse\x12N\n" +
+	"\vExplainFlag\x12\x1e.domcobb.v1.ExplainFlagRequest\x1a\x1f.domcobb.v1.ExplainFlagResponse\x12]\n" +
+	"\x10EvaluateAllFlags\x12#.domcobb.v1.EvaluateAllFlagsRequest\x1a$.domcobb.v1.EvaluateAllFlagsResponseBEZCgithub.com/ArshiAbolghasemi/dom-cobb/pkg/proto/domcobb/v1;domcobbv1b\x06proto3"
+
+var (
+	file_domcobb_v1_flags_proto_rawDescOnce sync.Once
+	file_domcobb_v1_flags_proto_rawDescData []byte
+)
+
+func file_domcobb_v1_flags_proto_rawDescGZIP() []byte {
+	file_domcobb_v1_flags_proto_rawDescOnce.Do(func() {
+		file_domcobb_v1_flags_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_domcobb_v1_flags_proto_rawDesc), len(file_domcobb_v1_flags_proto_rawDesc)))
+	})
+	return file_domcobb_v1_flags_proto_rawDescData
+}
+
+var file_domcobb_v1_flags_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
+var file_domcobb_v1_flags_proto_goTypes = []any{
+	(*Flag)(nil),                           // 0: domcobb.v1.Flag
+	(*Pagination)(nil),                     // 1: domcobb.v1.Pagination
+	(*CreateFlagRequest)(nil),              // 2: domcobb.v1.CreateFlagRequest
+	(*CreateFlagResponse)(nil),             // 3: domcobb.v1.CreateFlagResponse
+	(*GetFlagRequest)(nil),                 // 4: domcobb.v1.GetFlagRequest
+	(*GetFlagResponse)(nil),                // 5: domcobb.v1.GetFlagResponse
+	(*ListFlagsRequest)(nil),               // 6: domcobb.v1.ListFlagsRequest
+	(*ListFlagsResponse)(nil),              // 7: domcobb.v1.ListFlagsResponse
+	(*ToggleFlagRequest)(nil),              // 8: domcobb.v1.ToggleFlagRequest
+	(*ToggleFlagResponse)(nil),             // 9: domcobb.v1.ToggleFlagResponse
+	(*UpdateFlagDependenciesRequest)(nil),  // 10: domcobb.v1.UpdateFlagDependenciesRequest
+	(*UpdateFlagDependenciesResponse)(nil), // 11: domcobb.v1.UpdateFlagDependenciesResponse
+	(*GetFlagLogsRequest)(nil),             // 12: domcobb.v1.GetFlagLogsRequest
+	(*FlagState)(nil),                      // 13: domcobb.v1.FlagState
+	(*LogEntry)(nil),                       // 14: domcobb.v1.LogEntry
+	(*GetFlagLogsResponse)(nil),            // 15: domcobb.v1.GetFlagLogsResponse
+	(*EvaluationContext)(nil),              // 16: domcobb.v1.EvaluationContext
+	(*EvaluationResult)(nil),               // 17: domcobb.v1.EvaluationResult
+	(*EvaluateFlagRequest)(nil),            // 18: domcobb.v1.EvaluateFlagRequest
+	(*EvaluateFlagResponse)(nil),           // 19: domcobb.v1.EvaluateFlagResponse
+	(*TraceStep)(nil),                      // 20: domcobb.v1.TraceStep
+	(*ExplainFlagRequest)(nil),             // 21: domcobb.v1.ExplainFlagRequest
+	(*ExplainFlagResponse)(nil),            // 22: domcobb.v1.ExplainFlagResponse
+	(*EvaluateAllFlagsRequest)(nil),        // 23: domcobb.v1.EvaluateAllFlagsRequest
+	(*EvaluateAllFlagsResponse)(nil),       // 24: domcobb.v1.EvaluateAllFlagsResponse
+	nil,                                    // 25: domcobb.v1.EvaluateAllFlagsResponse.FlagsEntry
+	(*timestamppb.Timestamp)(nil),          // 26: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),                // 27: google.protobuf.Struct
+}
+var file_domcobb_v1_flags_proto_depIdxs = []int32{
+	0,  // 0: domcobb.v1.CreateFlagResponse.flag:type_name -> domcobb.v1.Flag
+	0,  // 1: domcobb.v1.GetFlagResponse.flag:type_name -> domcobb.v1.Flag
+	0,  // 2: domcobb.v1.ListFlagsResponse.flags:type_name -> domcobb.v1.Flag
+	1,  // 3: domcobb.v1.ListFlagsResponse.pagination:type_name -> domcobb.v1.Pagination
+	0,  // 4: domcobb.v1.ToggleFlagResponse.flag:type_name -> domcobb.v1.Flag
+	0,  // 5: domcobb.v1.UpdateFlagDependenciesResponse.flag:type_name -> domcobb.v1.Flag
+	26, // 6: domcobb.v1.LogEntry.timestamp:type_name -> google.protobuf.Timestamp
+	27, // 7: domcobb.v1.LogEntry.metadata:type_name -> google.protobuf.Struct
+	13, // 8: domcobb.v1.LogEntry.before:type_name -> domcobb.v1.FlagState
+	13, // 9: domcobb.v1.LogEntry.after:type_name -> domcobb.v1.FlagState
+	14, // 10: domcobb.v1.GetFlagLogsResponse.logs:type_name -> domcobb.v1.LogEntry
+	1,  // 11: domcobb.v1.GetFlagLogsResponse.pagination:type_name -> domcobb.v1.Pagination
+	16, // 12: domcobb.v1.EvaluateFlagRequest.context:type_name -> domcobb.v1.EvaluationContext
+	17, // 13: domcobb.v1.EvaluateFlagResponse.result:type_name -> domcobb.v1.EvaluationResult
+	16, // 14: domcobb.v1.ExplainFlagRequest.context:type_name -> domcobb.v1.EvaluationContext
+	17, // 15: domcobb.v1.ExplainFlagResponse.result:type_name -> domcobb.v1.EvaluationResult
+	20, // 16: domcobb.v1.ExplainFlagResponse.trace:type_name -> domcobb.v1.TraceStep
+	16, // 17: domcobb.v1.EvaluateAllFlagsRequest.context:type_name -> domcobb.v1.EvaluationContext
+	25, // 18: domcobb.v1.EvaluateAllFlagsResponse.flags:type_name -> domcobb.v1.EvaluateAllFlagsResponse.FlagsEntry
+	17, // 19: domcobb.v1.EvaluateAllFlagsResponse.FlagsEntry.value:type_name -> domcobb.v1.EvaluationResult
+	2,  // 20: domcobb.v1.FlagService.CreateFlag:input_type -> domcobb.v1.CreateFlagRequest
+	4,  // 21: domcobb.v1.FlagService.GetFlag:input_type -> domcobb.v1.GetFlagRequest
+	6,  // 22: domcobb.v1.FlagService.ListFlags:input_type -> domcobb.v1.ListFlagsRequest
+	8,  // 23: domcobb.v1.FlagService.ToggleFlag:input_type -> domcobb.v1.ToggleFlagRequest
+	10, // 24: domcobb.v1.FlagService.UpdateFlagDependencies:input_type -> domcobb.v1.UpdateFlagDependenciesRequest
+	12, // 25: domcobb.v1.FlagService.GetFlagLogs:input_type -> domcobb.v1.GetFlagLogsRequest
+	18, // 26: domcobb.v1.FlagService.EvaluateFlag:input_type -> domcobb.v1.EvaluateFlagRequest
+	21, // 27: domcobb.v1.FlagService.ExplainFlag:input_type -> domcobb.v1.ExplainFlagRequest
+	23, // 28: domcobb.v1.FlagService.EvaluateAllFlags:input_type -> domcobb.v1.EvaluateAllFlagsRequest
+	3,  // 29: domcobb.v1.FlagService.CreateFlag:output_type -> domcobb.v1.CreateFlagResponse
+	5,  // 30: domcobb.v1.FlagService.GetFlag:output_type -> domcobb.v1.GetFlagResponse
+	7,  // 31: domcobb.v1.FlagService.ListFlags:output_type -> domcobb.v1.ListFlagsResponse
+	9,  // 32: domcobb.v1.FlagService.ToggleFlag:output_type -> domcobb.v1.ToggleFlagResponse
+	11, // 33: domcobb.v1.FlagService.UpdateFlagDependencies:output_type -> domcobb.v1.UpdateFlagDependenciesResponse
+	15, // 34: domcobb.v1.FlagService.GetFlagLogs:output_type -> domcobb.v1.GetFlagLogsResponse
+	19, // 35: domcobb.v1.FlagService.EvaluateFlag:output_type -> domcobb.v1.EvaluateFlagResponse
+	22, // 36: domcobb.v1.FlagService.ExplainFlag:output_type -> domcobb.v1.ExplainFlagResponse
+	24, // 37: domcobb.v1.FlagService.EvaluateAllFlags:output_type -> domcobb.v1.EvaluateAllFlagsResponse
+	29, // [29:38] is the sub-list for method output_type
+	20, // [20:29] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_domcobb_v1_flags_proto_init() }
+func file_domcobb_v1_flags_proto_init() {
+	if File_domcobb_v1_flags_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_domcobb_v1_flags_proto_rawDesc), len(file_domcobb_v1_flags_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   26,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_domcobb_v1_flags_proto_goTypes,
+		DependencyIndexes: file_domcobb_v1_flags_proto_depIdxs,
+		MessageInfos:      file_domcobb_v1_flags_proto_msgTypes,
+	}.Build()
+	File_domcobb_v1_flags_proto = out.File
+	file_domcobb_v1_flags_proto_goTypes = nil
+	file_domcobb_v1_flags_proto_depIdxs = nil
+}
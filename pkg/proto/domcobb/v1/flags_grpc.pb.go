@@ -0,0 +1,431 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             (unknown)
+// source: domcobb/v1/flags.proto
+
+package domcobbv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FlagService_CreateFlag_FullMethodName             = "/domcobb.v1.FlagService/CreateFlag"
+	FlagService_GetFlag_FullMethodName                = "/domcobb.v1.FlagService/GetFlag"
+	FlagService_ListFlags_FullMethodName              = "/domcobb.v1.FlagService/ListFlags"
+	FlagService_ToggleFlag_FullMethodName             = "/domcobb.v1.FlagService/ToggleFlag"
+	FlagService_UpdateFlagDependencies_FullMethodName = "/domcobb.v1.FlagService/UpdateFlagDependencies"
+	FlagService_GetFlagLogs_FullMethodName            = "/domcobb.v1.FlagService/GetFlagLogs"
+	FlagService_EvaluateFlag_FullMethodName           = "/domcobb.v1.FlagService/EvaluateFlag"
+	FlagService_ExplainFlag_FullMethodName            = "/domcobb.v1.FlagService/ExplainFlag"
+	FlagService_EvaluateAllFlags_FullMethodName       = "/domcobb.v1.FlagService/EvaluateAllFlags"
+)
+
+// FlagServiceClient is the client API for FlagService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FlagService exposes feature flag management and evaluation over gRPC. It is
+// backed by the same service layer as the REST API.
+type FlagServiceClient interface {
+	CreateFlag(ctx context.Context, in *CreateFlagRequest, opts ...grpc.CallOption) (*CreateFlagResponse, error)
+	GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error)
+	ListFlags(ctx context.Context, in *ListFlagsRequest, opts ...grpc.CallOption) (*ListFlagsResponse, error)
+	ToggleFlag(ctx context.Context, in *ToggleFlagRequest, opts ...grpc.CallOption) (*ToggleFlagResponse, error)
+	UpdateFlagDependencies(ctx context.Context, in *UpdateFlagDependenciesRequest, opts ...grpc.CallOption) (*UpdateFlagDependenciesResponse, error)
+	GetFlagLogs(ctx context.Context, in *GetFlagLogsRequest, opts ...grpc.CallOption) (*GetFlagLogsResponse, error)
+	EvaluateFlag(ctx context.Context, in *EvaluateFlagRequest, opts ...grpc.CallOption) (*EvaluateFlagResponse, error)
+	ExplainFlag(ctx context.Context, in *ExplainFlagRequest, opts ...grpc.CallOption) (*ExplainFlagResponse, error)
+	EvaluateAllFlags(ctx context.Context, in *EvaluateAllFlagsRequest, opts ...grpc.CallOption) (*EvaluateAllFlagsResponse, error)
+}
+
+type flagServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlagServiceClient(cc grpc.ClientConnInterface) FlagServiceClient {
+	return &flagServiceClient{cc}
+}
+
+func (c *flagServiceClient) CreateFlag(ctx context.Context, in *CreateFlagRequest, opts ...grpc.CallOption) (*CreateFlagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateFlagResponse)
+	err := c.cc.Invoke(ctx, FlagService_CreateFlag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) GetFlag(ctx context.Context, in *GetFlagRequest, opts ...grpc.CallOption) (*GetFlagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFlagResponse)
+	err := c.cc.Invoke(ctx, FlagService_GetFlag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) ListFlags(ctx context.Context, in *ListFlagsRequest, opts ...grpc.CallOption) (*ListFlagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFlagsResponse)
+	err := c.cc.Invoke(ctx, FlagService_ListFlags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) ToggleFlag(ctx context.Context, in *ToggleFlagRequest, opts ...grpc.CallOption) (*ToggleFlagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ToggleFlagResponse)
+	err := c.cc.Invoke(ctx, FlagService_ToggleFlag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) UpdateFlagDependencies(ctx context.Context, in *UpdateFlagDependenciesRequest, opts ...grpc.CallOption) (*UpdateFlagDependenciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateFlagDependenciesResponse)
+	err := c.cc.Invoke(ctx, FlagService_UpdateFlagDependencies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) GetFlagLogs(ctx context.Context, in *GetFlagLogsRequest, opts ...grpc.CallOption) (*GetFlagLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFlagLogsResponse)
+	err := c.cc.Invoke(ctx, FlagService_GetFlagLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) EvaluateFlag(ctx context.Context, in *EvaluateFlagRequest, opts ...grpc.CallOption) (*EvaluateFlagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluateFlagResponse)
+	err := c.cc.Invoke(ctx, FlagService_EvaluateFlag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) ExplainFlag(ctx context.Context, in *ExplainFlagRequest, opts ...grpc.CallOption) (*ExplainFlagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainFlagResponse)
+	err := c.cc.Invoke(ctx, FlagService_ExplainFlag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flagServiceClient) EvaluateAllFlags(ctx context.Context, in *EvaluateAllFlagsRequest, opts ...grpc.CallOption) (*EvaluateAllFlagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluateAllFlagsResponse)
+	err := c.cc.Invoke(ctx, FlagService_EvaluateAllFlags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FlagServiceServer is the server API for FlagService service.
+// All implementations must embed UnimplementedFlagServiceServer
+// for forward compatibility.
+//
+// FlagService exposes feature flag management and evaluation over gRPC. It is
+// backed by the same service layer as the REST API.
+type FlagServiceServer interface {
+	CreateFlag(context.Context, *CreateFlagRequest) (*CreateFlagResponse, error)
+	GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error)
+	ListFlags(context.Context, *ListFlagsRequest) (*ListFlagsResponse, error)
+	ToggleFlag(context.Context, *ToggleFlagRequest) (*ToggleFlagResponse, error)
+	UpdateFlagDependencies(context.Context, *UpdateFlagDependenciesRequest) (*UpdateFlagDependenciesResponse, error)
+	GetFlagLogs(context.Context, *GetFlagLogsRequest) (*GetFlagLogsResponse, error)
+	EvaluateFlag(context.Context, *EvaluateFlagRequest) (*EvaluateFlagResponse, error)
+	ExplainFlag(context.Context, *ExplainFlagRequest) (*ExplainFlagResponse, error)
+	EvaluateAllFlags(context.Context, *EvaluateAllFlagsRequest) (*EvaluateAllFlagsResponse, error)
+	mustEmbedUnimplementedFlagServiceServer()
+}
+
+// UnimplementedFlagServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlagServiceServer struct{}
+
+func (UnimplementedFlagServiceServer) CreateFlag(context.Context, *CreateFlagRequest) (*CreateFlagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateFlag not implemented")
+}
+func (UnimplementedFlagServiceServer) GetFlag(context.Context, *GetFlagRequest) (*GetFlagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFlag not implemented")
+}
+func (UnimplementedFlagServiceServer) ListFlags(context.Context, *ListFlagsRequest) (*ListFlagsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFlags not implemented")
+}
+func (UnimplementedFlagServiceServer) ToggleFlag(context.Context, *ToggleFlagRequest) (*ToggleFlagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ToggleFlag not implemented")
+}
+func (UnimplementedFlagServiceServer) UpdateFlagDependencies(context.Context, *UpdateFlagDependenciesRequest) (*UpdateFlagDependenciesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateFlagDependencies not implemented")
+}
+func (UnimplementedFlagServiceServer) GetFlagLogs(context.Context, *GetFlagLogsRequest) (*GetFlagLogsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFlagLogs not implemented")
+}
+func (UnimplementedFlagServiceServer) EvaluateFlag(context.Context, *EvaluateFlagRequest) (*EvaluateFlagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EvaluateFlag not implemented")
+}
+func (UnimplementedFlagServiceServer) ExplainFlag(context.Context, *ExplainFlagRequest) (*ExplainFlagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExplainFlag not implemented")
+}
+func (UnimplementedFlagServiceServer) EvaluateAllFlags(context.Context, *EvaluateAllFlagsRequest) (*EvaluateAllFlagsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EvaluateAllFlags not implemented")
+}
+func (UnimplementedFlagServiceServer) mustEmbedUnimplementedFlagServiceServer() {}
+func (UnimplementedFlagServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeFlagServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlagServiceServer will
+// result in compilation errors.
+type UnsafeFlagServiceServer interface {
+	mustEmbedUnimplementedFlagServiceServer()
+}
+
+func RegisterFlagServiceServer(s grpc.ServiceRegistrar, srv FlagServiceServer) {
+	// If the following call panics, it indicates UnimplementedFlagServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FlagService_ServiceDesc, srv)
+}
+
+func _FlagService_CreateFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).CreateFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_CreateFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).CreateFlag(ctx, req.(*CreateFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_GetFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).GetFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_GetFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).GetFlag(ctx, req.(*GetFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_ListFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).ListFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_ListFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).ListFlags(ctx, req.(*ListFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_ToggleFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ToggleFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).ToggleFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_ToggleFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).ToggleFlag(ctx, req.(*ToggleFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_UpdateFlagDependencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFlagDependenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).UpdateFlagDependencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_UpdateFlagDependencies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).UpdateFlagDependencies(ctx, req.(*UpdateFlagDependenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_GetFlagLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlagLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).GetFlagLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_GetFlagLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).GetFlagLogs(ctx, req.(*GetFlagLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_EvaluateFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).EvaluateFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_EvaluateFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).EvaluateFlag(ctx, req.(*EvaluateFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_ExplainFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExplainFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).ExplainFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_ExplainFlag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).ExplainFlag(ctx, req.(*ExplainFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlagService_EvaluateAllFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateAllFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlagServiceServer).EvaluateAllFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlagService_EvaluateAllFlags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlagServiceServer).EvaluateAllFlags(ctx, req.(*EvaluateAllFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FlagService_ServiceDesc is the grpc.ServiceDesc for FlagService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlagService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "domcobb.v1.FlagService",
+	HandlerType: (*FlagServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateFlag",
+			Handler:    _FlagService_CreateFlag_Handler,
+		},
+		{
+			MethodName: "GetFlag",
+			Handler:    _FlagService_GetFlag_Handler,
+		},
+		{
+			MethodName: "ListFlags",
+			Handler:    _FlagService_ListFlags_Handler,
+		},
+		{
+			MethodName: "ToggleFlag",
+			Handler:    _FlagService_ToggleFlag_Handler,
+		},
+		{
+			MethodName: "UpdateFlagDependencies",
+			Handler:    _FlagService_UpdateFlagDependencies_Handler,
+		},
+		{
+			MethodName: "GetFlagLogs",
+			Handler:    _FlagService_GetFlagLogs_Handler,
+		},
+		{
+			MethodName: "EvaluateFlag",
+			Handler:    _FlagService_EvaluateFlag_Handler,
+		},
+		{
+			MethodName: "ExplainFlag",
+			Handler:    _FlagService_ExplainFlag_Handler,
+		},
+		{
+			MethodName: "EvaluateAllFlags",
+			Handler:    _FlagService_EvaluateAllFlags_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "domcobb/v1/flags.proto",
+}
@@ -0,0 +1,25 @@
+package snapshot
+
+import (
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+)
+
+const AlgorithmEd25519 = "ed25519"
+
+// Snapshot is a self-contained copy of the flag configuration at a revision.
+type Snapshot struct {
+	Revision  uint64             `json:"revision"`
+	CreatedAt time.Time          `json:"created_at"`
+	Config    *evaluation.Config `json:"config"`
+}
+
+// File is the signed form of a snapshot. Payload holds the base64 encoded
+// snapshot JSON exactly as it was signed, so re-encoding the file can never
+// break the signature.
+type File struct {
+	Algorithm string `json:"algorithm"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
@@ -0,0 +1,93 @@
+package snapshot
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidSignature     = errors.New("snapshot signature is invalid")
+	ErrUnsupportedAlgorithm = errors.New("snapshot signature algorithm is not supported")
+)
+
+func Sign(snapshot *Snapshot, key ed25519.PrivateKey) (*File, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key size %d", len(key))
+	}
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Algorithm: AlgorithmEd25519,
+		Payload:   base64.StdEncoding.EncodeToString(payload),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, payload)),
+	}, nil
+}
+
+func Verify(file *File, key ed25519.PublicKey) (*Snapshot, error) {
+	if file.Algorithm != AlgorithmEd25519 {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, file.Algorithm)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key size %d", len(key))
+	}
+	payload, err := base64.StdEncoding.DecodeString(file.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot payload: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(file.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot signature: %w", err)
+	}
+	if !ed25519.Verify(key, payload, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(payload, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// Open parses a snapshot file and returns its content only if the signature
+// matches the given public key.
+func Open(data []byte, key ed25519.PublicKey) (*Snapshot, error) {
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot file: %w", err)
+	}
+	return Verify(&file, key)
+}
+
+// ParsePrivateKey accepts a base64 encoded 32 byte seed or 64 byte key.
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	default:
+		return nil, fmt.Errorf("invalid ed25519 private key size %d", len(key))
+	}
+}
+
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key size %d", len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
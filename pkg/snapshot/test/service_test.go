@@ -0,0 +1,114 @@
+package snapshot_test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/evaluation"
+	"github.com/ArshiAbolghasemi/dom-cobb/pkg/snapshot"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Snapshot", func() {
+	var (
+		publicKey  ed25519.PublicKey
+		privateKey ed25519.PrivateKey
+		original   *snapshot.Snapshot
+	)
+
+	BeforeEach(func() {
+		var err error
+		publicKey, privateKey, err = ed25519.GenerateKey(nil)
+		Expect(err).To(BeNil())
+
+		original = &snapshot.Snapshot{
+			Revision:  42,
+			CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Config: &evaluation.Config{
+				Flags: []*evaluation.Flag{
+					{ID: 1, Name: "checkout", Active: true, Dependencies: []uint{}, Allow: []string{"user-1"}},
+					{ID: 2, Name: "new-cart", Active: false, Dependencies: []uint{1}},
+				},
+			},
+		}
+	})
+
+	It("should open a file it signed", func() {
+		file, err := snapshot.Sign(original, privateKey)
+		Expect(err).To(BeNil())
+		data, err := json.Marshal(file)
+		Expect(err).To(BeNil())
+
+		opened, err := snapshot.Open(data, publicKey)
+
+		Expect(err).To(BeNil())
+		Expect(opened).To(Equal(original))
+	})
+
+	It("should reject a tampered payload", func() {
+		file, err := snapshot.Sign(original, privateKey)
+		Expect(err).To(BeNil())
+
+		original.Config.Flags[1].Active = true
+		tampered, err := snapshot.Sign(original, privateKey)
+		Expect(err).To(BeNil())
+		file.Payload = tampered.Payload
+
+		_, err = snapshot.Verify(file, publicKey)
+		Expect(err).To(MatchError(snapshot.ErrInvalidSignature))
+	})
+
+	It("should reject a file signed with another key", func() {
+		_, otherKey, err := ed25519.GenerateKey(nil)
+		Expect(err).To(BeNil())
+		file, err := snapshot.Sign(original, otherKey)
+		Expect(err).To(BeNil())
+
+		_, err = snapshot.Verify(file, publicKey)
+		Expect(err).To(MatchError(snapshot.ErrInvalidSignature))
+	})
+
+	It("should reject keys of the wrong size", func() {
+		_, err := snapshot.Sign(original, privateKey[:16])
+		Expect(err).To(MatchError(ContainSubstring("invalid ed25519 private key size 16")))
+
+		file, err := snapshot.Sign(original, privateKey)
+		Expect(err).To(BeNil())
+
+		_, err = snapshot.Verify(file, publicKey[:16])
+		Expect(err).To(MatchError(ContainSubstring("invalid ed25519 public key size 16")))
+	})
+
+	It("should reject unsupported algorithms", func() {
+		file, err := snapshot.Sign(original, privateKey)
+		Expect(err).To(BeNil())
+		file.Algorithm = "none"
+
+		_, err = snapshot.Verify(file, publicKey)
+		Expect(err).To(MatchError(snapshot.ErrUnsupportedAlgorithm))
+	})
+
+	Describe("Keys", func() {
+		It("should parse private keys from a seed or a full key", func() {
+			fromSeed, err := snapshot.ParsePrivateKey(base64.StdEncoding.EncodeToString(privateKey.Seed()))
+			Expect(err).To(BeNil())
+			Expect(fromSeed).To(Equal(privateKey))
+
+			fromKey, err := snapshot.ParsePrivateKey(base64.StdEncoding.EncodeToString(privateKey))
+			Expect(err).To(BeNil())
+			Expect(fromKey).To(Equal(privateKey))
+		})
+
+		It("should parse public keys", func() {
+			key, err := snapshot.ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey))
+			Expect(err).To(BeNil())
+			Expect(key).To(Equal(publicKey))
+
+			_, err = snapshot.ParsePublicKey(base64.StdEncoding.EncodeToString([]byte("short")))
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})